@@ -0,0 +1,83 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package main
+
+import (
+	"context"
+	"time"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/archive"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+// exportAction writes the local chain into an archive file.
+func exportAction(ctx *cli.Context) error {
+	if err := cfg.Load("dusk", nil, nil); err != nil {
+		return err
+	}
+
+	drvr, db := heavy.CreateDBConnection()
+
+	defer func() {
+		_ = drvr.Close()
+	}()
+
+	file := ctx.String(ArchiveFileFlag.Name)
+	log.WithField("file", file).Info("exporting chain")
+
+	return archive.Export(db, file, protocol.MagicFromConfig(), ctx.Uint64(FromHeightFlag.Name), ctx.Uint64(ToHeightFlag.Name), logProgress())
+}
+
+// importAction appends the blocks of an archive file to the local chain.
+// Rusk is required in order to execute the state transitions.
+func importAction(ctx *cli.Context) error {
+	if err := cfg.Load("dusk", nil, nil); err != nil {
+		return err
+	}
+
+	drvr, db := heavy.CreateDBConnection()
+
+	defer func() {
+		_ = drvr.Close()
+	}()
+
+	gctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Get().RPC.Rusk.ConnectionTimeout)*time.Millisecond)
+	defer cancel()
+
+	proxy, ruskConn := setupGRPCClients(gctx)
+
+	defer func() {
+		_ = ruskConn.Close()
+	}()
+
+	file := ctx.String(ArchiveFileFlag.Name)
+	log.WithField("file", file).Info("importing chain")
+
+	return archive.Import(context.Background(), db, cfg.DecodeGenesis(), proxy.Executor(), file, protocol.MagicFromConfig(), logProgress())
+}
+
+// logProgress returns an archive.ProgressFunc logging each completed percent.
+func logProgress() archive.ProgressFunc {
+	last := -1
+
+	return func(height uint64, percent float64) {
+		if int(percent) == last {
+			return
+		}
+
+		last = int(percent)
+
+		log.WithFields(logrus.Fields{
+			"height":   height,
+			"progress": last,
+		}).Info("archive progress")
+	}
+}
@@ -0,0 +1,76 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package main
+
+import (
+	"encoding/hex"
+	"os"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/chain"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
+	"github.com/urfave/cli"
+)
+
+// checkpointAction writes the provisioners snapshots persisted up to the
+// checkpoint height into a file, and prints the checkpoint to be configured
+// in the [sync.checkpoint] section of the nodes to fast sync.
+func checkpointAction(ctx *cli.Context) error {
+	if err := cfg.Load("dusk", nil, nil); err != nil {
+		return err
+	}
+
+	drvr, db := heavy.CreateDBConnection()
+
+	defer func() {
+		_ = drvr.Close()
+	}()
+
+	l := chain.NewDBLoader(db, cfg.DecodeGenesis())
+
+	height := ctx.Uint64(ToHeightFlag.Name)
+	if height == 0 {
+		tip, err := l.Height()
+		if err != nil {
+			return err
+		}
+
+		height = tip
+	}
+
+	blk, err := l.BlockAt(height)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := l.ProvisionersSnapshots(height)
+	if err != nil {
+		return err
+	}
+
+	file := ctx.String(SnapshotsFileFlag.Name)
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if err := chain.WriteProvisionersSnapshots(f, snapshots); err != nil {
+		return err
+	}
+
+	return printReport(struct {
+		Height       uint64 `json:"height"`
+		Hash         string `json:"hash"`
+		Provisioners string `json:"provisioners"`
+		Snapshots    int    `json:"snapshots"`
+	}{height, hex.EncodeToString(blk.Header.Hash), file, len(snapshots)})
+}
@@ -26,6 +26,39 @@ var (
 		Name:  "datadir",
 		Usage: "Data directory for the node",
 	}
+	// ArchiveFileFlag flag to set the chain archive file.
+	ArchiveFileFlag = cli.StringFlag{
+		Name:  "file",
+		Usage: "chain archive file",
+		Value: "chain.dat",
+	}
+	// FromHeightFlag flag to set the first block height of a range.
+	FromHeightFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "first block height",
+	}
+	// ToHeightFlag flag to set the last block height of a range.
+	ToHeightFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "last block height (0 for the chain tip)",
+	}
+	// WorkersFlag flag to set the amount of parallel verification workers.
+	WorkersFlag = cli.IntFlag{
+		Name:  "workers",
+		Usage: "amount of parallel verification workers",
+		Value: 4,
+	}
+	// RepairFlag flag to enable the repair of the derived database indexes.
+	RepairFlag = cli.BoolFlag{
+		Name:  "repair",
+		Usage: "repair the derived database indexes",
+	}
+	// SnapshotsFileFlag flag to set the provisioners snapshots file.
+	SnapshotsFileFlag = cli.StringFlag{
+		Name:  "file",
+		Usage: "provisioners snapshots file",
+		Value: "provisioners.dat",
+	}
 )
 
 var (
@@ -41,6 +41,36 @@ func init() {
 			Usage:   "serializes the genesis block and prints it",
 			Action:  genesis.Action,
 		},
+		{
+			Name:   "export",
+			Usage:  "exports the chain into a portable archive file",
+			Action: exportAction,
+			Flags:  []cli.Flag{ArchiveFileFlag, FromHeightFlag, ToHeightFlag},
+		},
+		{
+			Name:   "import",
+			Usage:  "imports the chain from a portable archive file",
+			Action: importAction,
+			Flags:  []cli.Flag{ArchiveFileFlag},
+		},
+		{
+			Name:   "verifycerts",
+			Usage:  "re-verifies the certificates of the stored blocks",
+			Action: verifyCertificatesAction,
+			Flags:  []cli.Flag{FromHeightFlag, ToHeightFlag, WorkersFlag},
+		},
+		{
+			Name:   "checkdb",
+			Usage:  "checks the integrity of the whole database",
+			Action: checkDBAction,
+			Flags:  []cli.Flag{RepairFlag},
+		},
+		{
+			Name:   "checkpoint",
+			Usage:  "exports a fast sync checkpoint and its provisioners snapshots",
+			Action: checkpointAction,
+			Flags:  []cli.Flag{ToHeightFlag, SnapshotsFileFlag},
+		},
 	}
 	app.Flags = append(app.Flags, CLIFlags...)
 	app.Flags = append(app.Flags, GlobalFlags...)
@@ -20,12 +20,15 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/chain"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/bidautomaton"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/equivocation"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/journal"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/stakeautomaton"
 	walletdb "github.com/dusk-network/dusk-blockchain/pkg/core/data/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/keys"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/wallet"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	_ "github.com/dusk-network/dusk-blockchain/pkg/core/database/bbolt"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/loop"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
@@ -56,6 +59,7 @@ type Server struct {
 	ruskConn      *grpc.ClientConn
 	readerFactory *peer.ReaderFactory
 	kadPeer       *kadcast.Peer
+	journal       *journal.Journal
 }
 
 // LaunchChain instantiates a chain.Loader, does the wire up to create a Chain
@@ -213,17 +217,50 @@ func Setup() *Server {
 		Keys:        w.Keys(),
 		Proxy:       proxy,
 		TimerLength: cfg.ConsensusTimeOut,
+		Timeouts:    consensus.NewTimeouts(cfg.ConsensusTimeOut),
+	}
+
+	if err = e.Timeouts.Serve(ctx, rpcBus); err != nil {
+		log.Panic(err)
+	}
+
+	if conf := cfg.Get().Consensus.Journal; conf.Enabled {
+		if e.Journal, err = journal.Open(conf.Dir, conf.MaxFileSize, conf.MaxFiles); err != nil {
+			log.Panic(err)
+		}
 	}
 
 	cl := loop.New(e, &w.PublicKey)
 	processor.Register(topics.Candidate, cl.ProcessCandidate)
 
+	if err = cl.ServeStats(ctx, rpcBus); err != nil {
+		log.Panic(err)
+	}
+
 	c, err := LaunchChain(ctx, cl, proxy, eventBus, grpcServer, db)
 	if err != nil {
 		log.Panic(err)
 	}
 
 	processor.Register(topics.Block, c.ProcessBlockFromNetwork)
+	processor.Register(topics.Headers, c.ProcessHeadersFromNetwork)
+
+	// Watch the consensus messages for equivocations. The evidence is only
+	// served on the API, as Rusk does not support slashing yet
+	detector := equivocation.NewDetector()
+	detector.Run(ctx, eventBus, c.Provisioners)
+
+	if err = detector.Serve(ctx, rpcBus); err != nil {
+		log.Panic(err)
+	}
+
+	if err = c.ServeSyncStatus(ctx, rpcBus); err != nil {
+		log.Panic(err)
+	}
+
+	if err = c.ServeCommittee(ctx, rpcBus); err != nil {
+		log.Panic(err)
+	}
 
 	// Instantiate GraphQL server
 	if cfg.Get().Gql.Enabled {
@@ -254,6 +291,10 @@ func Setup() *Server {
 		panic("could not contact any voucher seeders")
 	}
 
+	c.EnableParallelSync(connector)
+	c.SetPeerReporter(connector)
+	peer.RegisterReputationServer(grpcServer, connector)
+
 	// creating the Server
 	srv := &Server{
 		eventBus:      eventBus,
@@ -263,6 +304,7 @@ func Setup() *Server {
 		grpcServer:    grpcServer,
 		ruskConn:      ruskConn,
 		readerFactory: readerFactory,
+		journal:       e.Journal,
 	}
 
 	// Setting up the transactor component
@@ -314,6 +356,8 @@ func (s *Server) Close() {
 	if s.kadPeer != nil {
 		s.kadPeer.Close()
 	}
+
+	_ = s.journal.Close()
 }
 
 func registerPeerServices(processor *peer.MessageProcessor, db database.DB, eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus) {
@@ -330,6 +374,7 @@ func registerPeerServices(processor *peer.MessageProcessor, db database.DB, even
 	processor.Register(topics.Pong, responding.ProcessPong)
 	processor.Register(topics.Inv, dataRequestor.RequestMissingItems)
 	processor.Register(topics.GetBlocks, bhb.AdvertiseMissingBlocks)
+	processor.Register(topics.GetHeaders, bhb.ProvideHeaders)
 	processor.Register(topics.GetCandidate, cb.ProvideCandidate)
 	processor.Register(topics.Score, cp.Process)
 	processor.Register(topics.Reduction, cp.Process)
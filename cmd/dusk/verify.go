@@ -0,0 +1,134 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/chain"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+// verifyCertificatesAction re-verifies the certificates of the stored blocks
+// and prints a JSON report of the failures on stdout. It does not require
+// Rusk, as the provisioners are restored from the database snapshots.
+func verifyCertificatesAction(ctx *cli.Context) error {
+	if err := cfg.Load("dusk", nil, nil); err != nil {
+		return err
+	}
+
+	drvr, db := heavy.CreateDBConnection()
+
+	defer func() {
+		_ = drvr.Close()
+	}()
+
+	l := chain.NewDBLoader(db, cfg.DecodeGenesis())
+
+	to := ctx.Uint64(ToHeightFlag.Name)
+	if to == 0 {
+		tip, err := l.Height()
+		if err != nil {
+			return err
+		}
+
+		to = tip
+	}
+
+	from := ctx.Uint64(FromHeightFlag.Name)
+
+	log.WithFields(logrus.Fields{
+		"from": from,
+		"to":   to,
+	}).Info("verifying block certificates")
+
+	report, err := l.VerifyCertificates(context.Background(), from, to, ctx.Int(WorkersFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	for _, f := range report.Failures {
+		log.WithFields(logrus.Fields{
+			"height": f.Height,
+			"hash":   f.Hash,
+			"reason": f.Reason,
+			"step":   f.Step,
+		}).WithError(errors.New(f.Error)).Error("certificate verification failed")
+	}
+
+	if err := printReport(report); err != nil {
+		return err
+	}
+
+	if len(report.Failures) > 0 {
+		return fmt.Errorf("%d of %d certificates failed verification", len(report.Failures), report.Checked)
+	}
+
+	return nil
+}
+
+// checkDBAction walks the whole database, and prints a JSON report of the
+// inconsistencies found on stdout. If requested, the derived indexes are
+// repaired.
+func checkDBAction(ctx *cli.Context) error {
+	if err := cfg.Load("dusk", nil, nil); err != nil {
+		return err
+	}
+
+	drvr, db := heavy.CreateDBConnection()
+
+	defer func() {
+		_ = drvr.Close()
+	}()
+
+	if drvr.Name() != heavy.DriverName {
+		return fmt.Errorf("checkdb is not supported by the %s driver", drvr.Name())
+	}
+
+	repair := ctx.Bool(RepairFlag.Name)
+	log.WithField("repair", repair).Info("checking database integrity")
+
+	report, err := heavy.CheckIntegrity(db, repair)
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range report.Issues {
+		log.WithFields(logrus.Fields{
+			"kind":     issue.Kind,
+			"height":   issue.Height,
+			"key":      issue.Key,
+			"repaired": issue.Repaired,
+		}).Warn(issue.Detail)
+	}
+
+	if err := printReport(report); err != nil {
+		return err
+	}
+
+	for _, issue := range report.Issues {
+		if !issue.Repaired {
+			return fmt.Errorf("%d database issues found", len(report.Issues))
+		}
+	}
+
+	return nil
+}
+
+func printReport(report interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(report)
+}
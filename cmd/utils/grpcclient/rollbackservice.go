@@ -0,0 +1,46 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package grpcclient
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/chain"
+	log "github.com/sirupsen/logrus"
+)
+
+// TryRollback sets the chain tip of a node back to the block at height,
+// through the node.Rollback grpc service.
+func TryRollback(address string, height uint64, timeout int) error {
+	// Add UNIX prefix in case we're using unix sockets.
+	if strings.Contains(address, ".sock") {
+		address = "unix://" + address
+	}
+
+	c := grpcClient{dialTimeout: 5}
+	if err := c.TryConnect(address); err != nil {
+		return err
+	}
+
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	resp, err := chain.Rollback(ctx, c.conn, height)
+	if err != nil {
+		return err
+	}
+
+	log.WithField("height", resp.Height).
+		WithField("hash", resp.Hash).
+		Info("chain rolled back")
+
+	return nil
+}
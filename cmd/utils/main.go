@@ -10,9 +10,11 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/dusk-network/dusk-blockchain/cmd/utils/grpcclient"
 	"github.com/dusk-network/dusk-blockchain/cmd/utils/mock"
+	"github.com/dusk-network/dusk-blockchain/cmd/utils/replay"
 	"github.com/dusk-network/dusk-blockchain/cmd/utils/tps"
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/logging"
@@ -38,6 +40,8 @@ func main() {
 		setConfigCMD,
 		tpsCMD,
 		automateCMD,
+		replayCMD,
+		rollbackCMD,
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -175,6 +179,40 @@ var (
 		Value: 5,
 	}
 
+	journalFlag = cli.StringFlag{
+		Name:  "journal",
+		Usage: "consensus journal file or directory , eg: --journal=./journal",
+		Value: "journal",
+	}
+
+	speedFlag = cli.Float64Flag{
+		Name:  "speed",
+		Usage: "replay speed factor, eg: --speed=10",
+		Value: 1,
+	}
+
+	consensusTimeoutFlag = cli.IntFlag{
+		Name:  "consensustimeout",
+		Usage: "consensus step timeout of the recording node in seconds, eg: --consensustimeout=5",
+		Value: 5,
+	}
+
+	heightFlag = cli.Uint64Flag{
+		Name:  "height",
+		Usage: "height of the block to set the chain tip to, eg: --height=1000",
+	}
+
+	rollbackTimeoutFlag = cli.IntFlag{
+		Name:  "rollbacktimeout",
+		Usage: "timeout for the rollback request in seconds",
+		Value: 60,
+	}
+
+	verboseFlag = cli.BoolFlag{
+		Name:  "verbose",
+		Usage: "print the phase transitions and the timeouts of every round",
+	}
+
 	metricsCMD = cli.Command{
 		Name:      "metrics",
 		Usage:     "expose a metrics endpoint",
@@ -273,6 +311,37 @@ var (
 		},
 		Description: `Automate consensus participation of a node until the process exits`,
 	}
+
+	// replay command
+	// Example ./bin/utils replay --journal=/tmp/dusk-node/journal --speed=10 --verbose.
+	replayCMD = cli.Command{
+		Name:      "replay",
+		Usage:     "replay a consensus journal offline",
+		Action:    replayAction,
+		ArgsUsage: "",
+		Flags: []cli.Flag{
+			journalFlag,
+			speedFlag,
+			consensusTimeoutFlag,
+			verboseFlag,
+		},
+		Description: `Feed the rounds recorded in a consensus journal through a fresh consensus state machine, and compare the outcomes`,
+	}
+
+	// rollback command
+	// Example ./bin/utils rollback --grpcaddr="unix:///tmp/dusk-node/dusk-grpc.sock" --height=1000.
+	rollbackCMD = cli.Command{
+		Name:      "rollback",
+		Usage:     "set the chain tip of a node back to a block",
+		Action:    rollbackAction,
+		ArgsUsage: "",
+		Flags: []cli.Flag{
+			grpcAddressFlag,
+			heightFlag,
+			rollbackTimeoutFlag,
+		},
+		Description: `Remove all blocks above the given height from a running node. Rusk must be restored at that height beforehand, as its state is not rolled back`,
+	}
 )
 
 // metricsAction will expose the metrics endpoint.
@@ -380,3 +449,20 @@ func automateAction(ctx *cli.Context) error {
 	sendBidTimeout := ctx.Int(sendBidTimeoutFlag.Name)
 	return grpcclient.AutomateStakesAndBids(address, sendStakeTimeout, sendBidTimeout)
 }
+
+func replayAction(ctx *cli.Context) error {
+	path := ctx.String(journalFlag.Name)
+	speed := ctx.Float64(speedFlag.Name)
+	timeout := time.Duration(ctx.Int(consensusTimeoutFlag.Name)) * time.Second
+	verbose := ctx.Bool(verboseFlag.Name)
+
+	return replay.Run(path, speed, timeout, verbose, os.Stdout)
+}
+
+func rollbackAction(ctx *cli.Context) error {
+	address := ctx.String(grpcAddressFlag.Name)
+	height := ctx.Uint64(heightFlag.Name)
+	timeout := ctx.Int(rollbackTimeoutFlag.Name)
+
+	return grpcclient.TryRollback(address, height, timeout)
+}
@@ -227,6 +227,29 @@ func pendingTransactionCount(duskInfo *DuskInfo) (int, error) {
 	return count, nil
 }
 
+func getConsensusQueues(duskInfo *DuskInfo) (*ConsensusQueues, error) {
+	//nolint:gosec
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/consensus/queues", duskInfo.NodeAPIPort))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("consensus queues: %s", resp.Status)
+	}
+
+	queues := new(ConsensusQueues)
+	if err := json.NewDecoder(resp.Body).Decode(queues); err != nil {
+		return nil, err
+	}
+
+	return queues, nil
+}
+
 //nolint
 func getTransactionByID(client *graphql.Client, values map[string]interface{}) (interface{}, error) {
 	query := `
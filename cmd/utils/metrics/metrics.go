@@ -30,6 +30,7 @@ var (
 	node               *engine.DuskNode
 	pendingTx          int
 	currentBlockNumber uint64
+	consensusQueues    *ConsensusQueues
 )
 
 // DuskInfo is the placeholder for exporter metrics.
@@ -87,6 +88,11 @@ func Routine() {
 			fmt.Printf("ERROR: pendingTransactionCount: %+v\n", err)
 		}
 
+		consensusQueues, err = getConsensusQueues(duskInfo)
+		if err != nil {
+			fmt.Printf("ERROR: getConsensusQueues: %+v\n", err)
+		}
+
 		// newBlock, err := getBlockByNumber(duskInfo, map[string]interface{}{"height": currentBlockNumber + 1})
 		newBlock, err := getLatestBlock(duskInfo, currentBlockNumber+1)
 		if err != nil {
@@ -178,6 +184,19 @@ func HandlerMetrics(w http.ResponseWriter, r *http.Request) {
 	allOut = append(allOut, fmt.Sprintf("dusk_transfers %v", duskInfo.DuskTransfers))
 	allOut = append(allOut, fmt.Sprintf("dusk_load_time %0.4f", duskInfo.LoadTime))
 
+	if q := consensusQueues; q != nil {
+		allOut = append(allOut, fmt.Sprintf("dusk_consensus_agreement_chan_len %d", q.AgreementChan.Len))
+		allOut = append(allOut, fmt.Sprintf("dusk_consensus_agreement_chan_dropped %d", q.AgreementChan.Dropped))
+		allOut = append(allOut, fmt.Sprintf("dusk_consensus_event_chan_len %d", q.EventChan.Len))
+		allOut = append(allOut, fmt.Sprintf("dusk_consensus_event_chan_dropped %d", q.EventChan.Dropped))
+		allOut = append(allOut, fmt.Sprintf("dusk_consensus_event_queue_size %d", q.EventQueue.Size))
+		allOut = append(allOut, fmt.Sprintf("dusk_consensus_event_queue_dropped %d", q.EventQueue.Dropped))
+		allOut = append(allOut, fmt.Sprintf("dusk_consensus_event_queue_oldest_round %d", q.EventQueue.OldestRound))
+		allOut = append(allOut, fmt.Sprintf("dusk_consensus_round_queue_size %d", q.RoundQueue.Size))
+		allOut = append(allOut, fmt.Sprintf("dusk_consensus_round_queue_dropped %d", q.RoundQueue.Dropped))
+		allOut = append(allOut, fmt.Sprintf("dusk_consensus_round_queue_oldest_round %d", q.RoundQueue.OldestRound))
+	}
+
 	_, _ = fmt.Fprintln(w, strings.Join(allOut, "\n"))
 }
 
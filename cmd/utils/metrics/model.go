@@ -39,3 +39,27 @@ type Header struct {
 	//*Certificate `json:"certificate"` // Block certificate
 	Hash []byte `json:"hash"` // Hash of all previous fields
 }
+
+// ConsensusQueues is the occupation of the consensus channels and queues, as
+// reported by the node API.
+type ConsensusQueues struct {
+	AgreementChan ConsensusChan  `json:"agreementChan"`
+	EventChan     ConsensusChan  `json:"eventChan"`
+	EventQueue    ConsensusQueue `json:"eventQueue"`
+	RoundQueue    ConsensusQueue `json:"roundQueue"`
+}
+
+// ConsensusChan is the occupation of a consensus channel.
+type ConsensusChan struct {
+	Len     int    `json:"len"`
+	Cap     int    `json:"cap"`
+	Dropped uint64 `json:"dropped"`
+}
+
+// ConsensusQueue is the occupation of a consensus queue.
+type ConsensusQueue struct {
+	Size        int    `json:"size"`
+	Rounds      int    `json:"rounds"`
+	OldestRound uint64 `json:"oldestRound"`
+	Dropped     uint64 `json:"dropped"`
+}
@@ -0,0 +1,297 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package replay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/journal"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/keys"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/loop"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+// round holds the entries recorded during a round.
+type round struct {
+	start    journal.Entry
+	messages []journal.Entry
+	// decisions are the phase transitions, the timeouts and the end of the
+	// round.
+	decisions []journal.Entry
+}
+
+// Run replays the consensus journal at path, which is either a journal file
+// or a journal directory. Each recorded round is run through a fresh
+// consensus state machine, fed with the recorded messages at their recorded
+// pace, accelerated by speed. The decisions of the replay are printed along
+// with the recorded ones. Rounds which do not end with the recorded winning
+// block are reported as mismatches.
+func Run(path string, speed float64, timeout time.Duration, verbose bool, out io.Writer) error {
+	entries, err := readJournal(path)
+	if err != nil {
+		return err
+	}
+
+	if speed <= 0 {
+		speed = 1
+	}
+
+	mismatches := 0
+
+	for _, r := range splitRounds(entries) {
+		replayed, err := replayRound(r, speed, timeout)
+		if err != nil {
+			return fmt.Errorf("round %d: %w", r.start.Round, err)
+		}
+
+		if !report(out, r, replayed, verbose) {
+			mismatches++
+		}
+	}
+
+	if mismatches > 0 {
+		return fmt.Errorf("%d rounds replayed with a different outcome", mismatches)
+	}
+
+	return nil
+}
+
+func readJournal(path string) ([]journal.Entry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return journal.ReadDir(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	return journal.Read(f)
+}
+
+// splitRounds groups the entries per round. The entries preceding the first
+// recorded round start are dropped, as the state of their round is unknown.
+func splitRounds(entries []journal.Entry) []*round {
+	rounds := make([]*round, 0)
+
+	var cur *round
+
+	for _, e := range entries {
+		if e.Kind == journal.RoundStart {
+			cur = &round{start: e}
+			rounds = append(rounds, cur)
+
+			continue
+		}
+
+		if cur == nil {
+			continue
+		}
+
+		if e.Kind == journal.MessageReceived {
+			// Scores cannot be verified offline, as it takes Rusk. Only the
+			// scores verified by the node are replayed
+			if e.Name == topics.Score.String() && e.Err != "" {
+				continue
+			}
+
+			cur.messages = append(cur.messages, e)
+			continue
+		}
+
+		if e.Round == cur.start.Round {
+			cur.decisions = append(cur.decisions, e)
+		}
+	}
+
+	return rounds
+}
+
+// replayRound runs a round through a fresh state machine, and returns the
+// decisions it took.
+func replayRound(r *round, speed float64, timeout time.Duration) ([]journal.Entry, error) {
+	seed, p, err := r.start.RoundState()
+	if err != nil {
+		return nil, err
+	}
+
+	ru := consensus.RoundUpdate{
+		Round:           r.start.Round,
+		P:               p,
+		Seed:            seed,
+		Hash:            r.start.Hash,
+		LastCertificate: block.EmptyCertificate(),
+	}
+
+	scaled := func(d time.Duration) time.Duration {
+		return time.Duration(float64(d) / speed)
+	}
+
+	// The replaying node is not a provisioner: it only observes the
+	// recorded messages
+	out := new(lockedBuffer)
+	e := consensus.MockEmitter(scaled(timeout), transactions.MockProxy{P: transactions.PermissiveProvisioner{}})
+	e.Journal = journal.New(out)
+
+	_, db := lite.CreateDBConnection()
+	l := loop.New(e, keys.NewPublicKey())
+
+	// candidates are not verified against the state, as it takes Rusk
+	scr, agr, err := l.CreateStateMachine(db, scaled(timeout), func(block.Block) error { return nil })
+	if err != nil {
+		return nil, err
+	}
+
+	// the round is given up after its recorded duration, plus a grace
+	// period of a couple of steps
+	end := r.start.Time
+	if len(r.decisions) > 0 {
+		end = r.decisions[len(r.decisions)-1].Time
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), scaled(end.Sub(r.start.Time)+2*timeout))
+	defer cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		l.Spin(ctx, scr, agr, ru)
+	}()
+
+	startTime := time.Now()
+
+	for _, entry := range r.messages {
+		m, err := entry.Message()
+		if err != nil {
+			return nil, err
+		}
+
+		delay := scaled(entry.Time.Sub(r.start.Time)) - time.Since(startTime)
+
+		select {
+		case <-time.After(delay):
+		case <-done:
+		}
+
+		e.EventBus.Publish(m.Category(), m)
+	}
+
+	<-done
+
+	if err := e.Journal.Close(); err != nil {
+		return nil, err
+	}
+
+	entries, err := journal.Read(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+
+	decisions := make([]journal.Entry, 0)
+
+	for _, entry := range entries {
+		switch entry.Kind {
+		case journal.PhaseStart, journal.Timeout, journal.RoundEnd:
+			decisions = append(decisions, entry)
+		}
+	}
+
+	return decisions, nil
+}
+
+// report prints the recorded and the replayed decisions of a round. It
+// returns false if the round did not end with the recorded winning block.
+func report(out io.Writer, r *round, replayed []journal.Entry, verbose bool) bool {
+	recordedEnd := lastEnd(r.decisions)
+	replayedEnd := lastEnd(replayed)
+
+	match := recordedEnd == nil || (replayedEnd != nil && bytes.Equal(recordedEnd.Hash, replayedEnd.Hash))
+
+	outcome := "match"
+	if !match {
+		outcome = "MISMATCH"
+	}
+
+	_, _ = fmt.Fprintf(out, "round %d: %d messages, recorded %s, replayed %s: %s\n",
+		r.start.Round, len(r.messages), describe(recordedEnd), describe(replayedEnd), outcome)
+
+	if verbose || !match {
+		_, _ = fmt.Fprintln(out, "  recorded:")
+
+		for _, e := range r.decisions {
+			_, _ = fmt.Fprintf(out, "    %s\n", e)
+		}
+
+		_, _ = fmt.Fprintln(out, "  replayed:")
+
+		for _, e := range replayed {
+			_, _ = fmt.Fprintf(out, "    %s\n", e)
+		}
+	}
+
+	return match
+}
+
+func lastEnd(entries []journal.Entry) *journal.Entry {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Kind == journal.RoundEnd {
+			return &entries[i]
+		}
+	}
+
+	return nil
+}
+
+func describe(e *journal.Entry) string {
+	switch {
+	case e == nil:
+		return "no outcome"
+	case e.Err != "":
+		return fmt.Sprintf("error %q", e.Err)
+	default:
+		return fmt.Sprintf("block %x at step %d", e.Hash, e.Step)
+	}
+}
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use, as the consensus
+// components record their events from several goroutines.
+type lockedBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
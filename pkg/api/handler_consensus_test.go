@@ -52,6 +52,26 @@ func TestConsensusAPISmokeTest(t *testing.T) {
 			name:      "Get event queue status",
 			Data:      `{}`,
 		},
+		{
+			targetURL: "/consensus/timeouts",
+			name:      "Get step timeouts",
+			Data:      `{}`,
+		},
+		{
+			targetURL: "/consensus/queues",
+			name:      "Get queues occupation",
+			Data:      `{}`,
+		},
+		{
+			targetURL: "/consensus/committee?round=1&step=2",
+			name:      "Get committee",
+			Data:      `{}`,
+		},
+		{
+			targetURL: "/consensus/equivocations",
+			name:      "Get equivocations",
+			Data:      `{}`,
+		},
 	}
 
 	testflight.WithServer(apiServer.Server.Handler, func(r *testflight.Requester) {
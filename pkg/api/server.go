@@ -117,6 +117,10 @@ func (s *Server) InitRouting() *pat.Router {
 	r.HandleFunc("/consensus/provisioners", capi.GetProvisionersHandler).Methods("GET")
 	r.HandleFunc("/consensus/roundinfo", capi.GetRoundInfoHandler).Methods("GET")
 	r.HandleFunc("/consensus/eventqueuestatus", capi.GetEventQueueStatusHandler).Methods("GET")
+	r.HandleFunc("/consensus/timeouts", capi.GetTimeoutsHandler).Methods("GET")
+	r.HandleFunc("/consensus/queues", capi.GetQueuesHandler).Methods("GET")
+	r.HandleFunc("/consensus/committee", capi.GetCommitteeHandler).Methods("GET")
+	r.HandleFunc("/consensus/equivocations", capi.GetEquivocationsHandler).Methods("GET")
 	r.HandleFunc("/p2p/logs", capi.GetP2PLogsHandler).Methods("GET")
 	r.HandleFunc("/p2p/count", capi.GetP2PCountHandler).Methods("GET")
 
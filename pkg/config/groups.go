@@ -24,6 +24,11 @@ type timeoutConfiguration struct {
 	TimeoutVerifyCandidateBlock int64
 	TimeoutSendStakeTX          int64
 	TimeoutGetMempoolTXs        int64
+	TimeoutGetSyncStatus        int64
+	TimeoutGetConsensusTimeouts int64
+	TimeoutGetConsensusQueues   int64
+	TimeoutGetCommittee         int64
+	TimeoutGetEquivocations     int64
 	TimeoutGetRoundResults      int64
 	TimeoutBrokerGetCandidate   int64
 	TimeoutReadWrite            int64
@@ -48,15 +53,24 @@ type logMonitorConfiguration struct {
 }
 
 type networkConfiguration struct {
-	Seeder  seedersConfiguration
-	Monitor monitorConfiguration
-	Port    string
+	Seeder     seedersConfiguration
+	Monitor    monitorConfiguration
+	Reputation reputationConfiguration
+	RateLimit  rateLimitConfiguration
+	AddrBook   addrBookConfiguration
+	Port       string
 
 	MaxDupeMapItems  uint32
 	MaxDupeMapExpire uint32
 
+	// MinimumConnections is the number of outbound connections to full
+	// nodes the node maintains.
 	MinimumConnections int
-	MaxConnections     int
+	// MaxConnections is the maximum number of inbound connections.
+	MaxConnections int
+
+	// BanDuration is the number of seconds a misbehaving peer is banned for.
+	BanDuration int64
 
 	ServiceFlag uint8
 }
@@ -82,6 +96,54 @@ type monitorConfiguration struct {
 	Enabled bool
 }
 
+// Peer scoring configs. Each misbehavior adds its penalty to the score of
+// the peer, and peers scoring above Threshold are banned.
+type reputationConfiguration struct {
+	Threshold int
+	// Decay is the score forgiven to each peer every minute.
+	Decay int
+	// BanList is the file the bans are persisted into.
+	BanList string
+
+	IllegalTopic     int
+	MalformedMessage int
+	InvalidBlock     int
+	InvalidTx        int
+	DuplicateMessage int
+	Timeout          int
+}
+
+// Per-peer rate limits of the incoming messages, in messages per second,
+// enforced with a token bucket per topic. Consensus topics, sync topics and
+// data topics have separate budgets. A zero rate disables the limit.
+type rateLimitConfiguration struct {
+	ConsensusRate  float64
+	ConsensusBurst int
+	SyncRate       float64
+	SyncBurst      int
+	DataRate       float64
+	DataBurst      int
+}
+
+// Address book configs. The addresses of the full nodes learned from the
+// network are dialed to maintain the minimum outbound connections.
+type addrBookConfiguration struct {
+	// File is the file the address book is persisted into.
+	File         string
+	MaxAddresses int
+	// MaxAddressesPerSource is the maximum number of addresses learned from
+	// a single peer host.
+	MaxAddressesPerSource int
+	// DialInterval is the number of seconds between two checks of the
+	// outbound connections count.
+	DialInterval int64
+	// MinBackoff and MaxBackoff bound the number of seconds before an
+	// address which could not be dialed is retried. The backoff doubles on
+	// each consecutive failure.
+	MinBackoff int64
+	MaxBackoff int64
+}
+
 type seedersConfiguration struct {
 	Addresses []string
 	Fixed     []string
@@ -91,6 +153,24 @@ type seedersConfiguration struct {
 type databaseConfiguration struct {
 	Driver string
 	Dir    string
+
+	// ProvisionersSnapshotInterval is the number of blocks between two
+	// persisted provisioners snapshots. Snapshots are persisted on each
+	// stake-set change too. 0 disables periodic snapshots.
+	ProvisionersSnapshotInterval uint64
+
+	Pruning pruningConfiguration
+}
+
+// Chain pruning configs. Supported by heavy driver only.
+type pruningConfiguration struct {
+	Enabled bool
+	// KeepBlocks is the number of most recent blocks to keep full bodies of.
+	KeepBlocks uint64
+	// BatchSize is the number of blocks pruned in a single atomic update.
+	BatchSize uint64
+	// Interval between two pruning runs, in seconds.
+	Interval uint
 }
 
 // wallet configs.
@@ -176,6 +256,29 @@ type mempoolConfiguration struct {
 	PoolType    string
 	PreallocTxs uint32
 	MaxInvItems uint32
+
+	// TxTTL is the number of seconds a tx can stay in the mempool. Zero
+	// disables the expiry.
+	TxTTL int64
+	// ReverifyAge is the number of seconds after which a tx is verified
+	// again against the latest chain state. Zero disables re-verification.
+	ReverifyAge int64
+
+	// ReplaceFeeMargin is the minimum fee increase, in percent, for a tx to
+	// replace the pending txs spending the same nullifiers.
+	ReplaceFeeMargin uint64
+	// MaxTxsPerPeer is the maximum number of pending txs received from a
+	// single peer. Zero disables the quota.
+	MaxTxsPerPeer int
+	// MaxTxsPerClient is the maximum number of pending txs submitted by a
+	// single RPC client. Zero disables the quota.
+	MaxTxsPerClient int
+
+	// JournalFile is the file where the verified txs are journaled, to be
+	// reloaded on restart. Empty disables the journal.
+	JournalFile string
+	// JournalInterval is the number of seconds between two journal writes.
+	JournalInterval int64
 }
 
 type consensusConfiguration struct {
@@ -183,4 +286,86 @@ type consensusConfiguration struct {
 	DefaultAmount   uint64
 	// ConsensusTimeOut is the time out for consensus step timers.
 	ConsensusTimeOut int64
+
+	Journal  consensusJournalConfiguration
+	Timeouts consensusTimeoutsConfiguration
+	Queues   consensusQueuesConfiguration
+}
+
+// Capacities of the consensus message channels and queues.
+type consensusQueuesConfiguration struct {
+	// AgreementChanSize and EventChanSize are the buffer sizes of the
+	// channels the Agreement, and the Score and Reduction messages are
+	// dispatched to. The messages are dropped while they are full.
+	AgreementChanSize int
+	EventChanSize     int
+	// MaxRoundEvents is the number of messages queued per round, for the
+	// steps and the rounds ahead of the current one.
+	MaxRoundEvents int
+	// MaxRounds is the number of rounds with queued messages. Once reached,
+	// a message for a lower round evicts the highest one.
+	MaxRounds int
+}
+
+// Adaptive reduction step timeouts, derived from the time the steps of the
+// recent rounds took to reach quorum.
+type consensusTimeoutsConfiguration struct {
+	// Adaptive enables the adaptation. If false, the reduction steps start a
+	// round with ConsensusTimeOut, like the selection.
+	Adaptive bool
+	// Window is the number of recent quorum delays kept per step.
+	Window int
+	// Multiplier is applied to the 90th percentile of the quorum delays.
+	Multiplier float64
+	// MinTimeout and MaxTimeout bound the step timeouts, in milliseconds.
+	MinTimeout int64
+	MaxTimeout int64
+}
+
+// Consensus journal, recording the phases, the timeouts, the messages and the
+// certificates of each round.
+type consensusJournalConfiguration struct {
+	Enabled bool
+	// Dir is the directory of the journal files.
+	Dir string
+	// MaxFileSize is the size in bytes the journal file is rotated at.
+	MaxFileSize int64
+	// MaxFiles is the number of journal files kept, the oldest being
+	// removed on rotation.
+	MaxFiles int
+}
+
+// Block synchronization configs.
+type syncConfiguration struct {
+	// Workers is the maximum number of peers downloading block bodies in
+	// parallel, after the headers are fetched. Zero disables the header-first
+	// sync, in favor of the single-peer GetBlocks sync.
+	Workers int
+	// BatchSize is the number of blocks requested from a peer at once.
+	BatchSize uint64
+	// RequestTimeout is the number of seconds after which the blocks not yet
+	// delivered by a peer are requested to another one.
+	RequestTimeout int64
+
+	// SequencerMaxBlocks and SequencerMaxBytes bound the blocks received
+	// ahead of the chain tip during a sync.
+	SequencerMaxBlocks int
+	SequencerMaxBytes  int
+	// MaxBlocksAhead is the maximum height above the sync target of the
+	// blocks accepted during a sync.
+	MaxBlocksAhead uint64
+
+	Checkpoint checkpointConfiguration
+}
+
+// Fast sync checkpoint configs. Blocks up to the checkpoint are accepted
+// without executing their state transition.
+type checkpointConfiguration struct {
+	// Height of the trusted block. Zero disables the fast sync.
+	Height uint64
+	// Hash of the trusted block, hex-encoded.
+	Hash string
+	// Provisioners is the path of the trusted provisioners snapshots file,
+	// as written by the `dusk checkpoint` command.
+	Provisioners string
 }
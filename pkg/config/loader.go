@@ -59,6 +59,7 @@ type Registry struct {
 	Kadcast   kadcastConfiguration
 	Mempool   mempoolConfiguration
 	Consensus consensusConfiguration
+	Sync      syncConfiguration
 
 	RPC rpcConfiguration
 	Gql gqlConfiguration
@@ -252,6 +253,7 @@ func init() {
 	r = new(Registry)
 	r.lock = new(sync.RWMutex)
 	r.Database.Driver = "lite_v0.1.0"
+	r.Database.ProvisionersSnapshotInterval = 1000
 	r.General.Network = devnet
 	r.Wallet.File = "wallet.dat"
 	r.Wallet.Store = "walletDB"
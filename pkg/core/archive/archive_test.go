@@ -0,0 +1,152 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package archive
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestExportResume(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(os.TempDir(), "archive_")
+	assert.NoError(err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	_, db := lite.CreateDBConnection()
+
+	blocks := storeBlocks(t, db, 10)
+	path := filepath.Join(dir, "chain.dat")
+
+	// Export the first half, then simulate an interrupted export by chopping
+	// off the tail of the last record
+	assert.NoError(Export(db, path, protocol.DevNet, 0, 4, nil))
+
+	info, err := os.Stat(path)
+	assert.NoError(err)
+	assert.NoError(os.Truncate(path, info.Size()-2))
+
+	var lastPercent float64
+
+	assert.NoError(Export(db, path, protocol.DevNet, 0, 0, func(height uint64, percent float64) {
+		lastPercent = percent
+	}))
+	assert.Equal(float64(100), lastPercent)
+
+	f, err := os.Open(path)
+	assert.NoError(err)
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	r, err := NewReader(f)
+	assert.NoError(err)
+	assert.Equal(Version, r.Header().Version)
+	assert.Equal(protocol.DevNet, r.Header().Network)
+
+	for _, blk := range blocks {
+		rec, err := r.Next()
+		assert.NoError(err)
+		assert.Equal(blk.Header.Height, rec.Height)
+		assert.True(blk.Equals(rec.Block))
+	}
+
+	_, err = r.Next()
+	assert.Equal(io.EOF, err)
+}
+
+func TestExportWrongNetwork(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(os.TempDir(), "archive_")
+	assert.NoError(err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	_, db := lite.CreateDBConnection()
+	_ = storeBlocks(t, db, 2)
+
+	path := filepath.Join(dir, "chain.dat")
+	assert.NoError(Export(db, path, protocol.DevNet, 0, 0, nil))
+	assert.Error(Export(db, path, protocol.TestNet, 0, 0, nil))
+}
+
+func TestCorruptedRecord(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(os.TempDir(), "archive_")
+	assert.NoError(err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	_, db := lite.CreateDBConnection()
+	_ = storeBlocks(t, db, 1)
+
+	path := filepath.Join(dir, "chain.dat")
+	assert.NoError(Export(db, path, protocol.DevNet, 0, 0, nil))
+
+	b, err := ioutil.ReadFile(path)
+	assert.NoError(err)
+
+	// Flip a byte in the middle of the first record
+	b[headerSize+recordPrefix+10] ^= 0xff
+	assert.NoError(ioutil.WriteFile(path, b, 0644))
+
+	f, err := os.Open(path)
+	assert.NoError(err)
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	r, err := NewReader(f)
+	assert.NoError(err)
+
+	_, err = r.Next()
+	assert.Equal(ErrInvalidChecksum, err)
+}
+
+func storeBlocks(t *testing.T, db database.DB, n int) []*block.Block {
+	blocks := make([]*block.Block, n)
+	for i := range blocks {
+		blocks[i] = helper.RandomBlock(uint64(i), 1)
+	}
+
+	err := db.Update(func(t database.Transaction) error {
+		for _, blk := range blocks {
+			if err := t.StoreBlock(blk); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return blocks
+}
@@ -0,0 +1,157 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package archive
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	logger "github.com/sirupsen/logrus"
+)
+
+var log = logger.WithField("process", "archive")
+
+// Export writes all blocks in range [from, to] to the archive at path. If to
+// is 0, the export stops at the current chain tip.
+//
+// If the archive already exists, the export is resumed after the last valid
+// record found in it. A record truncated by an interrupted export is
+// discarded and written again.
+func Export(db database.DB, path string, network protocol.Magic, from, to uint64, progress ProgressFunc) error {
+	var tip uint64
+
+	err := db.View(func(t database.Transaction) error {
+		var err error
+		tip, err = t.FetchCurrentHeight()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if to == 0 || to > tip {
+		to = tip
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	offset, last, err := scanArchive(f, network)
+	if err != nil {
+		return err
+	}
+
+	writeHeader := offset == 0
+	if last != nil {
+		from = *last + 1
+
+		log.WithField("height", *last).Info("resuming export")
+	}
+
+	// Drop whatever follows the last valid record
+	if err = f.Truncate(offset); err != nil {
+		return err
+	}
+
+	if _, err = f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	w, err := NewWriter(f, network, writeHeader)
+	if err != nil {
+		return err
+	}
+
+	for height := from; height <= to; height++ {
+		var blk *block.Block
+
+		err = db.View(func(t database.Transaction) error {
+			hash, err := t.FetchBlockHashByHeight(height)
+			if err != nil {
+				return err
+			}
+
+			blk, err = t.FetchBlock(hash)
+			return err
+		})
+		if err != nil {
+			_ = w.Flush()
+			return fmt.Errorf("could not fetch block %d: %v", height, err)
+		}
+
+		if err = w.Write(blk); err != nil {
+			_ = w.Flush()
+			return err
+		}
+
+		if progress != nil {
+			progress(height, float64(height-from+1)*100/float64(to-from+1))
+		}
+	}
+
+	if err = w.Flush(); err != nil {
+		return err
+	}
+
+	return f.Sync()
+}
+
+// scanArchive reads an existing archive and returns the offset right after
+// the last valid record, together with its height. A nil height means no
+// record has been found. An empty file has offset 0.
+func scanArchive(f *os.File, network protocol.Magic) (int64, *uint64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if info.Size() == 0 {
+		return 0, nil, nil
+	}
+
+	r, err := NewReader(f)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if r.Header().Network != network {
+		return 0, nil, fmt.Errorf("archive network is %s, expected %s", r.Header().Network, network)
+	}
+
+	var last *uint64
+
+	offset := int64(headerSize)
+
+	for {
+		rec, err := r.Next()
+
+		switch err {
+		case nil:
+			height := rec.Height
+			last = &height
+			offset += rec.Size
+			continue
+		case io.EOF:
+		case io.ErrUnexpectedEOF, ErrInvalidChecksum:
+			log.WithError(err).WithField("offset", offset).Warn("discarding corrupted archive tail")
+		default:
+			return 0, nil, err
+		}
+
+		return offset, last, nil
+	}
+}
@@ -0,0 +1,240 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+// Package archive implements a portable, streaming file format to move a
+// chain between nodes.
+//
+// An archive starts with a fixed-size header:
+//
+//	magic (8 bytes) | version (1 byte) | network (1 byte) | checksum (4 bytes)
+//
+// followed by a sequence of block records:
+//
+//	height (8 bytes LE) | size (4 bytes LE) | marshaled block | checksum (4 bytes)
+//
+// Checksums are computed with the wire checksum package over all preceding
+// bytes of the header or record. Records are stored in ascending height
+// order, without gaps.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/checksum"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+)
+
+// Version is the archive format version written by this package.
+const Version uint8 = 1
+
+// MaxRecordSize is the maximum size of a marshaled block accepted in a record.
+const MaxRecordSize = 1 << 26
+
+const (
+	magicLen       = 8
+	headerSize     = magicLen + 1 + 1 + checksum.Length
+	recordPrefix   = 8 + 4
+	recordOverhead = recordPrefix + checksum.Length
+)
+
+var archiveMagic = []byte("DUSKARCH")
+
+var (
+	// ErrInvalidArchive is returned when the file is not a chain archive.
+	ErrInvalidArchive = errors.New("archive: invalid archive file")
+	// ErrUnsupportedVersion is returned when the archive has been written
+	// with an unknown format version.
+	ErrUnsupportedVersion = errors.New("archive: unsupported version")
+	// ErrInvalidChecksum is returned when a header or a record is corrupted.
+	ErrInvalidChecksum = errors.New("archive: invalid checksum")
+)
+
+// Header describes the content of an archive.
+type Header struct {
+	Version uint8
+	Network protocol.Magic
+}
+
+// Record is a single block stored in an archive.
+type Record struct {
+	Height uint64
+	Block  *block.Block
+	// Size is the amount of bytes the record takes in the archive.
+	Size int64
+}
+
+// ProgressFunc is called after each block processed by Export or Import,
+// with the height of the block and the completion percentage.
+type ProgressFunc func(height uint64, percent float64)
+
+func marshalHeader(h Header) []byte {
+	buf := new(bytes.Buffer)
+	buf.Write(archiveMagic)
+	buf.WriteByte(h.Version)
+	buf.WriteByte(byte(h.Network))
+	buf.Write(checksum.Generate(buf.Bytes()))
+
+	return buf.Bytes()
+}
+
+func unmarshalHeader(b []byte) (Header, error) {
+	if len(b) != headerSize || !bytes.Equal(b[:magicLen], archiveMagic) {
+		return Header{}, ErrInvalidArchive
+	}
+
+	if !checksum.Verify(b[:headerSize-checksum.Length], b[headerSize-checksum.Length:]) {
+		return Header{}, ErrInvalidChecksum
+	}
+
+	h := Header{Version: b[magicLen], Network: protocol.Magic(b[magicLen+1])}
+	if h.Version != Version {
+		return Header{}, ErrUnsupportedVersion
+	}
+
+	return h, nil
+}
+
+// Writer writes block records to an archive.
+type Writer struct {
+	w *bufio.Writer
+}
+
+// NewWriter creates a Writer on top of w. If writeHeader is false, w is
+// expected to be positioned after the last valid record of an existing
+// archive, so that new records are appended to it.
+func NewWriter(w io.Writer, network protocol.Magic, writeHeader bool) (*Writer, error) {
+	bw := bufio.NewWriter(w)
+
+	if writeHeader {
+		if _, err := bw.Write(marshalHeader(Header{Version: Version, Network: network})); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Writer{w: bw}, nil
+}
+
+// Write appends a block record to the archive.
+func (w *Writer) Write(blk *block.Block) error {
+	body := new(bytes.Buffer)
+	if err := message.MarshalBlock(body, blk); err != nil {
+		return err
+	}
+
+	if body.Len() > MaxRecordSize {
+		return fmt.Errorf("archive: block %d exceeds max record size", blk.Header.Height)
+	}
+
+	rec := new(bytes.Buffer)
+	if err := encoding.WriteUint64LE(rec, blk.Header.Height); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteUint32LE(rec, uint32(body.Len())); err != nil {
+		return err
+	}
+
+	_, _ = rec.Write(body.Bytes())
+	_, _ = rec.Write(checksum.Generate(rec.Bytes()))
+
+	_, err := w.w.Write(rec.Bytes())
+	return err
+}
+
+// Flush writes any buffered record to the underlying writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Reader reads block records from an archive.
+type Reader struct {
+	r      *bufio.Reader
+	header Header
+}
+
+// NewReader creates a Reader on top of r and validates the archive header.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+
+	b := make([]byte, headerSize)
+	if _, err := io.ReadFull(br, b); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrInvalidArchive
+		}
+
+		return nil, err
+	}
+
+	h, err := unmarshalHeader(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reader{r: br, header: h}, nil
+}
+
+// Header returns the archive header.
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+// Next reads the next record from the archive. It returns io.EOF when the
+// archive ends cleanly, and io.ErrUnexpectedEOF if the last record has been
+// truncated, e.g. by an interrupted export.
+func (r *Reader) Next() (*Record, error) {
+	prefix := make([]byte, recordPrefix)
+	if _, err := io.ReadFull(r.r, prefix); err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(prefix)
+
+	var height uint64
+	if err := encoding.ReadUint64LE(buf, &height); err != nil {
+		return nil, err
+	}
+
+	var size uint32
+	if err := encoding.ReadUint32LE(buf, &size); err != nil {
+		return nil, err
+	}
+
+	if size > MaxRecordSize {
+		return nil, ErrInvalidArchive
+	}
+
+	rest := make([]byte, int(size)+checksum.Length)
+	if _, err := io.ReadFull(r.r, rest); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	body := rest[:size]
+	if !checksum.Verify(append(prefix, body...), rest[size:]) {
+		return nil, ErrInvalidChecksum
+	}
+
+	blk := block.NewBlock()
+	if err := message.UnmarshalBlock(bytes.NewBuffer(body), blk); err != nil {
+		return nil, err
+	}
+
+	if blk.Header.Height != height {
+		return nil, ErrInvalidArchive
+	}
+
+	return &Record{Height: height, Block: blk, Size: int64(size) + recordOverhead}, nil
+}
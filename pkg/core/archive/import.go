@@ -0,0 +1,134 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/chain"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+)
+
+// Import reads the archive at path and appends its blocks to the local chain.
+// Each block is checked with chain.DBLoader.SanityCheckBlock and
+// verifiers.CheckBlockCertificate, and executed through the executor in
+// order to keep the provisioner set up to date, before being stored.
+//
+// Blocks already present in the local chain are skipped, so an interrupted
+// import can be resumed by running it again on the same archive.
+func Import(ctx context.Context, db database.DB, genesis *block.Block, executor transactions.Executor, path string, network protocol.Magic, progress ProgressFunc) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	r, err := NewReader(f)
+	if err != nil {
+		return err
+	}
+
+	if r.Header().Network != network {
+		return fmt.Errorf("archive network is %s, expected %s", r.Header().Network, network)
+	}
+
+	loader := chain.NewDBLoader(db, genesis)
+
+	tip, err := loader.LoadTip()
+	if err != nil {
+		return err
+	}
+
+	provisioners, err := executor.GetProvisioners(ctx)
+	if err != nil {
+		return err
+	}
+
+	offset := int64(headerSize)
+
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		blk := rec.Block
+		offset += rec.Size
+
+		if rec.Height <= tip.Header.Height {
+			if err := checkKnownBlock(db, blk); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if rec.Height > tip.Header.Height+1 {
+			return fmt.Errorf("archive has a gap: expected block %d, got %d", tip.Header.Height+1, rec.Height)
+		}
+
+		if err := loader.SanityCheckBlock(*tip, *blk); err != nil {
+			return fmt.Errorf("block %d: %v", rec.Height, err)
+		}
+
+		if err := verifiers.CheckBlockCertificate(provisioners, *blk); err != nil {
+			return fmt.Errorf("block %d: invalid certificate: %v", rec.Height, err)
+		}
+
+		provisioners, err = executor.ExecuteStateTransition(ctx, blk.Txs, blk.Header.Height)
+		if err != nil {
+			return fmt.Errorf("block %d: %v", rec.Height, err)
+		}
+
+		if err := loader.Append(blk); err != nil {
+			return err
+		}
+
+		tip = blk
+
+		if progress != nil {
+			progress(rec.Height, float64(offset)*100/float64(info.Size()))
+		}
+	}
+}
+
+// checkKnownBlock ensures that a block below the local tip matches the one
+// already stored at the same height.
+func checkKnownBlock(db database.DB, blk *block.Block) error {
+	return db.View(func(t database.Transaction) error {
+		hash, err := t.FetchBlockHashByHeight(blk.Header.Height)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(hash, blk.Header.Hash) {
+			return fmt.Errorf("block %d in archive conflicts with the local chain", blk.Header.Height)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,177 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
+)
+
+const (
+	// FailureBlockUnavailable means that the block header could not be read
+	// from the database.
+	FailureBlockUnavailable verifiers.CertificateFailure = "block unavailable"
+	// FailureProvisionersUnavailable means that no provisioners snapshot is
+	// available for the height preceding the block.
+	FailureProvisionersUnavailable verifiers.CertificateFailure = "provisioners unavailable"
+)
+
+// CertificateFailure reports a block which certificate could not be verified.
+type CertificateFailure struct {
+	Height uint64                       `json:"height"`
+	Hash   string                       `json:"hash,omitempty"`
+	Reason verifiers.CertificateFailure `json:"reason"`
+	Step   uint8                        `json:"step,omitempty"`
+	Error  string                       `json:"error"`
+}
+
+// CertificateReport is the outcome of DBLoader.VerifyCertificates.
+type CertificateReport struct {
+	From     uint64               `json:"from"`
+	To       uint64               `json:"to"`
+	Checked  uint64               `json:"checked"`
+	Failures []CertificateFailure `json:"failures"`
+}
+
+// VerifyCertificates re-verifies the certificates of all blocks stored in the
+// height range [from, to] against the provisioners valid at each height, as
+// restored from the provisioners snapshots. Blocks are verified by the given
+// amount of parallel workers, and failures are reported sorted by height.
+//
+// An error is returned only if the verification could not be carried out.
+func (l *DBLoader) VerifyCertificates(ctx context.Context, from, to uint64, workers int) (*CertificateReport, error) {
+	if from > to {
+		return nil, errors.New("invalid height range")
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		lock   sync.Mutex
+		wg     sync.WaitGroup
+		report = &CertificateReport{From: from, To: to, Failures: make([]CertificateFailure, 0)}
+	)
+
+	heights := make(chan uint64, workers)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for height := range heights {
+				failure := l.verifyCertificateAt(height)
+
+				lock.Lock()
+				report.Checked++
+
+				if failure != nil {
+					report.Failures = append(report.Failures, *failure)
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+
+	var err error
+
+feed:
+	for height := from; ; height++ {
+		select {
+		case heights <- height:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break feed
+		}
+
+		if height == to {
+			break
+		}
+	}
+
+	close(heights)
+	wg.Wait()
+
+	sort.Slice(report.Failures, func(i, j int) bool {
+		return report.Failures[i].Height < report.Failures[j].Height
+	})
+
+	return report, err
+}
+
+// verifyCertificateAt verifies the certificate of the block at height. It
+// returns nil if the certificate is valid.
+func (l *DBLoader) verifyCertificateAt(height uint64) *CertificateFailure {
+	blk, err := l.BlockAt(height)
+	if err == database.ErrBlockPruned {
+		// The certificate is part of the header, which is never pruned
+		blk, err = l.headerAt(height)
+	}
+
+	if err != nil {
+		return &CertificateFailure{Height: height, Reason: FailureBlockUnavailable, Error: err.Error()}
+	}
+
+	failure := &CertificateFailure{Height: height, Hash: hex.EncodeToString(blk.Header.Hash)}
+
+	if height < 2 {
+		// Certificates of the first blocks are not verified, see
+		// verifiers.CheckBlockCertificate
+		return nil
+	}
+
+	provisioners, err := l.ProvisionersAt(height - 1)
+	if err != nil {
+		failure.Reason = FailureProvisionersUnavailable
+		failure.Error = err.Error()
+		return failure
+	}
+
+	err = verifiers.AuditBlockCertificate(*provisioners, blk)
+	if err == nil {
+		return nil
+	}
+
+	var certErr *verifiers.CertificateError
+	if errors.As(err, &certErr) {
+		failure.Reason = certErr.Failure
+		failure.Step = certErr.Step
+	}
+
+	failure.Error = err.Error()
+	return failure
+}
+
+// headerAt returns a block holding only the header stored at height.
+func (l *DBLoader) headerAt(height uint64) (block.Block, error) {
+	var hdr *block.Header
+
+	err := l.db.View(func(t database.Transaction) error {
+		hash, err := t.FetchBlockHashByHeight(height)
+		if err != nil {
+			return err
+		}
+
+		hdr, err = t.FetchBlockHeader(hash)
+		return err
+	})
+	if err != nil {
+		return block.Block{}, err
+	}
+
+	return block.Block{Header: hdr}, nil
+}
@@ -0,0 +1,56 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestVerifyCertificates(t *testing.T) {
+	assert := assert.New(t)
+
+	_, db := lite.CreateDBConnection()
+
+	// Certificates of random blocks are empty, and so fail on the step
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		for height := uint64(0); height < 6; height++ {
+			if err := t.StoreBlock(helper.RandomBlock(height, 1)); err != nil {
+				return err
+			}
+		}
+
+		p, _ := consensus.MockProvisioners(5)
+		return t.StoreProvisioners(2, p)
+	}))
+
+	l := NewDBLoader(db, nil)
+
+	report, err := l.VerifyCertificates(context.Background(), 0, 5, 3)
+	assert.NoError(err)
+	assert.Equal(uint64(6), report.Checked)
+	assert.Len(report.Failures, 4)
+
+	// No provisioners are known before height 2
+	assert.Equal(uint64(2), report.Failures[0].Height)
+	assert.Equal(FailureProvisionersUnavailable, report.Failures[0].Reason)
+
+	for i, failure := range report.Failures[1:] {
+		assert.Equal(uint64(i+3), failure.Height)
+		assert.Equal(verifiers.FailureStep, failure.Reason)
+	}
+
+	_, err = l.VerifyCertificates(context.Background(), 5, 0, 1)
+	assert.Error(err)
+}
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
@@ -60,6 +61,10 @@ type Loader interface {
 	BlockAt(uint64) (block.Block, error)
 	// Append a block on the storage.
 	Append(*block.Block) error
+	// AppendWithProvisioners appends a block on the storage, together with
+	// the snapshot of the provisioners resulting from it. A nil snapshot is
+	// not stored.
+	AppendWithProvisioners(*block.Block, *user.Provisioners) error
 }
 
 // Ledger is the Chain interface used in tests.
@@ -71,6 +76,21 @@ type Ledger interface {
 	ProcessSyncTimerExpired(strPeerAddr string) error
 }
 
+// InvalidBlockError is returned by AcceptBlock when a block fails the
+// verification, as opposed to a failure of the node itself.
+type InvalidBlockError struct {
+	Err error
+}
+
+func (e *InvalidBlockError) Error() string {
+	return "invalid block: " + e.Err.Error()
+}
+
+// Unwrap returns the verification error.
+func (e *InvalidBlockError) Unwrap() error {
+	return e.Err
+}
+
 // Chain represents the nodes blockchain.
 // This struct will be aware of the current state of the node.
 type Chain struct {
@@ -90,6 +110,9 @@ type Chain struct {
 	// Current set of provisioners.
 	p *user.Provisioners
 
+	// checkpoint is set while fast syncing to a trusted block.
+	checkpoint *checkpoint
+
 	// Consensus loop.
 	loop              *loop.Consensus
 	stopConsensusChan chan struct{}
@@ -120,20 +143,33 @@ func New(ctx context.Context, db database.DB, eventBus *eventbus.EventBus, loade
 
 	chain.synchronizer = newSynchronizer(db, chain)
 
-	provisioners, err := proxy.Executor().GetProvisioners(ctx)
+	prevBlock, err := loader.LoadTip()
 	if err != nil {
-		log.WithError(err).Error("Error in getting provisioners")
 		return nil, err
 	}
 
-	chain.p = &provisioners
+	chain.tip = prevBlock
+
+	if err := chain.initCheckpoint(prevBlock.Header.Height); err != nil {
+		log.WithError(err).Error("Error in setting up the checkpoint")
+		return nil, err
+	}
+
+	var provisioners *user.Provisioners
+
+	if chain.checkpoint != nil {
+		// The executor state is the one at the checkpoint
+		provisioners, _, err = chain.fetchProvisioners(prevBlock.Header.Height)
+	} else {
+		provisioners, err = chain.loadProvisioners(prevBlock.Header.Height)
+	}
 
-	prevBlock, err := loader.LoadTip()
 	if err != nil {
+		log.WithError(err).Error("Error in getting provisioners")
 		return nil, err
 	}
 
-	chain.tip = prevBlock
+	chain.p = provisioners
 
 	if prevBlock.Header.Height == 0 {
 		// TODO: this is currently mocking bid values, and should be removed when
@@ -145,6 +181,9 @@ func New(ctx context.Context, db database.DB, eventBus *eventbus.EventBus, loade
 
 	if srv != nil {
 		node.RegisterChainServer(srv, chain)
+		RegisterSyncStatusServer(srv, chain)
+		RegisterCommitteeServer(srv, chain)
+		RegisterRollbackServer(srv, rollbackServer{chain})
 	}
 
 	return chain, nil
@@ -197,6 +236,41 @@ func (c *Chain) ProcessBlockFromNetwork(srcPeerID string, m message.Message) ([]
 	return c.synchronizer.processBlock(srcPeerID, c.tip.Header.Height, blk, kadcastHeight)
 }
 
+// ProcessHeadersFromNetwork will handle the headers requested by the
+// header-first sync.
+// Satisfies the peer.ProcessorFunc interface.
+func (c *Chain) ProcessHeadersFromNetwork(srcPeerID string, m message.Message) ([]bytes.Buffer, error) {
+	headers := m.Payload().(message.Headers)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return nil, c.synchronizer.processHeaders(srcPeerID, headers.Headers)
+}
+
+// EnableParallelSync switches the synchronizer to the header-first sync,
+// downloading the blocks from multiple peers. It has no effect if the sync
+// workers are disabled by configuration.
+func (c *Chain) EnableParallelSync(peers Peers) {
+	if config.Get().Sync.Workers <= 0 {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.synchronizer.manager = newSyncManager(peers, c.ProcessSyncTimerExpired)
+}
+
+// SetPeerReporter enables the reporting of the peers supplying invalid
+// blocks.
+func (c *Chain) SetPeerReporter(reporter PeerReporter) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.synchronizer.reporter = reporter
+}
+
 // ProduceBlock will start the consensus loop. It can be halted at any point by
 // sending a signal through the `stopConsensus` channel (`StopBlockProduction`
 // as exposed by the `Ledger` interface).
@@ -332,7 +406,7 @@ func (c *Chain) AcceptBlock(blk block.Block) error {
 	// 1. Check that stateless and stateful checks pass
 	if err := c.verifier.SanityCheckBlock(*c.tip, blk); err != nil {
 		l.WithError(err).Error("block verification failed")
-		return err
+		return &InvalidBlockError{err}
 	}
 
 	// 2. Check the certificate
@@ -343,23 +417,44 @@ func (c *Chain) AcceptBlock(blk block.Block) error {
 
 	if err := verifiers.CheckBlockCertificate(*c.p, blk); err != nil {
 		l.WithError(err).Error("certificate verification failed")
-		return err
+		return &InvalidBlockError{err}
 	}
 
-	// 3. Call ExecuteStateTransitionFunction
+	// 3. Call ExecuteStateTransitionFunction. Up to a trusted checkpoint, the
+	// resulting provisioners are restored from the snapshots instead
 	prov_num := c.p.Set.Len()
 
-	l.WithField("provisioners", prov_num).Info("calling ExecuteStateTransitionFunction")
+	var (
+		provisioners *user.Provisioners
+		changed      bool
+	)
 
-	// TODO: the context here should maybe used to set a timeout
-	provisioners, err := c.proxy.Executor().ExecuteStateTransition(c.ctx, blk.Txs, blk.Header.Height)
-	if err != nil {
-		l.WithError(err).Error("Error in executing the state transition")
-		return err
+	if c.checkpoint != nil {
+		l.Trace("verifying block against the checkpoint")
+
+		p, err := c.checkpointProvisioners(blk)
+		if err != nil {
+			l.WithError(err).Error("checkpoint verification failed")
+			return err
+		}
+
+		provisioners = p
+	} else {
+		l.WithField("provisioners", prov_num).Info("calling ExecuteStateTransitionFunction")
+
+		// TODO: the context here should maybe used to set a timeout
+		p, err := c.proxy.Executor().ExecuteStateTransition(c.ctx, blk.Txs, blk.Header.Height)
+		if err != nil {
+			l.WithError(err).Error("Error in executing the state transition")
+			return err
+		}
+
+		provisioners = &p
+		changed = provisionersChanged(c.p, provisioners)
 	}
 
 	// Update the provisioners as blk.Txs may bring new provisioners to the current state
-	c.p = &provisioners
+	c.p = provisioners
 	c.tip = &blk
 
 	l.WithField("provisioners", c.p.Set.Len()).
@@ -370,10 +465,17 @@ func (c *Chain) AcceptBlock(blk block.Block) error {
 		go c.storeStakesInStormDB(blk.Header.Height)
 	}
 
-	// 4. Store the approved block
+	// 4. Store the approved block, with a provisioners snapshot if the set
+	// changed or the snapshot interval is reached. Both are stored atomically,
+	// so that a stored block always has its snapshot.
 	l.Trace("storing block in db")
 
-	if err := c.loader.Append(&blk); err != nil {
+	var snapshot *user.Provisioners
+	if snapshotDue(blk.Header.Height, changed) {
+		snapshot = c.p
+	}
+
+	if err := c.loader.AppendWithProvisioners(&blk, snapshot); err != nil {
 		l.WithError(err).Error("block storing failed")
 		return err
 	}
@@ -385,7 +487,13 @@ func (c *Chain) AcceptBlock(blk block.Block) error {
 		return err
 	}
 
-	// 6. Notify other subsystems for the accepted block
+	if c.checkpoint != nil && blk.Header.Height == c.checkpoint.height {
+		l.Info("checkpoint reached, switching to full verification")
+
+		c.checkpoint = nil
+	}
+
+	// 5. Notify other subsystems for the accepted block
 	// Subsystems listening for this topic:
 	// mempool.Mempool
 	l.Trace("notifying internally")
@@ -399,6 +507,135 @@ func (c *Chain) AcceptBlock(blk block.Block) error {
 	return nil
 }
 
+// Rollback removes all blocks above height from the chain, and restores the
+// block at height as chain tip, with the provisioners snapshot persisted at
+// or below height. For each removed block, from the highest down, a
+// topics.RolledBackBlock is published so that the txs can be re-admitted in
+// the mempool.
+//
+// The executor state can not be rolled back through the Rusk API. The
+// operator is expected to restore Rusk at height beforehand, and the rollback
+// is refused if the provisioners of the executor do not match the snapshot.
+//
+// Block production is stopped, and restarted from the resulting tip if the
+// node runs the consensus.
+func (c *Chain) Rollback(height uint64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	tipHeight := c.tip.Header.Height
+	if height >= tipHeight {
+		return fmt.Errorf("rollback height %d is not lower than the tip height %d", height, tipHeight)
+	}
+
+	var prunedHeight uint64
+
+	if err := c.db.View(func(t database.Transaction) error {
+		var err error
+		prunedHeight, err = t.FetchPrunedHeight()
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if prunedHeight > 0 && height < prunedHeight {
+		return fmt.Errorf("rollback height %d is lower than the pruned height %d", height, prunedHeight)
+	}
+
+	provisioners, _, err := c.fetchProvisioners(height)
+	if err != nil {
+		return fmt.Errorf("no provisioners snapshot at height %d: %w", height, err)
+	}
+
+	executorProvisioners, err := c.proxy.Executor().GetProvisioners(c.ctx)
+	if err != nil {
+		return fmt.Errorf("could not get the executor provisioners: %w", err)
+	}
+
+	if provisionersChanged(provisioners, &executorProvisioners) {
+		return fmt.Errorf("height %d: %w", height, ErrExecutorStateMismatch)
+	}
+
+	l := log.WithField("process", "rollback").
+		WithField("height", height).
+		WithField("tip", tipHeight)
+
+	l.Info("rolling back chain")
+
+	c.StopBlockProduction()
+
+	// Block production resumes from the current tip, whether or not the
+	// rollback succeeds
+	defer func() {
+		if c.loop == nil {
+			return
+		}
+
+		if err := c.ProduceBlock(); err != nil {
+			l.WithError(err).Error("could not restart the consensus loop")
+		}
+	}()
+
+	removed := make([]block.Block, 0, tipHeight-height)
+
+	for h := tipHeight; h > height; h-- {
+		blk, err := c.loader.BlockAt(h)
+		if err != nil {
+			l.WithError(err).Error("could not fetch block to remove")
+			return err
+		}
+
+		removed = append(removed, blk)
+	}
+
+	if err := c.db.Update(func(t database.Transaction) error {
+		for i := range removed {
+			if err := t.DeleteBlock(&removed[i]); err != nil {
+				return err
+			}
+		}
+
+		return t.ClearCandidateMessages()
+	}); err != nil {
+		l.WithError(err).Error("block deletion failed")
+		return err
+	}
+
+	tip, err := c.loader.LoadTip()
+	if err != nil {
+		l.WithError(err).Error("could not load the restored tip")
+		return err
+	}
+
+	c.tip = tip
+	c.p = provisioners
+
+	// Notify other subsystems for the removed blocks
+	// Subsystems listening for this topic:
+	// mempool.Mempool
+	for _, blk := range removed {
+		msg := message.New(topics.RolledBackBlock, blk)
+		errList := c.eventBus.Publish(topics.RolledBackBlock, msg)
+
+		diagnostics.LogPublishErrors("chain/chain.go, topics.RolledBackBlock", errList)
+	}
+
+	l.WithField("removed", len(removed)).
+		WithField("provisioners", c.p.Set.Len()).
+		Info("chain rolled back")
+
+	// Any sync in progress targets the removed blocks
+	c.timer.Cancel()
+	c.stopParallelSync()
+	c.stats.stopped()
+
+	log.WithField("state", "inSync").Traceln("change sync state")
+
+	c.state = c.inSync
+
+	return nil
+}
+
 // VerifyCandidateBlock can be used as a callback for the consensus in order to
 // verify potential winning candidates.
 func (c *Chain) VerifyCandidateBlock(blk block.Block) error {
@@ -481,6 +718,10 @@ func (c *Chain) CalculateSyncProgress() float64 {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
 
+	return c.syncProgress()
+}
+
+func (c *Chain) syncProgress() float64 {
 	if c.highestSeen == 0 {
 		return 0.0
 	}
@@ -540,15 +781,26 @@ func (c *Chain) storeStakesInStormDB(blkHeight uint64) {
 	}
 }
 
-// ProcessSyncTimerExpired called by outsync timer when a peer does not provide GetData response.
+// ProcessSyncTimerExpired called by outsync timer when a peer does not provide GetData response,
+// or by the header-first sync when no peer can deliver the missing blocks.
 // It implements transition back to inSync state.
 // strPeerAddr is the address of the peer initiated the syncing but failed to deliver.
+// It does nothing if the sync has already completed, as the consensus loop
+// is already running.
 func (c *Chain) ProcessSyncTimerExpired(strPeerAddr string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.stats.syncing {
+		return nil
+	}
+
 	log.WithField("curr", c.tip.Header.Height).
 		WithField("src_addr", strPeerAddr).Warn("sync timer expired")
 
-	c.lock.Lock()
-	defer c.lock.Unlock()
+	c.stopParallelSync()
+	c.stats.failed(fmt.Errorf("peer %s failed to deliver the missing blocks", strPeerAddr))
+	c.stats.stopped()
 
 	if err := c.ProduceBlock(); err != nil {
 		log.WithError(err).Warn("sync timer could not restart consensus loop")
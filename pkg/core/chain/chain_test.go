@@ -9,6 +9,7 @@ package chain
 import (
 	"bytes"
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/key"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/keys"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
@@ -176,6 +178,65 @@ func TestAcceptBlock(t *testing.T) {
 	assert.True(decodedBlk.Equals(c.tip))
 }
 
+// This test ensures that a rollback restores the previous tip and notifies
+// about the removed blocks.
+func TestRollback(t *testing.T) {
+	assert := assert.New(t)
+	eb, c := setupChainTest(t, 0)
+
+	rolledBackChan := make(chan message.Message, 2)
+	eb.Subscribe(topics.RolledBackBlock, eventbus.NewChanListener(rolledBackChan))
+
+	c.lock.Lock()
+	prevTip := c.tip
+	c.lock.Unlock()
+
+	// Append two blocks on top of the current tip
+	blk1 := helper.RandomBlock(prevTip.Header.Height+1, 1)
+	blk1.Header.PrevBlockHash = prevTip.Header.Hash
+	blk2 := helper.RandomBlock(prevTip.Header.Height+2, 1)
+	blk2.Header.PrevBlockHash = blk1.Header.Hash
+
+	assert.NoError(c.loader.Append(blk1))
+	assert.NoError(c.loader.Append(blk2))
+
+	c.lock.Lock()
+	c.tip = blk2
+	c.lock.Unlock()
+
+	assert.Error(c.Rollback(blk2.Header.Height))
+
+	// The rollback is refused while the executor state does not match
+	executor := c.proxy.Executor().(*transactions.PermissiveExecutor)
+	k, _ := key.NewRandKeys()
+	assert.NoError(executor.P.Add(k.BLSPubKeyBytes, 1000, 0, 1000))
+	assert.True(errors.Is(c.Rollback(prevTip.Header.Height), ErrExecutorStateMismatch))
+
+	executor.P = user.NewProvisioners()
+	assert.NoError(c.Rollback(prevTip.Header.Height))
+
+	c.lock.RLock()
+	assert.True(bytes.Equal(prevTip.Header.Hash, c.tip.Header.Hash))
+	c.lock.RUnlock()
+
+	assert.NoError(c.db.View(func(t database.Transaction) error {
+		s, err := t.FetchState()
+		assert.NoError(err)
+		assert.Equal(prevTip.Header.Hash, s.TipHash)
+
+		_, err = t.FetchBlockExists(blk1.Header.Hash)
+		assert.Equal(database.ErrBlockNotFound, err)
+		return nil
+	}))
+
+	// Removed blocks are notified from the highest down
+	for _, blk := range []*block.Block{blk2, blk1} {
+		m := <-rolledBackChan
+		decodedBlk := m.Payload().(block.Block)
+		assert.True(decodedBlk.Equals(blk))
+	}
+}
+
 func createLoader(db database.DB) *DBLoader {
 	genesis := config.DecodeGenesis()
 	// genesis := helper.RandomBlock(0, 12)
@@ -221,6 +282,39 @@ func TestSyncProgress(t *testing.T) {
 	assert.Equal(resp.Progress, float32(50.0))
 }
 
+func TestSyncStatus(t *testing.T) {
+	assert := assert.New(t)
+	_, c := setupChainTest(t, 0)
+
+	status := c.SyncStatus()
+	assert.Equal(SyncStateInSync, status.State)
+	assert.Zero(status.Target)
+
+	// An expired sync timer is ignored once in sync
+	assert.NoError(c.ProcessSyncTimerExpired("peer"))
+	assert.Empty(c.SyncStatus().LastError)
+
+	// A block from far in the future starts a sync
+	blk := helper.RandomBlock(100, 1)
+	_, err := c.ProcessBlockFromNetwork("peer", message.New(topics.Block, *blk))
+	assert.NoError(err)
+
+	status = c.SyncStatus()
+	assert.Equal(SyncStateOutSync, status.State)
+	assert.Equal(uint64(100), status.Target)
+	assert.Equal(uint64(100), status.HighestSeen)
+	assert.Equal("peer", status.Peer)
+	assert.Equal(1, status.SequencerBacklog)
+	assert.Empty(status.LastError)
+
+	assert.NoError(c.ProcessSyncTimerExpired("peer"))
+
+	status = c.SyncStatus()
+	assert.Equal(SyncStateInSync, status.State)
+	assert.NotEmpty(status.LastError)
+	assert.NotZero(status.LastErrorTime)
+}
+
 // mock a block which can be accepted by the chain.
 // note that this is only valid for height 1, as the certificate
 // is not checked on height 1 (for network bootstrapping)
@@ -0,0 +1,231 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+)
+
+// checkpoint is a trusted block of the chain. Blocks up to the checkpoint
+// are accepted without executing their state transition: only their linkage
+// and certificates are verified, against the trusted provisioners snapshots.
+type checkpoint struct {
+	height uint64
+	hash   []byte
+}
+
+// loadCheckpoint returns the configured checkpoint, or nil if the fast sync
+// is disabled.
+func loadCheckpoint() (*checkpoint, error) {
+	conf := config.Get().Sync.Checkpoint
+	if conf.Height == 0 {
+		return nil, nil
+	}
+
+	hash, err := hex.DecodeString(conf.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint hash: %v", err)
+	}
+
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("invalid checkpoint hash length %d", len(hash))
+	}
+
+	return &checkpoint{height: conf.Height, hash: hash}, nil
+}
+
+// initCheckpoint enables the fast sync if the chain tip is below the
+// configured checkpoint. The trusted provisioners snapshots are persisted, so
+// that the provisioners resulting from each block up to the checkpoint can be
+// restored without the executor.
+func (c *Chain) initCheckpoint(tipHeight uint64) error {
+	cp, err := loadCheckpoint()
+	if err != nil {
+		return err
+	}
+
+	if cp == nil || tipHeight >= cp.height {
+		return nil
+	}
+
+	f, err := os.Open(config.Get().Sync.Checkpoint.Provisioners)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	snapshots, err := ReadProvisionersSnapshots(f)
+	if err != nil {
+		return fmt.Errorf("could not read provisioners snapshots: %v", err)
+	}
+
+	if err := c.db.Update(func(t database.Transaction) error {
+		for _, s := range snapshots {
+			if err := t.StoreProvisioners(s.Height, s.Provisioners); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	log.WithField("height", cp.height).
+		WithField("hash", hex.EncodeToString(cp.hash)).
+		WithField("snapshots", len(snapshots)).
+		Info("fast sync to checkpoint enabled")
+
+	c.checkpoint = cp
+	return nil
+}
+
+// checkpointProvisioners verifies the hash of a block up to the checkpoint,
+// and returns the provisioners resulting from it, as restored from the
+// trusted snapshots.
+//
+// Blocks above the checkpoint are executed, hence the checkpoint block is
+// refused unless the provisioners of the executor match the trusted snapshot,
+// i.e. unless Rusk has been bootstrapped with the state at the checkpoint.
+func (c *Chain) checkpointProvisioners(blk block.Block) (*user.Provisioners, error) {
+	hash, err := blk.CalculateHash()
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(hash, blk.Header.Hash) {
+		return nil, &InvalidBlockError{errors.New("block hash mismatch")}
+	}
+
+	if blk.Header.Height == c.checkpoint.height && !bytes.Equal(hash, c.checkpoint.hash) {
+		return nil, &InvalidBlockError{fmt.Errorf("block %s does not match the checkpoint %s", hex.EncodeToString(hash), hex.EncodeToString(c.checkpoint.hash))}
+	}
+
+	p, _, err := c.fetchProvisioners(blk.Header.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	if blk.Header.Height == c.checkpoint.height {
+		executorProvisioners, err := c.proxy.Executor().GetProvisioners(c.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not get the executor provisioners: %w", err)
+		}
+
+		if provisionersChanged(p, &executorProvisioners) {
+			return nil, fmt.Errorf("checkpoint %d: %w", c.checkpoint.height, ErrExecutorStateMismatch)
+		}
+	}
+
+	return p, nil
+}
+
+// ProvisionersSnapshot is the provisioners set resulting from the block at
+// Height.
+type ProvisionersSnapshot struct {
+	Height       uint64
+	Provisioners *user.Provisioners
+}
+
+// ProvisionersSnapshots returns the provisioners snapshots persisted at or
+// below height, from the highest down.
+func (l *DBLoader) ProvisionersSnapshots(height uint64) ([]ProvisionersSnapshot, error) {
+	snapshots := make([]ProvisionersSnapshot, 0)
+
+	err := l.db.View(func(t database.Transaction) error {
+		for {
+			p, snapshotHeight, err := t.FetchProvisioners(height)
+			if err == database.ErrProvisionersNotFound && len(snapshots) > 0 {
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			snapshots = append(snapshots, ProvisionersSnapshot{snapshotHeight, p})
+
+			if snapshotHeight == 0 {
+				return nil
+			}
+
+			height = snapshotHeight - 1
+		}
+	})
+
+	return snapshots, err
+}
+
+// WriteProvisionersSnapshots encodes the snapshots into w.
+func WriteProvisionersSnapshots(w io.Writer, snapshots []ProvisionersSnapshot) error {
+	buf := new(bytes.Buffer)
+	if err := encoding.WriteVarInt(buf, uint64(len(snapshots))); err != nil {
+		return err
+	}
+
+	for _, s := range snapshots {
+		if err := encoding.WriteUint64LE(buf, s.Height); err != nil {
+			return err
+		}
+
+		if err := user.MarshalProvisioners(buf, s.Provisioners); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ReadProvisionersSnapshots decodes the snapshots written by
+// WriteProvisionersSnapshots.
+func ReadProvisionersSnapshots(r io.Reader) ([]ProvisionersSnapshot, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(b)
+
+	n, err := encoding.ReadVarInt(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]ProvisionersSnapshot, 0, n)
+
+	for i := uint64(0); i < n; i++ {
+		var height uint64
+		if err := encoding.ReadUint64LE(buf, &height); err != nil {
+			return nil, err
+		}
+
+		p, err := user.UnmarshalProvisioners(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, ProvisionersSnapshot{height, &p})
+	}
+
+	return snapshots, nil
+}
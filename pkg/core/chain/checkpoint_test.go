@@ -0,0 +1,93 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/key"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestProvisionersSnapshots(t *testing.T) {
+	assert := assert.New(t)
+
+	_, db := lite.CreateDBConnection()
+
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		for i, height := range []uint64{0, 3, 7} {
+			p, _ := consensus.MockProvisioners(i + 1)
+			if err := t.StoreProvisioners(height, p); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+
+	l := NewDBLoader(db, nil)
+
+	snapshots, err := l.ProvisionersSnapshots(5)
+	assert.NoError(err)
+	assert.Len(snapshots, 2)
+	assert.Equal(uint64(3), snapshots[0].Height)
+	assert.Equal(uint64(0), snapshots[1].Height)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(WriteProvisionersSnapshots(buf, snapshots))
+
+	decoded, err := ReadProvisionersSnapshots(buf)
+	assert.NoError(err)
+	assert.Len(decoded, 2)
+
+	for i := range snapshots {
+		assert.Equal(snapshots[i].Height, decoded[i].Height)
+		assert.False(provisionersChanged(snapshots[i].Provisioners, decoded[i].Provisioners))
+	}
+}
+
+// This test ensures that a block up to the checkpoint is accepted only if it
+// matches the checkpoint hash, and the executor state matches the checkpoint.
+func TestAcceptBlockCheckpoint(t *testing.T) {
+	assert := assert.New(t)
+	_, c := setupChainTest(t, 0)
+
+	blk := mockAcceptableBlock(*c.tip)
+
+	hash, err := blk.CalculateHash()
+	assert.NoError(err)
+
+	blk.Header.Hash = hash
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.checkpoint = &checkpoint{height: 1, hash: make([]byte, 32)}
+	assert.Error(c.AcceptBlock(*blk))
+
+	c.checkpoint = &checkpoint{height: 1, hash: hash}
+
+	// The executor state does not match the trusted snapshot
+	executor := c.proxy.Executor().(*transactions.PermissiveExecutor)
+	k, _ := key.NewRandKeys()
+	assert.NoError(executor.P.Add(k.BLSPubKeyBytes, 1000, 0, 1000))
+	assert.True(errors.Is(c.AcceptBlock(*blk), ErrExecutorStateMismatch))
+	assert.NotNil(c.checkpoint)
+
+	executor.P = user.NewProvisioners()
+	assert.NoError(c.AcceptBlock(*blk))
+
+	assert.True(bytes.Equal(hash, c.tip.Header.Hash))
+	assert.Nil(c.checkpoint)
+}
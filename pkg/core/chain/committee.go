@@ -0,0 +1,289 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/key"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/server"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"google.golang.org/grpc"
+)
+
+const (
+	// ProvisionersSourceCurrent means that a committee is extracted from the
+	// provisioners of the chain tip.
+	ProvisionersSourceCurrent = "current"
+	// ProvisionersSourceSnapshot means that a committee is extracted from a
+	// persisted provisioners snapshot.
+	ProvisionersSourceSnapshot = "snapshot"
+)
+
+var (
+	errNoRound       = errors.New("round 0 has no committee")
+	errNoStep        = errors.New("steps start at 1")
+	errSelectionStep = errors.New("selection steps have no voting committee")
+)
+
+// CommitteeRequest selects the voting committee of a round and step.
+type CommitteeRequest struct {
+	Round uint64 `json:"round"`
+	Step  uint8  `json:"step"`
+}
+
+// CommitteeMember is a provisioner extracted in a voting committee.
+type CommitteeMember struct {
+	// PubKeyBLS is the hex encoded BLS public key of the provisioner.
+	PubKeyBLS string `json:"pubKeyBLS"`
+	// Votes is the number of times the provisioner was extracted.
+	Votes int `json:"votes"`
+	// Stake is the stake of the provisioner active at the round.
+	Stake uint64 `json:"stake"`
+}
+
+// Committee is the voting committee of a round and step, as extracted by the
+// deterministic sortition.
+type Committee struct {
+	Round uint64 `json:"round"`
+	Step  uint8  `json:"step"`
+	// Phase is the reduction step the committee votes in.
+	Phase   string            `json:"phase"`
+	Members []CommitteeMember `json:"members"`
+	// Size is the number of votes of the committee.
+	Size int `json:"size"`
+	// Quorum is the number of votes needed to reach a quorum.
+	Quorum int `json:"quorum"`
+	// TotalWeight is the stake of all the provisioners active at the round,
+	// which the sortition is run on.
+	TotalWeight uint64 `json:"totalWeight"`
+	// Provisioners is the number of provisioners active at the round.
+	Provisioners int `json:"provisioners"`
+	// AmMember is true if the BLS key of this node is in the committee, with
+	// LocalVotes votes.
+	AmMember   bool `json:"amMember"`
+	LocalVotes int  `json:"localVotes"`
+	// Source tells where the provisioners come from, and SnapshotHeight is
+	// the height of the snapshot used.
+	Source         string `json:"source"`
+	SnapshotHeight uint64 `json:"snapshotHeight,omitempty"`
+}
+
+// Committee returns the voting committee of a round and step. The rounds
+// ahead of the chain tip are extracted from the current provisioners, and the
+// past rounds from the provisioners snapshot persisted for their previous
+// block.
+func (c *Chain) Committee(round uint64, step uint8) (*Committee, error) {
+	if round == 0 {
+		return nil, errNoRound
+	}
+
+	if step == 0 {
+		return nil, errNoStep
+	}
+
+	if step%3 == 1 {
+		return nil, fmt.Errorf("step %d: %w", step, errSelectionStep)
+	}
+
+	p, source, snapshotHeight, err := c.provisionersAt(round)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys key.Keys
+	if c.loop != nil && c.loop.Emitter != nil {
+		keys = c.loop.Keys
+	}
+
+	return extractCommittee(keys, p, round, step, source, snapshotHeight), nil
+}
+
+// provisionersAt returns the provisioners a round is run with.
+func (c *Chain) provisionersAt(round uint64) (user.Provisioners, string, uint64, error) {
+	c.lock.RLock()
+	tipHeight := c.tip.Header.Height
+
+	if round > tipHeight {
+		p := c.p.Copy()
+		c.lock.RUnlock()
+
+		return p, ProvisionersSourceCurrent, 0, nil
+	}
+
+	c.lock.RUnlock()
+
+	p, snapshotHeight, err := c.fetchProvisioners(round - 1)
+	if err != nil {
+		return user.Provisioners{}, "", 0, fmt.Errorf("provisioners of round %d: %w", round, err)
+	}
+
+	return *p, ProvisionersSourceSnapshot, snapshotHeight, nil
+}
+
+// extractCommittee runs the sortition of a reduction step committee, with the
+// committee size and quorum of the consensus.
+func extractCommittee(keys key.Keys, p user.Provisioners, round uint64, step uint8, source string, snapshotHeight uint64) *Committee {
+	h := reduction.NewHandler(keys, p)
+	vc := h.Committee(round, step)
+
+	phase := "reduction-first-step"
+	if step%3 == 0 {
+		phase = "reduction-second-step"
+	}
+
+	cmt := &Committee{
+		Round:          round,
+		Step:           step,
+		Phase:          phase,
+		Members:        make([]CommitteeMember, 0, vc.Set.Len()),
+		Size:           vc.Size(),
+		Quorum:         h.Quorum(round),
+		Source:         source,
+		SnapshotHeight: snapshotHeight,
+	}
+
+	for _, m := range p.Members {
+		if stake := activeStake(m, round); stake > 0 {
+			cmt.TotalWeight += stake
+			cmt.Provisioners++
+		}
+	}
+
+	for _, pk := range vc.Set {
+		pubKey := pk.Bytes()
+
+		cmt.Members = append(cmt.Members, CommitteeMember{
+			PubKeyBLS: hex.EncodeToString(pubKey),
+			Votes:     vc.OccurrencesOf(pubKey),
+			Stake:     activeStake(p.GetMember(pubKey), round),
+		})
+	}
+
+	if len(keys.BLSPubKeyBytes) > 0 {
+		cmt.LocalVotes = vc.OccurrencesOf(keys.BLSPubKeyBytes)
+		cmt.AmMember = cmt.LocalVotes > 0
+	}
+
+	return cmt
+}
+
+// activeStake returns the stake of a provisioner eligible to the sortition
+// of a round.
+func activeStake(m *user.Member, round uint64) uint64 {
+	if m == nil {
+		return 0
+	}
+
+	var stake uint64
+
+	for _, s := range m.Stakes {
+		if s.StartHeight <= round && s.EndHeight >= round {
+			stake += s.Amount
+		}
+	}
+
+	return stake
+}
+
+// ServeCommittee answers the topics.GetCommittee requests of the rpcbus,
+// until the context is canceled. The params of a request are the round, as
+// a little endian uint64, followed by the step.
+func (c *Chain) ServeCommittee(ctx context.Context, rpcBus *rpcbus.RPCBus) error {
+	reqChan := make(chan rpcbus.Request, 1)
+	if err := rpcBus.Register(topics.GetCommittee, reqChan); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case r := <-reqChan:
+				r.RespChan <- c.serveCommitteeRequest(r)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (c *Chain) serveCommitteeRequest(r rpcbus.Request) rpcbus.Response {
+	params, ok := r.Params.(bytes.Buffer)
+	if !ok {
+		return rpcbus.NewResponse(nil, errors.New("invalid committee request"))
+	}
+
+	var (
+		round uint64
+		step  uint8
+	)
+
+	if err := encoding.ReadUint64LE(&params, &round); err != nil {
+		return rpcbus.NewResponse(nil, err)
+	}
+
+	if err := encoding.ReadUint8(&params, &step); err != nil {
+		return rpcbus.NewResponse(nil, err)
+	}
+
+	cmt, err := c.Committee(round, step)
+	return rpcbus.NewResponse(cmt, err)
+}
+
+// GetCommitteeMethod is the full gRPC method name of the committee call. It
+// is served by the node.Committee server.JSONService until dusk-protobuf
+// defines it.
+const GetCommitteeMethod = "/node.Committee/GetCommittee"
+
+// CommitteeServer is the server API of the node.Committee service.
+type CommitteeServer interface {
+	GetCommittee(context.Context, *CommitteeRequest) (*Committee, error)
+}
+
+var committeeService = server.JSONService{
+	Name:        "node.Committee",
+	HandlerType: (*CommitteeServer)(nil),
+	Methods: []server.JSONMethod{
+		{
+			Name:       "GetCommittee",
+			NewRequest: func() interface{} { return new(CommitteeRequest) },
+			Call: func(srv interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.(CommitteeServer).GetCommittee(ctx, req.(*CommitteeRequest))
+			},
+		},
+	},
+}
+
+// RegisterCommitteeServer registers the node.Committee service.
+func RegisterCommitteeServer(s *grpc.Server, srv CommitteeServer) {
+	committeeService.Register(s, srv)
+}
+
+// GetCommittee implements CommitteeServer.
+func (c *Chain) GetCommittee(_ context.Context, req *CommitteeRequest) (*Committee, error) {
+	return c.Committee(req.Round, req.Step)
+}
+
+// GetCommittee calls the node.Committee service.
+func GetCommittee(ctx context.Context, cc grpc.ClientConnInterface, round uint64, step uint8) (*Committee, error) {
+	cmt := new(Committee)
+	if err := server.InvokeJSON(ctx, cc, GetCommitteeMethod, &CommitteeRequest{Round: round, Step: step}, cmt); err != nil {
+		return nil, err
+	}
+
+	return cmt, nil
+}
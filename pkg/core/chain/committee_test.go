@@ -0,0 +1,99 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/key"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestExtractCommittee(t *testing.T) {
+	assert := assert.New(t)
+
+	p, keys := consensus.MockProvisioners(5)
+
+	// a stake which is not active yet is not part of the sortition
+	late, _ := key.NewRandKeys()
+	assert.NoError(p.Add(late.BLSPubKeyBytes, 1000, 100, 200))
+
+	cmt := extractCommittee(keys[0], *p, 10, 2, ProvisionersSourceCurrent, 0)
+
+	assert.Equal("reduction-first-step", cmt.Phase)
+	assert.Equal(5, cmt.Size)
+	assert.Equal(4, cmt.Quorum)
+	assert.Equal(uint64(2500), cmt.TotalWeight)
+	assert.Equal(5, cmt.Provisioners)
+
+	votes := 0
+	for _, m := range cmt.Members {
+		votes += m.Votes
+		assert.Equal(uint64(500), m.Stake)
+	}
+
+	assert.Equal(cmt.Size, votes)
+
+	// the committee is the one the consensus extracts
+	h := reduction.NewHandler(keys[0], *p)
+	assert.Equal(h.AmMember(10, 2), cmt.AmMember)
+	assert.Equal(h.VotesFor(keys[0].BLSPubKeyBytes, 10, 2), cmt.LocalVotes)
+
+	// without keys, the node is not a member
+	cmt = extractCommittee(key.Keys{}, *p, 10, 3, ProvisionersSourceCurrent, 0)
+	assert.Equal("reduction-second-step", cmt.Phase)
+	assert.False(cmt.AmMember)
+}
+
+func TestCommitteeProvisioners(t *testing.T) {
+	assert := assert.New(t)
+
+	_, db := lite.CreateDBConnection()
+
+	past, _ := consensus.MockProvisioners(3)
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		return t.StoreProvisioners(2, past)
+	}))
+
+	current, _ := consensus.MockProvisioners(5)
+
+	tip := block.NewBlock()
+	tip.Header.Height = 5
+
+	c := &Chain{db: db, tip: tip, p: current}
+
+	// the rounds ahead use the current provisioners
+	cmt, err := c.Committee(6, 2)
+	assert.NoError(err)
+	assert.Equal(ProvisionersSourceCurrent, cmt.Source)
+	assert.Equal(5, cmt.Provisioners)
+
+	// the past rounds use the snapshot of their previous block
+	cmt, err = c.Committee(4, 2)
+	assert.NoError(err)
+	assert.Equal(ProvisionersSourceSnapshot, cmt.Source)
+	assert.Equal(uint64(2), cmt.SnapshotHeight)
+	assert.Equal(3, cmt.Provisioners)
+
+	_, err = c.Committee(2, 2)
+	assert.True(errors.Is(err, database.ErrProvisionersNotFound))
+
+	_, err = c.Committee(0, 2)
+	assert.Equal(errNoRound, err)
+
+	_, err = c.Committee(6, 0)
+	assert.Equal(errNoStep, err)
+
+	_, err = c.Committee(6, 4)
+	assert.True(errors.Is(err, errSelectionStep))
+}
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
@@ -85,6 +86,22 @@ func (l *DBLoader) Append(blk *block.Block) error {
 	})
 }
 
+// AppendWithProvisioners stores a block and the snapshot of the provisioners
+// resulting from it in the same transaction. A nil snapshot is not stored.
+func (l *DBLoader) AppendWithProvisioners(blk *block.Block, p *user.Provisioners) error {
+	return l.db.Update(func(t database.Transaction) error {
+		if err := t.StoreBlock(blk); err != nil {
+			return err
+		}
+
+		if p == nil {
+			return nil
+		}
+
+		return t.StoreProvisioners(blk.Header.Height, p)
+	})
+}
+
 // BlockAt returns the block stored at a given height.
 func (l *DBLoader) BlockAt(searchingHeight uint64) (block.Block, error) {
 	var blk *block.Block
@@ -105,6 +122,21 @@ func (l *DBLoader) BlockAt(searchingHeight uint64) (block.Block, error) {
 	return *blk, err
 }
 
+// ProvisionersAt returns the provisioners set resulting from the block at a
+// given height, as restored from the latest snapshot persisted at or below it.
+// It returns database.ErrProvisionersNotFound if no such snapshot exists.
+func (l *DBLoader) ProvisionersAt(height uint64) (*user.Provisioners, error) {
+	var p *user.Provisioners
+
+	err := l.db.View(func(t database.Transaction) error {
+		var err error
+		p, _, err = t.FetchProvisioners(height)
+		return err
+	})
+
+	return p, err
+}
+
 // Clear the underlying DB.
 func (l *DBLoader) Clear() error {
 	return l.db.Update(func(t database.Transaction) error {
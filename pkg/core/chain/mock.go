@@ -7,6 +7,7 @@
 package chain
 
 import (
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 )
 
@@ -65,6 +66,11 @@ func (m *MockLoader) Append(blk *block.Block) error {
 	return nil
 }
 
+// AppendWithProvisioners appends the block, the provisioners are ignored.
+func (m *MockLoader) AppendWithProvisioners(blk *block.Block, _ *user.Provisioners) error {
+	return m.Append(blk)
+}
+
 // BlockAt the block to the internal blockchain representation.
 func (m *MockLoader) BlockAt(index uint64) (block.Block, error) {
 	return m.blockchain[index], nil
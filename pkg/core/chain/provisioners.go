@@ -0,0 +1,102 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+)
+
+// provisionersChanged returns true if the stake sets of a and b differ.
+func provisionersChanged(a, b *user.Provisioners) bool {
+	if len(a.Members) != len(b.Members) {
+		return true
+	}
+
+	for k, m := range a.Members {
+		o, ok := b.Members[k]
+		if !ok || len(m.Stakes) != len(o.Stakes) {
+			return true
+		}
+
+		for i := range m.Stakes {
+			if m.Stakes[i] != o.Stakes[i] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// snapshotDue returns true if the provisioners resulting from the block at
+// height should be persisted.
+func snapshotDue(height uint64, changed bool) bool {
+	interval := config.Get().Database.ProvisionersSnapshotInterval
+	return changed || (interval > 0 && height%interval == 0)
+}
+
+// Provisioners returns a copy of the current provisioners, and the round they
+// run.
+func (c *Chain) Provisioners() (user.Provisioners, uint64) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.p.Copy(), c.tip.Header.Height + 1
+}
+
+// storeProvisioners persists a snapshot of p as the provisioners set
+// resulting from the block at height.
+func (c *Chain) storeProvisioners(height uint64, p *user.Provisioners) error {
+	return c.db.Update(func(t database.Transaction) error {
+		return t.StoreProvisioners(height, p)
+	})
+}
+
+// loadProvisioners requests the current provisioners from the executor. If
+// the executor cannot be reached, the snapshot persisted for the chain tip is
+// used instead.
+func (c *Chain) loadProvisioners(tipHeight uint64) (*user.Provisioners, error) {
+	provisioners, err := c.proxy.Executor().GetProvisioners(c.ctx)
+	if err == nil {
+		// Ensure there is a snapshot to start from
+		if _, _, fetchErr := c.fetchProvisioners(tipHeight); fetchErr == database.ErrProvisionersNotFound {
+			if storeErr := c.storeProvisioners(tipHeight, &provisioners); storeErr != nil {
+				log.WithError(storeErr).Warn("could not store provisioners snapshot")
+			}
+		}
+
+		return &provisioners, nil
+	}
+
+	log.WithError(err).Warn("could not get provisioners from executor, loading snapshot")
+
+	p, snapshotHeight, fetchErr := c.fetchProvisioners(tipHeight)
+	if fetchErr != nil {
+		// The executor error is the meaningful one
+		return nil, err
+	}
+
+	log.WithField("height", snapshotHeight).Info("provisioners loaded from snapshot")
+	return p, nil
+}
+
+func (c *Chain) fetchProvisioners(height uint64) (*user.Provisioners, uint64, error) {
+	var (
+		p              *user.Provisioners
+		snapshotHeight uint64
+	)
+
+	err := c.db.View(func(t database.Transaction) error {
+		var err error
+		p, snapshotHeight, err = t.FetchProvisioners(height)
+		return err
+	})
+
+	return p, snapshotHeight, err
+}
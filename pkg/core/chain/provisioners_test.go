@@ -0,0 +1,75 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	crypto "github.com/dusk-network/dusk-crypto/hash"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestProvisionersChanged(t *testing.T) {
+	assert := assert.New(t)
+
+	key, _ := crypto.RandEntropy(129)
+
+	a := user.NewProvisioners()
+	assert.NoError(a.Add(key, 1000, 0, 100))
+
+	b := user.NewProvisioners()
+	assert.NoError(b.Add(key, 1000, 0, 100))
+	assert.False(provisionersChanged(a, b))
+
+	// Same member, different stake
+	c := user.NewProvisioners()
+	assert.NoError(c.Add(key, 2000, 0, 100))
+	assert.True(provisionersChanged(a, c))
+
+	// New member
+	other, _ := crypto.RandEntropy(129)
+	assert.NoError(b.Add(other, 1000, 0, 100))
+	assert.True(provisionersChanged(a, b))
+}
+
+func TestAppendWithProvisioners(t *testing.T) {
+	assert := assert.New(t)
+
+	_, db := lite.CreateDBConnection()
+	loader := createLoader(db)
+
+	key, _ := crypto.RandEntropy(129)
+
+	p := user.NewProvisioners()
+	assert.NoError(p.Add(key, 1000, 0, 100))
+
+	blk := helper.RandomBlock(1, 1)
+	assert.NoError(loader.AppendWithProvisioners(blk, p))
+
+	// The snapshot is stored with the block
+	stored, err := loader.ProvisionersAt(1)
+	assert.NoError(err)
+	assert.False(provisionersChanged(p, stored))
+
+	// Without snapshot, only the block is stored
+	assert.NoError(loader.AppendWithProvisioners(helper.RandomBlock(2, 1), nil))
+
+	height, err := loader.Height()
+	assert.NoError(err)
+	assert.Equal(uint64(2), height)
+
+	assert.NoError(db.View(func(t database.Transaction) error {
+		_, snapshotHeight, err := t.FetchProvisioners(2)
+		assert.NoError(err)
+		assert.Equal(uint64(1), snapshotHeight)
+		return nil
+	}))
+}
@@ -0,0 +1,91 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/server"
+	"google.golang.org/grpc"
+)
+
+// ErrExecutorStateMismatch is returned when the provisioners of the executor
+// do not match the provisioners snapshot of the height the chain is set to.
+var ErrExecutorStateMismatch = errors.New("executor provisioners do not match the provisioners snapshot")
+
+// RollbackMethod is the full gRPC method name of the rollback call.
+// dusk-protobuf has no rollback RPC, so that it is served by the
+// node.Rollback server.JSONService.
+const RollbackMethod = "/node.Rollback/Rollback"
+
+// RollbackRequest sets the chain tip back to the block at Height.
+type RollbackRequest struct {
+	Height uint64 `json:"height"`
+}
+
+// RollbackResponse is the chain tip after a rollback.
+type RollbackResponse struct {
+	Height uint64 `json:"height"`
+	Hash   string `json:"hash"`
+}
+
+// RollbackServer is the server API of the node.Rollback service.
+type RollbackServer interface {
+	Rollback(context.Context, *RollbackRequest) (*RollbackResponse, error)
+}
+
+var rollbackService = server.JSONService{
+	Name:        "node.Rollback",
+	HandlerType: (*RollbackServer)(nil),
+	Methods: []server.JSONMethod{
+		{
+			Name:       "Rollback",
+			NewRequest: func() interface{} { return new(RollbackRequest) },
+			Call: func(srv interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.(RollbackServer).Rollback(ctx, req.(*RollbackRequest))
+			},
+		},
+	},
+}
+
+// RegisterRollbackServer registers the node.Rollback service.
+func RegisterRollbackServer(s *grpc.Server, srv RollbackServer) {
+	rollbackService.Register(s, srv)
+}
+
+// rollbackServer serves the node.Rollback service for a Chain, whose own
+// Rollback method takes a height.
+type rollbackServer struct {
+	c *Chain
+}
+
+// Rollback implements RollbackServer.
+func (s rollbackServer) Rollback(_ context.Context, req *RollbackRequest) (*RollbackResponse, error) {
+	if err := s.c.Rollback(req.Height); err != nil {
+		return nil, err
+	}
+
+	s.c.lock.RLock()
+	defer s.c.lock.RUnlock()
+
+	return &RollbackResponse{
+		Height: s.c.tip.Header.Height,
+		Hash:   hex.EncodeToString(s.c.tip.Header.Hash),
+	}, nil
+}
+
+// Rollback calls the node.Rollback service.
+func Rollback(ctx context.Context, cc grpc.ClientConnInterface, height uint64) (*RollbackResponse, error) {
+	resp := new(RollbackResponse)
+	if err := server.InvokeJSON(ctx, cc, RollbackMethod, &RollbackRequest{Height: height}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
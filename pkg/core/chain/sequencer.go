@@ -7,41 +7,104 @@
 package chain
 
 import (
+	"bytes"
 	"encoding/hex"
 	"errors"
 	"sync"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	defaultSequencerMaxBlocks = 2000
+	defaultSequencerMaxBytes  = 256 * 1024 * 1024
+)
+
+var errSequencerFull = errors.New("sequencer is full")
+
+// sequencedBlock is a block waiting in the sequencer, along with the address
+// of the peer which supplied it.
+type sequencedBlock struct {
+	block.Block
+	src  string
+	size int
+}
+
 // The sequencer is used to order incoming blocks and provide them
 // in the correct order to the Chain when synchronizing.
+// It is bounded both in number of blocks and in bytes. When full, the highest
+// blocks are evicted in favor of lower ones, as they are needed last.
 // NOTE: the sequencer is not synchronized, as it is used by the Chain
 // directly during the acceptance procedure. The mutex in this procedure
 // should be sufficient to guard this map.
 type sequencer struct {
 	lock      sync.RWMutex
-	blockPool map[uint64]block.Block
+	blockPool map[uint64]sequencedBlock
+	size      int
+
+	maxBlocks int
+	maxBytes  int
 }
 
 func newSequencer() *sequencer {
-	return &sequencer{blockPool: make(map[uint64]block.Block)}
+	conf := config.Get().Sync
+
+	s := &sequencer{
+		blockPool: make(map[uint64]sequencedBlock),
+		maxBlocks: conf.SequencerMaxBlocks,
+		maxBytes:  conf.SequencerMaxBytes,
+	}
+
+	if s.maxBlocks <= 0 {
+		s.maxBlocks = defaultSequencerMaxBlocks
+	}
+
+	if s.maxBytes <= 0 {
+		s.maxBytes = defaultSequencerMaxBytes
+	}
+
+	return s
 }
 
-func (s *sequencer) add(blk block.Block) {
+// add a block supplied by src. It returns errSequencerFull if there is no
+// room for the block, even after evicting the blocks above it.
+func (s *sequencer) add(blk block.Block, src string) error {
+	buf := new(bytes.Buffer)
+	if err := message.MarshalBlock(buf, &blk); err != nil {
+		return err
+	}
+
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	s.blockPool[blk.Header.Height] = blk
+
+	height := blk.Header.Height
+	s.delete(height)
+
+	for len(s.blockPool) >= s.maxBlocks || s.size+buf.Len() > s.maxBytes {
+		highest, ok := s.highest()
+		if !ok || highest < height {
+			return errSequencerFull
+		}
+
+		s.delete(highest)
+	}
+
+	s.blockPool[height] = sequencedBlock{Block: blk, src: src, size: buf.Len()}
+	s.size += buf.Len()
+
+	return nil
 }
 
-func (s *sequencer) get(height uint64) (block.Block, error) {
+func (s *sequencer) get(height uint64) (sequencedBlock, error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
 	blk, ok := s.blockPool[height]
 	if !ok {
-		return block.Block{}, errors.New("block not found")
+		return sequencedBlock{}, errors.New("block not found")
 	}
 
 	return blk, nil
@@ -50,7 +113,38 @@ func (s *sequencer) get(height uint64) (block.Block, error) {
 func (s *sequencer) remove(height uint64) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	delete(s.blockPool, height)
+	s.delete(height)
+}
+
+// delete a block. The lock must be held.
+func (s *sequencer) delete(height uint64) {
+	if blk, ok := s.blockPool[height]; ok {
+		s.size -= blk.size
+		delete(s.blockPool, height)
+	}
+}
+
+// highest returns the height of the highest block. The lock must be held.
+func (s *sequencer) highest() (uint64, bool) {
+	var (
+		highest uint64
+		found   bool
+	)
+
+	for height := range s.blockPool {
+		if !found || height > highest {
+			highest, found = height, true
+		}
+	}
+
+	return highest, found
+}
+
+// len returns the number of blocks in the pool.
+func (s *sequencer) len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.blockPool)
 }
 
 // cleanup removes all blocks that are lower than currentHeight.
@@ -60,15 +154,15 @@ func (s *sequencer) cleanup(currentHeight uint64) {
 
 	for height := range s.blockPool {
 		if height < currentHeight {
-			delete(s.blockPool, height)
+			s.delete(height)
 		}
 	}
 }
 
 // Provide successive blocks to the given height. Once a gap is detected, the loop
 // quits and returns a set of blocks.
-func (s *sequencer) provideSuccessors(blk block.Block) []block.Block {
-	blks := []block.Block{blk}
+func (s *sequencer) provideSuccessors(blk sequencedBlock) []sequencedBlock {
+	blks := []sequencedBlock{blk}
 
 	for i := blk.Header.Height + 1; ; i++ {
 		blk, err := s.get(i)
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
 	assert "github.com/stretchr/testify/require"
@@ -32,19 +33,19 @@ func TestSequencer(t *testing.T) {
 	}
 
 	for _, blk := range blks {
-		seq.add(blk)
+		assert.NoError(t, seq.add(blk, ""))
 	}
 
 	// Asking for successors for blk 0 should give us blocks 1-3
 	blk := helper.RandomBlock(0, 1)
-	successors := seq.provideSuccessors(*blk)
+	successors := seq.provideSuccessors(sequencedBlock{Block: *blk})
 
 	assert.True(t, len(successors) == 4)
-	assert.True(t, blk.Equals(&successors[0]))
+	assert.True(t, blk.Equals(&successors[0].Block))
 
 	for i := 1; i < 4; i++ {
 		b := &blks[i-1]
-		assert.True(t, b.Equals(&successors[i]))
+		assert.True(t, b.Equals(&successors[i].Block))
 	}
 
 	// sequencer should only have block 5 and 6
@@ -58,13 +59,49 @@ func TestSequencer(t *testing.T) {
 	}
 }
 
+func TestSequencerBounds(t *testing.T) {
+	conf := config.Get()
+
+	r := conf
+	r.Sync.SequencerMaxBlocks = 3
+	config.Mock(&r)
+
+	defer config.Mock(&conf)
+
+	seq := newSequencer()
+
+	for i := uint64(5); i < 8; i++ {
+		assert.NoError(t, seq.add(*helper.RandomBlock(i, 1), ""))
+	}
+
+	// The highest blocks are kept out when full
+	assert.Equal(t, errSequencerFull, seq.add(*helper.RandomBlock(8, 1), ""))
+
+	// Lower blocks evict the highest one
+	assert.NoError(t, seq.add(*helper.RandomBlock(4, 1), "peer"))
+	assert.Equal(t, 3, seq.len())
+	assert.Empty(t, seq.blockPool[7])
+
+	blk, err := seq.get(4)
+	assert.NoError(t, err)
+	assert.Equal(t, "peer", blk.src)
+
+	// Bytes are bounded too
+	seq.maxBytes = seq.size
+	assert.Equal(t, errSequencerFull, seq.add(*helper.RandomBlock(9, 1), ""))
+
+	seq.cleanup(6)
+	assert.Equal(t, 1, seq.len())
+	assert.Equal(t, seq.blockPool[6].size, seq.size)
+}
+
 func TestSequencerConcurrency(t *testing.T) {
 	seq := newSequencer()
 
 	// Populate sequencer with 100 blocks
 	for i := 1; i <= 100; i++ {
 		blk := helper.RandomBlock(uint64(i), 1)
-		seq.add(*blk)
+		assert.NoError(t, seq.add(*blk, ""))
 	}
 
 	var wg sync.WaitGroup
@@ -76,7 +113,7 @@ func TestSequencerConcurrency(t *testing.T) {
 		for i := 101; i < 10000; i++ {
 			blk := block.NewBlock()
 			blk.Header.Height = uint64(i)
-			seq.add(*blk)
+			_ = seq.add(*blk, "")
 		}
 		wg.Done()
 	}()
@@ -86,7 +123,7 @@ func TestSequencerConcurrency(t *testing.T) {
 		for i := 100; i >= 1; i-- {
 			blk := block.NewBlock()
 			blk.Header.Height = uint64(i)
-			_ = seq.provideSuccessors(*blk)
+			_ = seq.provideSuccessors(sequencedBlock{Block: *blk})
 		}
 		wg.Done()
 	}()
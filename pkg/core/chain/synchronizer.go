@@ -9,6 +9,7 @@ package chain
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
@@ -20,6 +21,8 @@ import (
 
 const (
 	syncTimeout = time.Duration(5) * time.Second
+
+	defaultMaxBlocksAhead = 100
 )
 
 type syncState func(srcPeerAddr string, currentHeight uint64, blk block.Block, kadcastHeight byte) ([]bytes.Buffer, error)
@@ -28,7 +31,18 @@ func (s *synchronizer) inSync(srcPeerAddr string, currentHeight uint64, blk bloc
 	if blk.Header.Height > currentHeight+1 {
 		// If this block is from far in the future, we should start syncing mode.
 		s.chain.StopBlockProduction()
-		s.sequencer.add(blk)
+		s.stats.started(srcPeerAddr, currentHeight)
+
+		if err := s.sequencer.add(blk, srcPeerAddr); err != nil {
+			log.WithError(err).WithField("height", blk.Header.Height).Debug("could not add block to the sequencer")
+		}
+
+		if s.manager != nil {
+			log.WithField("state", "outSync").Traceln("change sync state")
+
+			s.state = s.outSync
+			return nil, s.startParallelSync(srcPeerAddr, blk.Header.Height, currentHeight)
+		}
 
 		// Trigger timeout outSync timer. If the peer initiating the Sync
 		// procedure is dishonest, this timer should switch back to InSync
@@ -52,6 +66,8 @@ func (s *synchronizer) inSync(srcPeerAddr string, currentHeight uint64, blk bloc
 			WithField("state", "insync").
 			WithError(err).
 			Debug("could not AcceptBlock")
+
+		s.reportBadBlock(srcPeerAddr, err)
 		return nil, err
 	}
 
@@ -61,35 +77,63 @@ func (s *synchronizer) inSync(srcPeerAddr string, currentHeight uint64, blk bloc
 func (s *synchronizer) outSync(srcPeerAddr string, currentHeight uint64, blk block.Block, kadcastHeight byte) ([]bytes.Buffer, error) {
 	var err error
 
+	if s.manager != nil {
+		s.manager.onBlock(blk)
+	}
+
+	if blk.Header.Height > s.syncTarget+s.maxBlocksAhead {
+		log.WithField("height", blk.Header.Height).
+			WithField("target", s.syncTarget).
+			WithField("src_addr", srcPeerAddr).
+			Debug("discarded block too far ahead of the sync target")
+		return nil, nil
+	}
+
+	next := sequencedBlock{Block: blk, src: srcPeerAddr}
+
 	if blk.Header.Height > currentHeight+1 {
 		// if there is a gap we add the future block to the sequencer
-		s.sequencer.add(blk)
+		if err = s.sequencer.add(blk, srcPeerAddr); err != nil {
+			log.WithError(err).WithField("height", blk.Header.Height).Debug("could not add block to the sequencer")
+		}
 
-		blk, err = s.sequencer.get(currentHeight + 1)
+		next, err = s.sequencer.get(currentHeight + 1)
 		if err != nil {
 			return nil, nil
 		}
 	}
 
 	// Retrieve all successive blocks that need to be accepted
-	blks := s.sequencer.provideSuccessors(blk)
+	blks := s.sequencer.provideSuccessors(next)
 
 	for _, blk := range blks {
 		// append them all to the ledger
-		if err = s.chain.TryNextConsecutiveBlockOutSync(blk, kadcastHeight); err != nil {
+		if err = s.chain.TryNextConsecutiveBlockOutSync(blk.Block, kadcastHeight); err != nil {
 			log.WithError(err).WithField("state", "outSync").Debug("could not AcceptBlock")
+			s.stats.failed(err)
+			s.reportBadBlock(blk.src, err)
+
+			if s.manager != nil {
+				// Request the block again, possibly to another peer
+				s.manager.requeue(blk.Header.Height)
+			}
+
 			return nil, err
 		}
 
-		// Peer does provide a valid consecutive block
-		// outSyncTimer should restart its counter
-		if err = s.timer.Reset(srcPeerAddr); err != nil {
+		if s.manager != nil {
+			s.manager.progress(blk.Header.Height)
+		} else if err = s.timer.Reset(srcPeerAddr); err != nil {
+			// Peer does provide a valid consecutive block
+			// outSyncTimer should restart its counter
 			log.WithError(err).WithField("state", "outSync").Warn("timer error")
 		}
 
 		if blk.Header.Height == s.syncTarget {
 			// Sync Target reached. outSyncTimer is not anymore needed
 			s.timer.Cancel()
+			s.stopParallelSync()
+			s.stats.stopped()
 
 			// if we reach the target we get into sync mode
 			// and trigger the consensus again
@@ -118,14 +162,40 @@ type synchronizer struct {
 	syncTarget uint64
 
 	timer *outSyncTimer
+
+	// manager drives the header-first sync from multiple peers. If nil, the
+	// blocks are requested to a single peer with GetBlocks.
+	manager *syncManager
+
+	stats syncStats
+
+	// maxBlocksAhead bounds the height of the blocks accepted above the sync
+	// target.
+	maxBlocksAhead uint64
+
+	// reporter lowers the reputation of the peers supplying invalid blocks.
+	// If nil, misbehaving peers are not reported.
+	reporter PeerReporter
+}
+
+// PeerReporter penalizes misbehaving peers.
+type PeerReporter interface {
+	ReportInvalidBlock(addr string)
 }
 
 // newSynchronizer returns an initialized synchronizer, ready for use.
 func newSynchronizer(db database.DB, chain Ledger) *synchronizer {
+	conf := config.Get().Sync
+
 	s := &synchronizer{
-		db:        db,
-		sequencer: newSequencer(),
-		chain:     chain,
+		db:             db,
+		sequencer:      newSequencer(),
+		chain:          chain,
+		maxBlocksAhead: conf.MaxBlocksAhead,
+	}
+
+	if s.maxBlocksAhead == 0 {
+		s.maxBlocksAhead = defaultMaxBlocksAhead
 	}
 
 	s.timer = newSyncTimer(syncTimeout, chain.ProcessSyncTimerExpired)
@@ -170,6 +240,76 @@ func (s *synchronizer) startSync(strPeerAddr string, tipHeight, currentHeight ui
 	return marshalGetBlocks(msgGetBlocks)
 }
 
+// startParallelSync fetches the headers up to tipHeight from srcPeerAddr,
+// and the blocks from all the connected peers.
+func (s *synchronizer) startParallelSync(srcPeerAddr string, tipHeight, currentHeight uint64) error {
+	// The target is extended as more headers are fetched
+	s.syncTarget = tipHeight
+
+	log.WithField("curr", currentHeight).
+		WithField("tip", tipHeight).
+		WithField("src_addr", srcPeerAddr).
+		Info("Start parallel syncing")
+
+	var tip *block.Header
+
+	if err := s.db.View(func(t database.Transaction) error {
+		hash, err := t.FetchBlockHashByHeight(currentHeight)
+		if err != nil {
+			return err
+		}
+
+		tip, err = t.FetchBlockHeader(hash)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	s.manager.start(srcPeerAddr, tip)
+	return nil
+}
+
+// stopParallelSync stops the header-first sync, if enabled.
+func (s *synchronizer) stopParallelSync() {
+	if s.manager != nil {
+		s.manager.stop()
+	}
+}
+
+// processHeaders handles the headers requested by the header-first sync.
+func (s *synchronizer) processHeaders(srcPeerAddr string, headers []*block.Header) error {
+	if s.manager == nil {
+		return nil
+	}
+
+	last, err := s.manager.processHeaders(srcPeerAddr, headers)
+	if err != nil {
+		s.stats.failed(err)
+		return err
+	}
+
+	if last > s.syncTarget {
+		s.syncTarget = last
+	}
+
+	return nil
+}
+
+// reportBadBlock reports an invalid block supplied by srcPeerAddr. Errors
+// not due to the block itself are not attributed to the peer.
+func (s *synchronizer) reportBadBlock(srcPeerAddr string, err error) {
+	var invalid *InvalidBlockError
+	if srcPeerAddr == "" || s.reporter == nil || !errors.As(err, &invalid) {
+		return
+	}
+
+	log.WithField("src_addr", srcPeerAddr).
+		WithError(err).
+		Debug("reporting peer supplying an invalid block")
+
+	s.reporter.ReportInvalidBlock(srcPeerAddr)
+}
+
 func (s *synchronizer) setSyncTarget(tipHeight, maxHeight uint64) {
 	s.syncTarget = tipHeight
 	if tipHeight > maxHeight {
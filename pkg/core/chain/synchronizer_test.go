@@ -7,6 +7,7 @@
 package chain
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
@@ -50,6 +51,47 @@ func TestFutureBlocks(t *testing.T) {
 	assert.NotEmpty(s.sequencer.blockPool[height])
 }
 
+func TestFarFutureBlocks(t *testing.T) {
+	assert := assert.New(t)
+	s, _ := setupSynchronizerTest()
+
+	_, err := s.processBlock("", 0, *helper.RandomBlock(10, 1), 0)
+	assert.NoError(err)
+
+	// Blocks too far ahead of the sync target are discarded
+	height := s.syncTarget + s.maxBlocksAhead + 1
+	_, err = s.processBlock("", 0, *helper.RandomBlock(height, 1), 0)
+	assert.NoError(err)
+	assert.Empty(s.sequencer.blockPool[height])
+
+	_, err = s.processBlock("", 0, *helper.RandomBlock(height-1, 1), 0)
+	assert.NoError(err)
+	assert.NotEmpty(s.sequencer.blockPool[height-1])
+}
+
+func TestReportBadBlock(t *testing.T) {
+	assert := assert.New(t)
+	s, _ := setupSynchronizerTest()
+
+	reporter := &mockReporter{}
+	s.reporter = reporter
+
+	// Errors of the node itself are not attributed to the peer
+	s.reportBadBlock("peer", errors.New("executor unreachable"))
+	assert.Empty(reporter.reported)
+
+	s.reportBadBlock("peer", &InvalidBlockError{errors.New("invalid")})
+	assert.Equal([]string{"peer"}, reporter.reported)
+}
+
+type mockReporter struct {
+	reported []string
+}
+
+func (r *mockReporter) ReportInvalidBlock(addr string) {
+	r.reported = append(r.reported, addr)
+}
+
 func setupSynchronizerTest() (*synchronizer, chan consensus.Results) {
 	c := make(chan consensus.Results, 1)
 	m := &mockChain{tipHeight: 0, catchBlockChan: c}
@@ -0,0 +1,489 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+const (
+	defaultSyncBatchSize      = 50
+	defaultSyncRequestTimeout = 10 * time.Second
+)
+
+// Peers gives the syncManager access to the connected peers.
+type Peers interface {
+	// FullNodes returns the addresses of the connected full nodes.
+	FullNodes() []string
+	// SendTo puts a marshaled wire message on the outgoing queue of a peer.
+	SendTo(addr string, buf bytes.Buffer) error
+}
+
+// blockRequest is a batch of blocks requested to a single peer.
+type blockRequest struct {
+	heights  map[uint64]struct{}
+	deadline time.Time
+}
+
+// syncManager drives the header-first sync. The headers following the chain
+// tip are fetched from the peer which triggered the sync, then the blocks are
+// requested in batches to several peers in parallel. Batches not delivered in
+// time are requested to another peer. Delivered blocks are ordered by the
+// sequencer, as in the single-peer sync.
+//
+// The syncManager is safe for concurrent use. It must not be called with its
+// own lock held by the onStalled callback, which is expected to acquire the
+// Chain lock.
+type syncManager struct {
+	lock sync.Mutex
+
+	peers     Peers
+	workers   int
+	batchSize uint64
+	timeout   time.Duration
+	// onStalled is called when no connected peer can deliver the missing
+	// headers or blocks.
+	onStalled func(strPeerAddr string) error
+
+	active bool
+	quit   chan struct{}
+
+	// headerPeer provides the headers. A zero headerDeadline means that no
+	// headers request is in flight.
+	headerPeer     string
+	headerDeadline time.Time
+	// last is the last fetched header, more is true if the headerPeer can
+	// provide more headers after it.
+	last *block.Header
+	more bool
+
+	// headers are the fetched headers above the chain tip, by height.
+	headers map[uint64]*block.Header
+	// queue holds the sorted heights of the blocks to be requested.
+	queue []uint64
+	// requests in flight, by peer address.
+	requests map[string]*blockRequest
+	// stalled are the peers which failed to deliver during this sync.
+	stalled map[string]struct{}
+
+	tipHeight uint64
+}
+
+func newSyncManager(peers Peers, onStalled func(string) error) *syncManager {
+	conf := config.Get().Sync
+
+	m := &syncManager{
+		peers:     peers,
+		workers:   conf.Workers,
+		batchSize: conf.BatchSize,
+		timeout:   time.Duration(conf.RequestTimeout) * time.Second,
+		onStalled: onStalled,
+	}
+
+	if m.batchSize == 0 {
+		m.batchSize = defaultSyncBatchSize
+	}
+
+	if m.timeout == 0 {
+		m.timeout = defaultSyncRequestTimeout
+	}
+
+	return m
+}
+
+// start a sync from the chain tip, fetching the headers from srcPeerAddr.
+func (m *syncManager) start(srcPeerAddr string, tip *block.Header) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.active {
+		close(m.quit)
+	}
+
+	m.active = true
+	m.quit = make(chan struct{})
+	m.last = tip
+	m.more = true
+	m.tipHeight = tip.Height
+	m.headers = make(map[uint64]*block.Header)
+	m.queue = nil
+	m.requests = make(map[string]*blockRequest)
+	m.stalled = make(map[string]struct{})
+
+	m.requestHeaders(srcPeerAddr)
+
+	go m.timeoutLoop(m.quit)
+}
+
+// stop the sync in progress, if any.
+func (m *syncManager) stop() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !m.active {
+		return
+	}
+
+	m.active = false
+	close(m.quit)
+
+	m.headers = nil
+	m.queue = nil
+	m.requests = nil
+	m.stalled = nil
+}
+
+// processHeaders appends the headers received from srcPeerAddr to the fetched
+// ones, and returns the height of the last fetched header.
+func (m *syncManager) processHeaders(srcPeerAddr string, headers []*block.Header) (uint64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !m.active || srcPeerAddr != m.headerPeer || m.headerDeadline.IsZero() {
+		// Unsolicited headers
+		return 0, nil
+	}
+
+	m.headerDeadline = time.Time{}
+
+	if err := verifyHeaders(m.last, headers); err != nil {
+		m.stalled[srcPeerAddr] = struct{}{}
+		m.requestHeaders("")
+
+		return 0, err
+	}
+
+	for _, hdr := range headers {
+		m.headers[hdr.Height] = hdr
+		m.queue = append(m.queue, hdr.Height)
+	}
+
+	if len(headers) > 0 {
+		m.last = headers[len(headers)-1]
+	}
+
+	m.more = len(headers) == message.MaxHeaders
+
+	log.WithField("src_addr", srcPeerAddr).
+		WithField("count", len(headers)).
+		WithField("last", m.last.Height).
+		Debug("headers fetched")
+
+	m.dispatch()
+	return m.last.Height, nil
+}
+
+// onBlock marks a block as delivered.
+func (m *syncManager) onBlock(blk block.Block) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !m.active {
+		return
+	}
+
+	hdr, ok := m.headers[blk.Header.Height]
+	if !ok || !bytes.Equal(hdr.Hash, blk.Header.Hash) {
+		return
+	}
+
+	m.delivered(blk.Header.Height)
+	m.dispatch()
+}
+
+// progress notifies the new chain tip, sliding the download window.
+func (m *syncManager) progress(tipHeight uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !m.active {
+		return
+	}
+
+	for height := m.tipHeight + 1; height <= tipHeight; height++ {
+		delete(m.headers, height)
+		m.delivered(height)
+	}
+
+	for len(m.queue) > 0 && m.queue[0] <= tipHeight {
+		m.queue = m.queue[1:]
+	}
+
+	m.tipHeight = tipHeight
+	m.dispatch()
+}
+
+// requeue requests again a block which could not be accepted.
+func (m *syncManager) requeue(height uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !m.active {
+		return
+	}
+
+	if _, ok := m.headers[height]; !ok {
+		return
+	}
+
+	m.delivered(height)
+
+	i := sort.Search(len(m.queue), func(i int) bool { return m.queue[i] >= height })
+	if i == len(m.queue) || m.queue[i] != height {
+		m.queue = append(m.queue, 0)
+		copy(m.queue[i+1:], m.queue[i:])
+		m.queue[i] = height
+	}
+
+	m.dispatch()
+}
+
+// delivered removes a height from the requests in flight.
+func (m *syncManager) delivered(height uint64) {
+	for addr, r := range m.requests {
+		delete(r.heights, height)
+
+		if len(r.heights) == 0 {
+			delete(m.requests, addr)
+		}
+	}
+}
+
+// dispatch requests the queued blocks to the idle peers, up to the
+// configured number of workers. Only blocks within the download window above
+// the chain tip are requested, to bound the sequencer size.
+func (m *syncManager) dispatch() {
+	if m.more && m.headerDeadline.IsZero() && len(m.queue) < message.MaxHeaders {
+		m.requestHeaders(m.headerPeer)
+	}
+
+	maxHeight := m.tipHeight + uint64(m.workers)*m.batchSize*2
+
+	for _, addr := range m.available() {
+		if len(m.requests) >= m.workers || len(m.queue) == 0 || m.queue[0] > maxHeight {
+			return
+		}
+
+		inv := &message.Inv{}
+		r := &blockRequest{heights: make(map[uint64]struct{}), deadline: time.Now().Add(m.timeout)}
+
+		n := 0
+		for n < len(m.queue) && uint64(n) < m.batchSize && m.queue[n] <= maxHeight {
+			height := m.queue[n]
+
+			inv.AddItem(message.InvTypeBlock, m.headers[height].Hash)
+			r.heights[height] = struct{}{}
+			n++
+		}
+
+		if err := m.send(addr, topics.GetData, inv); err != nil {
+			log.WithError(err).WithField("src_addr", addr).Warn("could not request blocks")
+			m.stalled[addr] = struct{}{}
+
+			continue
+		}
+
+		m.queue = m.queue[n:]
+		m.requests[addr] = r
+	}
+}
+
+// available returns the connected peers which are not stalled nor busy.
+func (m *syncManager) available() []string {
+	addrs := make([]string, 0)
+
+	for _, addr := range m.peers.FullNodes() {
+		if _, ok := m.stalled[addr]; ok {
+			continue
+		}
+
+		if _, ok := m.requests[addr]; ok {
+			continue
+		}
+
+		addrs = append(addrs, addr)
+	}
+
+	sort.Strings(addrs)
+	return addrs
+}
+
+// requestHeaders requests the headers following the last fetched one to
+// addr. If addr is empty or stalled, another peer is chosen.
+func (m *syncManager) requestHeaders(addr string) {
+	m.headerDeadline = time.Time{}
+
+	candidates := m.available()
+	if addr != "" {
+		if _, ok := m.stalled[addr]; !ok {
+			candidates = append([]string{addr}, candidates...)
+		}
+	}
+
+	for _, candidate := range candidates {
+		getHeaders := &message.GetBlocks{Locators: [][]byte{m.last.Hash}}
+
+		if err := m.send(candidate, topics.GetHeaders, getHeaders); err != nil {
+			log.WithError(err).WithField("src_addr", candidate).Warn("could not request headers")
+			m.stalled[candidate] = struct{}{}
+
+			continue
+		}
+
+		m.headerPeer = candidate
+		m.headerDeadline = time.Now().Add(m.timeout)
+
+		return
+	}
+}
+
+func (m *syncManager) send(addr string, topic topics.Topic, msg interface{ Encode(*bytes.Buffer) error }) error {
+	buf := topic.ToBuffer()
+	if err := msg.Encode(&buf); err != nil {
+		return err
+	}
+
+	return m.peers.SendTo(addr, buf)
+}
+
+// timeoutLoop periodically re-assigns the requests not delivered in time.
+func (m *syncManager) timeoutLoop(quit chan struct{}) {
+	ticker := time.NewTicker(m.timeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if stalledPeer, stalled := m.checkTimeouts(); stalled {
+				log.WithField("src_addr", stalledPeer).Warn("no peer can deliver the missing blocks")
+
+				if err := m.onStalled(stalledPeer); err != nil {
+					log.WithError(err).Warn("sync stalled callback err")
+				}
+
+				return
+			}
+		case <-quit:
+			return
+		}
+	}
+}
+
+// checkTimeouts re-assigns the expired requests. It returns true if the sync
+// cannot progress anymore.
+func (m *syncManager) checkTimeouts() (string, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !m.active {
+		return "", false
+	}
+
+	now := time.Now()
+
+	if !m.headerDeadline.IsZero() && now.After(m.headerDeadline) {
+		log.WithField("src_addr", m.headerPeer).Debug("headers request timed out")
+
+		m.stalled[m.headerPeer] = struct{}{}
+		m.requestHeaders("")
+	}
+
+	for addr, r := range m.requests {
+		if now.Before(r.deadline) {
+			continue
+		}
+
+		log.WithField("src_addr", addr).
+			WithField("missing", len(r.heights)).
+			Debug("blocks request timed out")
+
+		m.stalled[addr] = struct{}{}
+		delete(m.requests, addr)
+
+		for height := range r.heights {
+			m.queue = append(m.queue, height)
+		}
+	}
+
+	sort.Slice(m.queue, func(i, j int) bool { return m.queue[i] < m.queue[j] })
+
+	m.dispatch()
+
+	// Without any request in flight, the sync is either complete, waiting for
+	// the delivered blocks to be accepted, or bounded by the download window.
+	// It is stalled only if blocks are still missing and no peer is left to
+	// request them to.
+	if len(m.requests) > 0 || !m.headerDeadline.IsZero() {
+		return "", false
+	}
+
+	missing := len(m.queue) > 0 || len(m.headers) > 0 || m.more
+	if missing && len(m.available()) == 0 {
+		return m.headerPeer, true
+	}
+
+	return "", false
+}
+
+// sources returns the peer providing the headers, and the peers with blocks
+// requests in flight.
+func (m *syncManager) sources() (string, []string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !m.active {
+		return "", nil
+	}
+
+	addrs := make([]string, 0, len(m.requests))
+	for addr := range m.requests {
+		addrs = append(addrs, addr)
+	}
+
+	sort.Strings(addrs)
+	return m.headerPeer, addrs
+}
+
+// verifyHeaders checks that the headers are consecutive, and link to prev.
+func verifyHeaders(prev *block.Header, headers []*block.Header) error {
+	if len(headers) > message.MaxHeaders {
+		return errors.New("too many headers")
+	}
+
+	for _, hdr := range headers {
+		if hdr.Height != prev.Height+1 {
+			return fmt.Errorf("header height %d does not follow %d", hdr.Height, prev.Height)
+		}
+
+		if !bytes.Equal(hdr.PrevBlockHash, prev.Hash) {
+			return fmt.Errorf("header %d does not link to the previous one", hdr.Height)
+		}
+
+		hash, err := hdr.CalculateHash()
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(hash, hdr.Hash) {
+			return fmt.Errorf("header %d hash mismatch", hdr.Height)
+		}
+
+		prev = hdr
+	}
+
+	return nil
+}
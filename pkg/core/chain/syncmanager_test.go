@@ -0,0 +1,254 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestSyncManager(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.Get()
+
+	r := conf
+	r.Sync.Workers = 2
+	r.Sync.BatchSize = 2
+	r.Sync.RequestTimeout = 60
+	config.Mock(&r)
+
+	defer config.Mock(&conf)
+
+	peers := &mockPeers{addrs: []string{"a", "b", "c"}, sent: make(map[string][]message.Message)}
+
+	var stalled string
+
+	m := newSyncManager(peers, func(addr string) error {
+		stalled = addr
+		return nil
+	})
+
+	blocks := linkedBlocks(t, 6)
+
+	m.start("a", blocks[0].Header)
+	defer m.stop()
+
+	assert.Equal(topics.GetHeaders, peers.last("a").Category())
+
+	headers := make([]*block.Header, 0)
+	for _, blk := range blocks[1:] {
+		headers = append(headers, blk.Header)
+	}
+
+	// Headers from another peer are ignored
+	last, err := m.processHeaders("b", headers)
+	assert.NoError(err)
+	assert.Zero(last)
+
+	last, err = m.processHeaders("a", headers)
+	assert.NoError(err)
+	assert.Equal(uint64(5), last)
+
+	// Blocks are requested in batches to the workers
+	assert.Equal([]uint64{1, 2}, peers.requested("a", blocks))
+	assert.Equal([]uint64{3, 4}, peers.requested("b", blocks))
+	assert.Nil(peers.last("c"))
+
+	// A delivered batch makes room for the next one
+	m.onBlock(*blocks[1])
+	m.onBlock(*blocks[2])
+	m.progress(2)
+
+	assert.Equal([]uint64{5}, peers.requested("a", blocks))
+
+	// An expired batch is requested to another peer
+	m.lock.Lock()
+	m.requests["b"].deadline = time.Now().Add(-time.Second)
+	m.lock.Unlock()
+
+	_, isStalled := m.checkTimeouts()
+	assert.False(isStalled)
+	assert.Equal([]uint64{3, 4}, peers.requested("c", blocks))
+
+	// A block which cannot be accepted is requested again
+	m.onBlock(*blocks[3])
+	m.onBlock(*blocks[4])
+	m.requeue(3)
+
+	assert.Equal([]uint64{3}, peers.requested("c", blocks))
+
+	// Without any available peer, the sync stalls
+	m.lock.Lock()
+	m.requests["a"].deadline = time.Now().Add(-time.Second)
+	m.requests["c"].deadline = time.Now().Add(-time.Second)
+	m.lock.Unlock()
+
+	addr, isStalled := m.checkTimeouts()
+	assert.True(isStalled)
+	assert.Equal("a", addr)
+	assert.Empty(stalled)
+}
+
+func TestSyncManagerNotStalled(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.Get()
+
+	r := conf
+	r.Sync.Workers = 1
+	r.Sync.BatchSize = 1
+	r.Sync.RequestTimeout = 60
+	config.Mock(&r)
+
+	defer config.Mock(&conf)
+
+	peers := &mockPeers{addrs: []string{"a"}, sent: make(map[string][]message.Message)}
+
+	m := newSyncManager(peers, func(string) error {
+		return nil
+	})
+
+	blocks := linkedBlocks(t, 6)
+
+	m.start("a", blocks[0].Header)
+	defer m.stop()
+
+	headers := make([]*block.Header, 0)
+	for _, blk := range blocks[1:] {
+		headers = append(headers, blk.Header)
+	}
+
+	_, err := m.processHeaders("a", headers)
+	assert.NoError(err)
+
+	m.onBlock(*blocks[1])
+	m.onBlock(*blocks[2])
+
+	// The download window is full until the tip moves
+	assert.Equal([]uint64{2}, peers.requested("a", blocks))
+
+	_, isStalled := m.checkTimeouts()
+	assert.False(isStalled)
+
+	// Once all the blocks are accepted, nothing is missing even without any
+	// available peer
+	m.progress(5)
+
+	m.lock.Lock()
+	m.stalled["a"] = struct{}{}
+	m.lock.Unlock()
+
+	_, isStalled = m.checkTimeouts()
+	assert.False(isStalled)
+}
+
+func TestVerifyHeaders(t *testing.T) {
+	assert := assert.New(t)
+
+	blocks := linkedBlocks(t, 4)
+
+	headers := []*block.Header{blocks[1].Header, blocks[2].Header, blocks[3].Header}
+	assert.NoError(verifyHeaders(blocks[0].Header, headers))
+
+	// Gap
+	assert.Error(verifyHeaders(blocks[0].Header, headers[1:]))
+
+	// Not linked
+	assert.Error(verifyHeaders(blocks[1].Header, []*block.Header{helper.RandomBlock(2, 1).Header}))
+
+	// Tampered
+	tampered := blocks[1].Header.Copy()
+	tampered.Timestamp++
+	assert.Error(verifyHeaders(blocks[0].Header, []*block.Header{tampered}))
+}
+
+// linkedBlocks returns a chain of blocks starting at height 0.
+func linkedBlocks(t *testing.T, n int) []*block.Block {
+	blocks := make([]*block.Block, n)
+
+	for i := range blocks {
+		blk := helper.RandomBlock(uint64(i), 1)
+
+		if i > 0 {
+			blk.Header.PrevBlockHash = blocks[i-1].Header.Hash
+
+			hash, err := blk.CalculateHash()
+			assert.NoError(t, err)
+
+			blk.Header.Hash = hash
+		}
+
+		blocks[i] = blk
+	}
+
+	return blocks
+}
+
+type mockPeers struct {
+	lock  sync.Mutex
+	addrs []string
+	sent  map[string][]message.Message
+}
+
+func (p *mockPeers) FullNodes() []string {
+	return p.addrs
+}
+
+func (p *mockPeers) SendTo(addr string, buf bytes.Buffer) error {
+	msg, err := message.Unmarshal(&buf)
+	if err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.sent[addr] = append(p.sent[addr], msg)
+	return nil
+}
+
+func (p *mockPeers) last(addr string) message.Message {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if len(p.sent[addr]) == 0 {
+		return nil
+	}
+
+	return p.sent[addr][len(p.sent[addr])-1]
+}
+
+// requested returns the heights of the blocks requested by the last GetData
+// sent to addr.
+func (p *mockPeers) requested(addr string, blocks []*block.Block) []uint64 {
+	msg := p.last(addr)
+	if msg == nil || msg.Category() != topics.GetData {
+		return nil
+	}
+
+	heights := make([]uint64, 0)
+
+	for _, item := range msg.Payload().(message.Inv).InvList {
+		for _, blk := range blocks {
+			if bytes.Equal(blk.Header.Hash, item.Hash) {
+				heights = append(heights, blk.Header.Height)
+			}
+		}
+	}
+
+	return heights
+}
@@ -0,0 +1,200 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"context"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/server"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"google.golang.org/grpc"
+)
+
+const (
+	// SyncStateInSync means that the node follows the chain tip, and runs the
+	// consensus.
+	SyncStateInSync = "insync"
+	// SyncStateOutSync means that the node is downloading missing blocks.
+	SyncStateOutSync = "outsync"
+)
+
+// SyncStatus reports the progress of the synchronization with the network.
+type SyncStatus struct {
+	State string `json:"state"`
+	// Height of the chain tip.
+	Height uint64 `json:"height"`
+	// Target is the height at which the current sync ends. It is zero while
+	// in sync.
+	Target uint64 `json:"target"`
+	// HighestSeen is the highest block height received from the network.
+	HighestSeen uint64 `json:"highestSeen"`
+	// Progress is the percentage of Height over HighestSeen.
+	Progress float64 `json:"progress"`
+	// Peer is the peer the sync has been started from. With the header-first
+	// sync, it is the peer providing the headers.
+	Peer string `json:"peer,omitempty"`
+	// Peers are the peers with blocks requests in flight, with the
+	// header-first sync only.
+	Peers []string `json:"peers,omitempty"`
+	// BlocksPerSecond is the average rate of the blocks accepted since the
+	// start of the current sync.
+	BlocksPerSecond float64 `json:"blocksPerSecond"`
+	// ETA is the estimated number of seconds to reach the target. It is zero
+	// if unknown.
+	ETA int64 `json:"eta"`
+	// SequencerBacklog is the number of blocks received ahead of the chain
+	// tip, waiting for the missing ones.
+	SequencerBacklog int `json:"sequencerBacklog"`
+	// LastError is the last error which interrupted or slowed down a sync.
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorTime is the unix time of LastError.
+	LastErrorTime int64 `json:"lastErrorTime,omitempty"`
+}
+
+// syncStats tracks the current sync, and the last sync error.
+type syncStats struct {
+	syncing     bool
+	peer        string
+	start       time.Time
+	startHeight uint64
+
+	lastErr     string
+	lastErrTime time.Time
+}
+
+func (s *syncStats) started(peer string, currentHeight uint64) {
+	s.syncing = true
+	s.peer = peer
+	s.start = time.Now()
+	s.startHeight = currentHeight
+}
+
+func (s *syncStats) stopped() {
+	s.syncing = false
+}
+
+func (s *syncStats) failed(err error) {
+	s.lastErr = err.Error()
+	s.lastErrTime = time.Now()
+}
+
+// SyncStatus returns the current status of the synchronization.
+func (c *Chain) SyncStatus() SyncStatus {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	height := c.tip.Header.Height
+
+	status := SyncStatus{
+		State:            SyncStateInSync,
+		Height:           height,
+		HighestSeen:      c.highestSeen,
+		Progress:         c.syncProgress(),
+		SequencerBacklog: c.sequencer.len(),
+		LastError:        c.stats.lastErr,
+	}
+
+	if !c.stats.lastErrTime.IsZero() {
+		status.LastErrorTime = c.stats.lastErrTime.Unix()
+	}
+
+	if !c.stats.syncing {
+		return status
+	}
+
+	status.State = SyncStateOutSync
+	status.Target = c.syncTarget
+	status.Peer = c.stats.peer
+
+	if c.manager != nil {
+		status.Peer, status.Peers = c.manager.sources()
+	}
+
+	elapsed := time.Since(c.stats.start).Seconds()
+	if elapsed > 0 && height > c.stats.startHeight {
+		status.BlocksPerSecond = float64(height-c.stats.startHeight) / elapsed
+	}
+
+	if status.BlocksPerSecond > 0 && c.syncTarget > height {
+		status.ETA = int64(float64(c.syncTarget-height) / status.BlocksPerSecond)
+	}
+
+	return status
+}
+
+// ServeSyncStatus answers the topics.GetSyncStatus requests of the rpcbus,
+// until the context is canceled.
+func (c *Chain) ServeSyncStatus(ctx context.Context, rpcBus *rpcbus.RPCBus) error {
+	reqChan := make(chan rpcbus.Request, 1)
+	if err := rpcBus.Register(topics.GetSyncStatus, reqChan); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case r := <-reqChan:
+				r.RespChan <- rpcbus.NewResponse(c.SyncStatus(), nil)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// GetSyncStatusMethod is the full gRPC method name of the sync status call.
+// It belongs to the node.Chain service, which is generated from
+// dusk-protobuf and has no such method yet, hence the node.SyncStatus
+// server.JSONService.
+const GetSyncStatusMethod = "/node.SyncStatus/GetSyncStatus"
+
+// SyncStatusRequest is the (empty) request of the sync status call.
+type SyncStatusRequest struct{}
+
+// SyncStatusServer is the server API of the node.SyncStatus service.
+type SyncStatusServer interface {
+	GetSyncStatus(context.Context, *SyncStatusRequest) (*SyncStatus, error)
+}
+
+var syncStatusService = server.JSONService{
+	Name:        "node.SyncStatus",
+	HandlerType: (*SyncStatusServer)(nil),
+	Methods: []server.JSONMethod{
+		{
+			Name:       "GetSyncStatus",
+			NewRequest: func() interface{} { return new(SyncStatusRequest) },
+			Call: func(srv interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.(SyncStatusServer).GetSyncStatus(ctx, req.(*SyncStatusRequest))
+			},
+		},
+	},
+}
+
+// RegisterSyncStatusServer registers the node.SyncStatus service.
+func RegisterSyncStatusServer(s *grpc.Server, srv SyncStatusServer) {
+	syncStatusService.Register(s, srv)
+}
+
+// GetSyncStatus implements SyncStatusServer.
+func (c *Chain) GetSyncStatus(_ context.Context, _ *SyncStatusRequest) (*SyncStatus, error) {
+	status := c.SyncStatus()
+	return &status, nil
+}
+
+// GetSyncStatus calls the node.SyncStatus service.
+func GetSyncStatus(ctx context.Context, cc grpc.ClientConnInterface) (*SyncStatus, error) {
+	status := new(SyncStatus)
+	if err := server.InvokeJSON(ctx, cc, GetSyncStatusMethod, &SyncStatusRequest{}, status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
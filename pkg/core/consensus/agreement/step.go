@@ -12,6 +12,7 @@ import (
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/candidate"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/journal"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
@@ -52,7 +53,13 @@ func (s *Loop) GetControlFn() consensus.ControlFn {
 func (s *Loop) Run(ctx context.Context, roundQueue *consensus.Queue, agreementChan <-chan message.Message, r consensus.RoundUpdate) consensus.Results {
 	// creating accumulator and handler
 	h := NewHandler(s.Keys, r.P)
-	acc := newAccumulator(h, WorkerAmount)
+
+	var accHandler Handler = h
+	if s.Journal != nil {
+		accHandler = &journaledHandler{Handler: h, journal: s.Journal}
+	}
+
+	acc := newAccumulator(accHandler, WorkerAmount)
 
 	// deferring queue cleanup at the end of the execution of this round
 	defer func() {
@@ -148,6 +155,7 @@ func (s *Loop) requestCandidate(ctx context.Context, hash []byte) (block.Block,
 func collectEvent(h *handler, accumulator *Accumulator, a message.Agreement, e *consensus.Emitter) {
 	hdr := a.State()
 	if !h.IsMember(hdr.PubKeyBLS, hdr.Round, hdr.Step) {
+		e.Journal.Received(message.New(topics.Agreement, a), journal.ErrNotMember)
 		return
 	}
 
@@ -158,3 +166,20 @@ func collectEvent(h *handler, accumulator *Accumulator, a message.Agreement, e *
 
 	accumulator.Process(a)
 }
+
+// journaledHandler records the outcome of the verification of the Agreement
+// messages into the consensus journal.
+type journaledHandler struct {
+	Handler
+	journal *journal.Journal
+}
+
+// Verify the Agreement, and record the result.
+func (h *journaledHandler) Verify(a message.Agreement) error {
+	// the verification can alter the signatures
+	cpy := a.Copy().(message.Agreement)
+
+	err := h.Handler.Verify(a)
+	h.journal.Received(message.New(topics.Agreement, cpy), err)
+	return err
+}
@@ -7,12 +7,18 @@
 package capi
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/asdine/storm/v3/q"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	"github.com/sirupsen/logrus"
@@ -233,3 +239,90 @@ func GetP2PCountHandler(res http.ResponseWriter, req *http.Request) {
 
 	_, _ = res.Write(b)
 }
+
+// GetTimeoutsHandler will return the current consensus step timeouts json.
+func GetTimeoutsHandler(res http.ResponseWriter, req *http.Request) {
+	writeRPCResponse(res, topics.GetConsensusTimeouts, rpcbus.EmptyRequest(), config.Get().Timeout.TimeoutGetConsensusTimeouts)
+}
+
+// GetQueuesHandler will return the occupation of the consensus channels and
+// queues json.
+func GetQueuesHandler(res http.ResponseWriter, req *http.Request) {
+	writeRPCResponse(res, topics.GetConsensusQueues, rpcbus.EmptyRequest(), config.Get().Timeout.TimeoutGetConsensusQueues)
+}
+
+// GetCommitteeHandler will return the voting committee json of a round and
+// step.
+func GetCommitteeHandler(res http.ResponseWriter, req *http.Request) {
+	round, err := strconv.ParseUint(req.URL.Query().Get("round"), 10, 64)
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	step, err := strconv.ParseUint(req.URL.Query().Get("step"), 10, 8)
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	log.WithField("round", round).WithField("step", step).Debug("GetCommitteeHandler")
+
+	params := new(bytes.Buffer)
+	if err := encoding.WriteUint64LE(params, round); err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if err := encoding.WriteUint8(params, uint8(step)); err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	writeRPCResponse(res, topics.GetCommittee, rpcbus.NewRequest(*params), config.Get().Timeout.TimeoutGetCommittee)
+}
+
+// GetEquivocationsHandler will return the equivocation evidence json.
+func GetEquivocationsHandler(res http.ResponseWriter, req *http.Request) {
+	writeRPCResponse(res, topics.GetEquivocations, rpcbus.EmptyRequest(), config.Get().Timeout.TimeoutGetEquivocations)
+}
+
+// writeRPCResponse writes the json of the response of a rpcbus call.
+// timeoutSecs is the timeout of the call, in seconds.
+func writeRPCResponse(res http.ResponseWriter, topic topics.Topic, r rpcbus.Request, timeoutSecs int64) {
+	if rpcBus == nil {
+		res.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	timeout := time.Duration(timeoutSecs) * time.Second
+	if timeout == 0 {
+		timeout = 3 * time.Second
+	}
+
+	resp, err := rpcBus.Call(topic, r, timeout)
+	if err != nil {
+		log.WithError(err).WithField("topic", topic.String()).Debug("rpcbus call failed")
+
+		var notExists *rpcbus.ErrMethodNotExists
+		if errors.Is(err, rpcbus.ErrRequestTimeout) || errors.As(err, &notExists) {
+			res.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		// the request was rejected by the component serving it
+		b, _ := json.Marshal(map[string]string{"error": err.Error()})
+
+		res.WriteHeader(http.StatusBadRequest)
+		_, _ = res.Write(b)
+		return
+	}
+
+	b, err := json.Marshal(resp)
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = res.Write(b)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/journal"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/key"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
@@ -45,6 +46,11 @@ type (
 		Keys        key.Keys
 		Proxy       transactions.Proxy
 		TimerLength time.Duration
+		// Journal records the events of the consensus. It can be nil.
+		Journal *journal.Journal
+		// Timeouts are the step timeouts adapted to the observed quorum
+		// delays. If nil, the steps use their own timer.
+		Timeouts *Timeouts
 	}
 
 	// RoundUpdate carries the data about the new Round, such as the active
@@ -99,6 +105,17 @@ func InitAcceptedBlockUpdate(subscriber eventbus.Subscriber) (chan block.Block,
 	return acceptedBlockChan, id
 }
 
+// InitRolledBackBlockUpdate subscribes to the RolledBackBlock topic and
+// returns a channel receiving each block removed by a chain rollback.
+func InitRolledBackBlockUpdate(subscriber eventbus.Subscriber) (chan block.Block, uint32) {
+	rolledBackBlockChan := make(chan block.Block, cfg.MaxInvBlocks)
+	collector := &acceptedBlockCollector{rolledBackBlockChan}
+	collectListener := eventbus.NewSafeCallbackListener(collector.Collect)
+	id := subscriber.Subscribe(topics.RolledBackBlock, collectListener)
+
+	return rolledBackBlockChan, id
+}
+
 // Collect as defined in the EventCollector interface. It reconstructs the bidList and notifies about it.
 func (c *acceptedBlockCollector) Collect(m message.Message) {
 	c.blockChan <- m.Payload().(block.Block)
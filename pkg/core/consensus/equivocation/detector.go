@@ -0,0 +1,355 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package equivocation
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/msg"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	logger "github.com/sirupsen/logrus"
+)
+
+var log = logger.WithField("process", "equivocation")
+
+const (
+	// keepRounds is the number of rounds, below the highest one observed,
+	// the votes are kept for.
+	keepRounds = 3
+	// maxEvidence bounds the evidence kept in memory.
+	maxEvidence = 100
+	// maxVotes bounds the votes kept in memory.
+	maxVotes = 100000
+)
+
+// Evidence is a pair of conflicting messages sent by the same provisioner
+// for the same round and step.
+type Evidence struct {
+	Topic     topics.Topic
+	PubKeyBLS []byte
+	Round     uint64
+	Step      uint8
+	// First and Second are the wire encodings of the conflicting messages.
+	First  []byte
+	Second []byte
+}
+
+// Provable returns true if the conflicting messages are signed by the
+// provisioner, so that the evidence can be verified by anyone. Score
+// messages are not, as the block hash of a Score is not signed.
+func (e Evidence) Provable() bool {
+	return e.Topic == topics.Reduction || e.Topic == topics.Agreement
+}
+
+// EvidenceJSON is the JSON representation of an Evidence, as served by the
+// API.
+type EvidenceJSON struct {
+	Topic string `json:"topic"`
+	// PubKeyBLS is the hex encoded BLS public key of the provisioner.
+	PubKeyBLS string `json:"pubKeyBLS"`
+	Round     uint64 `json:"round"`
+	Step      uint8  `json:"step"`
+	Provable  bool   `json:"provable"`
+	// First and Second are the hex encoded wire encodings of the
+	// conflicting messages.
+	First  string `json:"first"`
+	Second string `json:"second"`
+}
+
+// JSON returns the JSON representation of the evidence.
+func (e Evidence) JSON() EvidenceJSON {
+	return EvidenceJSON{
+		Topic:     e.Topic.String(),
+		PubKeyBLS: hex.EncodeToString(e.PubKeyBLS),
+		Round:     e.Round,
+		Step:      e.Step,
+		Provable:  e.Provable(),
+		First:     hex.EncodeToString(e.First),
+		Second:    hex.EncodeToString(e.Second),
+	}
+}
+
+type voteKey struct {
+	topic  topics.Topic
+	pubKey string
+	round  uint64
+	step   uint8
+}
+
+// Detector watches the consensus messages, and keeps the evidence of the
+// provisioners voting for different block hashes during the same round and
+// step.
+type Detector struct {
+	lock     sync.Mutex
+	votes    map[voteKey]message.Message
+	reported map[voteKey]bool
+	evidence []Evidence
+	round    uint64
+
+	// provisioners are the provisioners of the chain round. They are nil
+	// until the first Update, and the senders are not checked meanwhile.
+	provisioners *user.Provisioners
+}
+
+// NewDetector returns an initialized Detector.
+func NewDetector() *Detector {
+	return &Detector{
+		votes:    make(map[voteKey]message.Message),
+		reported: make(map[voteKey]bool),
+		evidence: make([]Evidence, 0),
+	}
+}
+
+// Update sets the round the chain is at, with its provisioners. From then
+// on, only the votes of the provisioners are kept, and the votes of the
+// rounds after the next one are ignored.
+func (d *Detector) Update(p user.Provisioners, round uint64) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.provisioners = &p
+	d.round = round
+	d.prune()
+}
+
+// Observe records a consensus message. It returns the evidence of an
+// equivocation if the message conflicts with one already observed.
+// Only the first evidence for each provisioner, round and step is
+// returned.
+//
+// A vote is kept only if its sender is a provisioner and its signature is
+// valid, so that forged votes can neither fill the memory nor shadow the
+// genuine ones.
+func (d *Detector) Observe(m message.Message) (Evidence, bool) {
+	hdr, blockHash, ok := vote(m)
+	if !ok {
+		return Evidence{}, false
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if hdr.Round+keepRounds < d.round {
+		return Evidence{}, false
+	}
+
+	if d.provisioners != nil {
+		if hdr.Round > d.round+1 || d.provisioners.GetMember(hdr.PubKeyBLS) == nil {
+			return Evidence{}, false
+		}
+	} else if hdr.Round > d.round {
+		d.round = hdr.Round
+		d.prune()
+	}
+
+	k := voteKey{m.Category(), string(hdr.PubKeyBLS), hdr.Round, hdr.Step}
+
+	prev, seen := d.votes[k]
+	if seen {
+		_, prevHash, _ := vote(prev)
+		if d.reported[k] || bytes.Equal(prevHash, blockHash) {
+			return Evidence{}, false
+		}
+	} else if len(d.votes) >= maxVotes {
+		return Evidence{}, false
+	}
+
+	// A forged message does not prove anything
+	if verify(m) != nil {
+		return Evidence{}, false
+	}
+
+	if !seen {
+		d.votes[k] = m
+		return Evidence{}, false
+	}
+
+	first, err := message.Marshal(prev)
+	if err != nil {
+		log.WithError(err).Warn("could not marshal the evidence")
+		return Evidence{}, false
+	}
+
+	second, err := message.Marshal(m)
+	if err != nil {
+		log.WithError(err).Warn("could not marshal the evidence")
+		return Evidence{}, false
+	}
+
+	e := Evidence{
+		Topic:     m.Category(),
+		PubKeyBLS: hdr.PubKeyBLS,
+		Round:     hdr.Round,
+		Step:      hdr.Step,
+		First:     first.Bytes(),
+		Second:    second.Bytes(),
+	}
+
+	d.reported[k] = true
+
+	d.evidence = append(d.evidence, e)
+	if len(d.evidence) > maxEvidence {
+		d.evidence = d.evidence[len(d.evidence)-maxEvidence:]
+	}
+
+	return e, true
+}
+
+// Evidence returns the evidence collected so far, from the oldest.
+func (d *Detector) Evidence() []Evidence {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	evidence := make([]Evidence, len(d.evidence))
+	copy(evidence, d.evidence)
+	return evidence
+}
+
+// prune forgets the votes of the rounds older than keepRounds. It must be
+// called with the lock held.
+func (d *Detector) prune() {
+	for k := range d.votes {
+		if k.round+keepRounds < d.round {
+			delete(d.votes, k)
+			delete(d.reported, k)
+		}
+	}
+}
+
+// vote returns the header of a consensus message, and the hash of the block
+// it votes for.
+func vote(m message.Message) (header.Header, []byte, bool) {
+	switch p := m.Payload().(type) {
+	case message.Reduction:
+		return p.State(), p.State().BlockHash, true
+	case message.Agreement:
+		return p.State(), p.State().BlockHash, true
+	case message.Score:
+		return p.State(), p.VoteHash(), true
+	default:
+		return header.Header{}, nil, false
+	}
+}
+
+// verify checks the BLS signature of the vote carried by a Reduction or an
+// Agreement. Score messages are not signed, and pass the verification.
+func verify(m message.Message) error {
+	var (
+		hdr header.Header
+		sig []byte
+	)
+
+	switch p := m.Payload().(type) {
+	case message.Reduction:
+		hdr, sig = p.State(), p.SignedHash
+	case message.Agreement:
+		hdr, sig = p.State(), p.SignedVotes()
+	default:
+		return nil
+	}
+
+	r := new(bytes.Buffer)
+	if err := header.MarshalSignableVote(r, hdr); err != nil {
+		return err
+	}
+
+	// the crypto package mutates the signature when decompressing it
+	sigCopy := make([]byte, len(sig))
+	copy(sigCopy, sig)
+
+	return msg.VerifyBLSSignature(hdr.PubKeyBLS, r.Bytes(), sigCopy)
+}
+
+// ProvisionersFunc returns the provisioners of the round the chain is at,
+// and the round.
+type ProvisionersFunc func() (user.Provisioners, uint64)
+
+// Run subscribes the detector to the consensus messages of the event bus,
+// until the context is canceled. The provisioners are refreshed on each
+// accepted block.
+//
+// The evidence is logged and kept in memory only. Rusk does not expose the
+// slashing of a provisioner yet, so no Slash contract call can be submitted.
+func (d *Detector) Run(ctx context.Context, eventBus eventbus.Broker, provisioners ProvisionersFunc) {
+	msgChan := make(chan message.Message, 1000)
+	blockChan := make(chan message.Message, 10)
+
+	ids := make(map[topics.Topic]uint32)
+	for _, topic := range []topics.Topic{topics.Reduction, topics.Agreement, topics.Score} {
+		ids[topic] = eventBus.Subscribe(topic, eventbus.NewSafeChanListener(msgChan))
+	}
+
+	ids[topics.AcceptedBlock] = eventBus.Subscribe(topics.AcceptedBlock, eventbus.NewSafeChanListener(blockChan))
+
+	d.Update(provisioners())
+
+	go func() {
+		defer func() {
+			for topic, id := range ids {
+				eventBus.Unsubscribe(topic, id)
+			}
+		}()
+
+		for {
+			select {
+			case <-blockChan:
+				d.Update(provisioners())
+			case m := <-msgChan:
+				e, ok := d.Observe(m)
+				if !ok {
+					continue
+				}
+
+				log.WithField("topic", e.Topic.String()).
+					WithField("provisioner", hex.EncodeToString(e.PubKeyBLS)).
+					WithField("round", e.Round).
+					WithField("step", e.Step).
+					WithField("provable", e.Provable()).
+					Warn("equivocation detected")
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Serve answers the topics.GetEquivocations requests of the rpcbus with the
+// JSON representation of the evidence, until the context is canceled.
+func (d *Detector) Serve(ctx context.Context, rpcBus *rpcbus.RPCBus) error {
+	reqChan := make(chan rpcbus.Request, 1)
+	if err := rpcBus.Register(topics.GetEquivocations, reqChan); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case r := <-reqChan:
+				evidence := d.Evidence()
+
+				resp := make([]EvidenceJSON, len(evidence))
+				for i, e := range evidence {
+					resp[i] = e.JSON()
+				}
+
+				r.RespChan <- rpcbus.NewResponse(resp, nil)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,212 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package equivocation
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/key"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	crypto "github.com/dusk-network/dusk-crypto/hash"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestReductionEquivocation(t *testing.T) {
+	k, err := key.NewRandKeys()
+	assert.NoError(t, err)
+
+	keys := []key.Keys{k}
+	hash1, _ := crypto.RandEntropy(32)
+	hash2, _ := crypto.RandEntropy(32)
+
+	d := NewDetector()
+
+	_, ok := d.Observe(message.New(topics.Reduction, message.MockReduction(hash1, 2, 1, keys)))
+	assert.False(t, ok)
+
+	// The same vote is not an equivocation
+	_, ok = d.Observe(message.New(topics.Reduction, message.MockReduction(hash1, 2, 1, keys)))
+	assert.False(t, ok)
+
+	// Neither is a vote for another step
+	_, ok = d.Observe(message.New(topics.Reduction, message.MockReduction(hash2, 2, 2, keys)))
+	assert.False(t, ok)
+
+	e, ok := d.Observe(message.New(topics.Reduction, message.MockReduction(hash2, 2, 1, keys)))
+	assert.True(t, ok)
+	assert.True(t, e.Provable())
+	assert.Equal(t, k.BLSPubKeyBytes, e.PubKeyBLS)
+	assert.Equal(t, uint64(2), e.Round)
+	assert.Equal(t, uint8(1), e.Step)
+
+	// The evidence holds both messages
+	for _, b := range [][]byte{e.First, e.Second} {
+		m, err := message.Unmarshal(bytes.NewBuffer(b))
+		assert.NoError(t, err)
+		assert.Equal(t, topics.Reduction, m.Category())
+	}
+
+	// The equivocation is reported once
+	hash3, _ := crypto.RandEntropy(32)
+	_, ok = d.Observe(message.New(topics.Reduction, message.MockReduction(hash3, 2, 1, keys)))
+	assert.False(t, ok)
+	assert.Len(t, d.Evidence(), 1)
+}
+
+func TestForgedVote(t *testing.T) {
+	k, err := key.NewRandKeys()
+	assert.NoError(t, err)
+
+	forger, err := key.NewRandKeys()
+	assert.NoError(t, err)
+
+	hash1, _ := crypto.RandEntropy(32)
+	hash2, _ := crypto.RandEntropy(32)
+
+	// A vote with the public key of k, signed by someone else
+	forged := message.NewReduction(header.Header{Round: 2, Step: 1, BlockHash: hash2, PubKeyBLS: k.BLSPubKeyBytes})
+	forged.SignedHash = message.MockReduction(hash2, 2, 1, []key.Keys{forger}).SignedHash
+
+	d := NewDetector()
+
+	_, ok := d.Observe(message.New(topics.Reduction, message.MockReduction(hash1, 2, 1, []key.Keys{k})))
+	assert.False(t, ok)
+
+	_, ok = d.Observe(message.New(topics.Reduction, *forged))
+	assert.False(t, ok)
+
+	// A forged vote observed first does not shadow the genuine ones
+	d = NewDetector()
+
+	_, ok = d.Observe(message.New(topics.Reduction, *forged))
+	assert.False(t, ok)
+
+	_, ok = d.Observe(message.New(topics.Reduction, message.MockReduction(hash1, 2, 1, []key.Keys{k})))
+	assert.False(t, ok)
+
+	_, ok = d.Observe(message.New(topics.Reduction, message.MockReduction(hash2, 2, 1, []key.Keys{k})))
+	assert.True(t, ok)
+}
+
+func TestScoreEquivocation(t *testing.T) {
+	k, err := key.NewRandKeys()
+	assert.NoError(t, err)
+
+	hdr := header.Header{Round: 2, Step: 1, PubKeyBLS: k.BLSPubKeyBytes, BlockHash: make([]byte, 32)}
+
+	blk1 := *block.NewBlock()
+	blk1.Header.Hash, _ = crypto.RandEntropy(32)
+
+	blk2 := *block.NewBlock()
+	blk2.Header.Hash, _ = crypto.RandEntropy(32)
+
+	d := NewDetector()
+
+	_, ok := d.Observe(message.New(topics.Score, message.MockScore(hdr, blk1)))
+	assert.False(t, ok)
+
+	e, ok := d.Observe(message.New(topics.Score, message.MockScore(hdr, blk2)))
+	assert.True(t, ok)
+
+	// Score messages are not signed, and cannot be used to slash
+	assert.False(t, e.Provable())
+}
+
+func TestPruneRounds(t *testing.T) {
+	k, err := key.NewRandKeys()
+	assert.NoError(t, err)
+
+	keys := []key.Keys{k}
+	hash1, _ := crypto.RandEntropy(32)
+	hash2, _ := crypto.RandEntropy(32)
+
+	d := NewDetector()
+
+	_, ok := d.Observe(message.New(topics.Reduction, message.MockReduction(hash1, 2, 1, keys)))
+	assert.False(t, ok)
+
+	_, ok = d.Observe(message.New(topics.Reduction, message.MockReduction(hash1, 2+keepRounds+1, 1, keys)))
+	assert.False(t, ok)
+
+	// The votes of the old rounds are forgotten
+	_, ok = d.Observe(message.New(topics.Reduction, message.MockReduction(hash2, 2, 1, keys)))
+	assert.False(t, ok)
+	assert.Len(t, d.votes, 1)
+}
+
+func TestProvisionersOnly(t *testing.T) {
+	k, err := key.NewRandKeys()
+	assert.NoError(t, err)
+
+	outsider, err := key.NewRandKeys()
+	assert.NoError(t, err)
+
+	p := user.NewProvisioners()
+	assert.NoError(t, p.Add(k.BLSPubKeyBytes, 1000, 0, 1000))
+
+	hash1, _ := crypto.RandEntropy(32)
+
+	d := NewDetector()
+	d.Update(*p, 2)
+
+	// The votes of other senders are not kept
+	_, ok := d.Observe(message.New(topics.Reduction, message.MockReduction(hash1, 2, 1, []key.Keys{outsider})))
+	assert.False(t, ok)
+	assert.Empty(t, d.votes)
+
+	// Neither are the votes for the rounds after the next one
+	_, ok = d.Observe(message.New(topics.Reduction, message.MockReduction(hash1, 4, 1, []key.Keys{k})))
+	assert.False(t, ok)
+	assert.Empty(t, d.votes)
+
+	_, ok = d.Observe(message.New(topics.Reduction, message.MockReduction(hash1, 3, 1, []key.Keys{k})))
+	assert.False(t, ok)
+	assert.Len(t, d.votes, 1)
+}
+
+func TestServe(t *testing.T) {
+	k, err := key.NewRandKeys()
+	assert.NoError(t, err)
+
+	keys := []key.Keys{k}
+	hash1, _ := crypto.RandEntropy(32)
+	hash2, _ := crypto.RandEntropy(32)
+
+	d := NewDetector()
+
+	_, ok := d.Observe(message.New(topics.Reduction, message.MockReduction(hash1, 2, 1, keys)))
+	assert.False(t, ok)
+
+	e, ok := d.Observe(message.New(topics.Reduction, message.MockReduction(hash2, 2, 1, keys)))
+	assert.True(t, ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rpcBus := rpcbus.New()
+	assert.NoError(t, d.Serve(ctx, rpcBus))
+
+	resp, err := rpcBus.Call(topics.GetEquivocations, rpcbus.EmptyRequest(), time.Second)
+	assert.NoError(t, err)
+
+	evidence := resp.([]EvidenceJSON)
+	assert.Len(t, evidence, 1)
+	assert.Equal(t, "reduction", evidence[0].Topic)
+	assert.Equal(t, hex.EncodeToString(k.BLSPubKeyBytes), evidence[0].PubKeyBLS)
+	assert.Equal(t, uint64(2), evidence[0].Round)
+	assert.True(t, evidence[0].Provable)
+	assert.Equal(t, hex.EncodeToString(e.Second), evidence[0].Second)
+}
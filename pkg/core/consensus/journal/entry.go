@@ -0,0 +1,283 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+)
+
+// maxEntrySize bounds the size of an entry read from a journal, so that a
+// corrupted length does not exhaust the memory.
+const maxEntrySize = 64 * 1024 * 1024
+
+// Kind is the kind of event recorded by an Entry.
+type Kind uint8
+
+const (
+	// RoundStart records the state a round starts from.
+	RoundStart Kind = iota
+	// PhaseStart records the transition to a consensus phase.
+	PhaseStart
+	// Timeout records the expiry of the timer of a phase.
+	Timeout
+	// MessageReceived records a consensus message, and the result of its
+	// verification.
+	MessageReceived
+	// RoundEnd records the outcome of a round, with the certificate of the
+	// winning block.
+	RoundEnd
+)
+
+// String representation of a Kind.
+func (k Kind) String() string {
+	switch k {
+	case RoundStart:
+		return "round-start"
+	case PhaseStart:
+		return "phase-start"
+	case Timeout:
+		return "timeout"
+	case MessageReceived:
+		return "message"
+	case RoundEnd:
+		return "round-end"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(k))
+	}
+}
+
+// Entry is an event of the consensus journal. The meaning of the fields
+// depends on the Kind:
+// - RoundStart: Hash is the hash of the previous block, Data holds the seed
+// and the provisioners.
+// - PhaseStart and Timeout: Name is the name of the phase.
+// - MessageReceived: Name is the topic, Sender the BLS key of the sender,
+// Hash the voted block hash and Data the wire encoding of the message.
+// - RoundEnd: Hash is the hash of the winning block, and Data its
+// certificate.
+// Err is the verification error of a message, or the error ending a round.
+type Entry struct {
+	Kind   Kind
+	Time   time.Time
+	Round  uint64
+	Step   uint8
+	Name   string
+	Sender []byte
+	Hash   []byte
+	Err    string
+	Data   []byte
+}
+
+// String representation of an Entry.
+func (e Entry) String() string {
+	s := fmt.Sprintf("%s round=%d step=%d kind=%s", e.Time.Format(time.RFC3339Nano), e.Round, e.Step, e.Kind)
+
+	if e.Name != "" {
+		s += fmt.Sprintf(" name=%s", e.Name)
+	}
+
+	if len(e.Sender) > 0 {
+		s += fmt.Sprintf(" sender=%x", e.Sender)
+	}
+
+	if len(e.Hash) > 0 {
+		s += fmt.Sprintf(" hash=%x", e.Hash)
+	}
+
+	if e.Err != "" {
+		s += fmt.Sprintf(" err=%q", e.Err)
+	}
+
+	return s
+}
+
+// Message decodes the message of a MessageReceived entry.
+func (e Entry) Message() (message.Message, error) {
+	if e.Kind != MessageReceived {
+		return nil, fmt.Errorf("no message in a %s entry", e.Kind)
+	}
+
+	return message.Unmarshal(bytes.NewBuffer(e.Data))
+}
+
+// RoundState decodes the seed and the provisioners of a RoundStart entry.
+func (e Entry) RoundState() ([]byte, user.Provisioners, error) {
+	if e.Kind != RoundStart {
+		return nil, user.Provisioners{}, fmt.Errorf("no round state in a %s entry", e.Kind)
+	}
+
+	r := bytes.NewBuffer(e.Data)
+
+	var seed []byte
+	if err := encoding.ReadVarBytes(r, &seed); err != nil {
+		return nil, user.Provisioners{}, err
+	}
+
+	p, err := user.UnmarshalProvisioners(r)
+	if err != nil {
+		return nil, user.Provisioners{}, err
+	}
+
+	return seed, p, nil
+}
+
+// Certificate decodes the certificate of a RoundEnd entry. It returns nil if
+// the round ended without a winning block.
+func (e Entry) Certificate() (*block.Certificate, error) {
+	if e.Kind != RoundEnd {
+		return nil, fmt.Errorf("no certificate in a %s entry", e.Kind)
+	}
+
+	if len(e.Data) == 0 {
+		return nil, nil
+	}
+
+	cert := block.EmptyCertificate()
+	if err := message.UnmarshalCertificate(bytes.NewBuffer(e.Data), cert); err != nil {
+		return nil, err
+	}
+
+	return cert, nil
+}
+
+// MarshalEntry encodes an Entry.
+func MarshalEntry(r *bytes.Buffer, e Entry) error {
+	if err := encoding.WriteUint8(r, uint8(e.Kind)); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteUint64LE(r, uint64(e.Time.UnixNano())); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteUint64LE(r, e.Round); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteUint8(r, e.Step); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteString(r, e.Name); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteVarBytes(r, e.Sender); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteVarBytes(r, e.Hash); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteString(r, e.Err); err != nil {
+		return err
+	}
+
+	return encoding.WriteVarBytes(r, e.Data)
+}
+
+// UnmarshalEntry decodes an Entry.
+func UnmarshalEntry(r *bytes.Buffer, e *Entry) error {
+	var kind uint8
+	if err := encoding.ReadUint8(r, &kind); err != nil {
+		return err
+	}
+
+	e.Kind = Kind(kind)
+
+	var t uint64
+	if err := encoding.ReadUint64LE(r, &t); err != nil {
+		return err
+	}
+
+	e.Time = time.Unix(0, int64(t))
+
+	if err := encoding.ReadUint64LE(r, &e.Round); err != nil {
+		return err
+	}
+
+	if err := encoding.ReadUint8(r, &e.Step); err != nil {
+		return err
+	}
+
+	var err error
+	if e.Name, err = encoding.ReadString(r); err != nil {
+		return err
+	}
+
+	if err := encoding.ReadVarBytes(r, &e.Sender); err != nil {
+		return err
+	}
+
+	if err := encoding.ReadVarBytes(r, &e.Hash); err != nil {
+		return err
+	}
+
+	if e.Err, err = encoding.ReadString(r); err != nil {
+		return err
+	}
+
+	return encoding.ReadVarBytes(r, &e.Data)
+}
+
+// Read decodes the entries of a journal. Each entry is prefixed by its
+// length. A truncated entry at the end, as left by a crash, is ignored. A
+// corrupted entry stops the decoding, and the entries before it are
+// returned.
+func Read(r io.Reader) ([]Entry, error) {
+	br := bufio.NewReader(r)
+	entries := make([]Entry, 0)
+
+	for {
+		var size uint32
+
+		err := binary.Read(br, binary.LittleEndian, &size)
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			return entries, nil
+		}
+
+		if err != nil {
+			return entries, err
+		}
+
+		if size > maxEntrySize {
+			log.WithField("entries", len(entries)).
+				Warnf("journal entry of %d bytes exceeds the maximum size, the following entries are skipped", size)
+			return entries, nil
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(br, data); err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) || err == io.EOF {
+				return entries, nil
+			}
+
+			return entries, err
+		}
+
+		var e Entry
+		if err := UnmarshalEntry(bytes.NewBuffer(data), &e); err != nil {
+			log.WithError(err).WithField("entries", len(entries)).
+				Warn("corrupted journal entry, the following entries are skipped")
+			return entries, nil
+		}
+
+		entries = append(entries, e)
+	}
+}
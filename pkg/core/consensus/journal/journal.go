@@ -0,0 +1,489 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	logger "github.com/sirupsen/logrus"
+)
+
+var log = logger.WithField("process", "consensus journal")
+
+// FileName is the name of the journal file being written. The rotated files
+// are suffixed with their generation, .1 being the most recent.
+const FileName = "consensus.journal"
+
+const (
+	defaultMaxFileSize = 64 * 1024 * 1024
+	defaultMaxFiles    = 5
+)
+
+// ErrNotMember is recorded for the messages discarded because their sender
+// is not part of the committee of the step.
+var ErrNotMember = errors.New("sender is not a committee member")
+
+// Journal records the events of the consensus into a binary log, to
+// investigate a stalled round, or to replay it offline. All the methods of a
+// nil Journal are no-ops, so that the consensus components can record their
+// events whether the journal is enabled or not.
+type Journal struct {
+	lock sync.Mutex
+	w    *bufio.Writer
+
+	// The file being written, and its rotation bounds. f is nil for a
+	// journal created with New, or disabled after an error. The file is
+	// rotated once its size exceeds limit.
+	dir         string
+	f           *os.File
+	size        int64
+	limit       int64
+	maxFileSize int64
+	maxFiles    int
+}
+
+// New creates a Journal writing into w, without rotation.
+func New(w io.Writer) *Journal {
+	return &Journal{w: bufio.NewWriter(w)}
+}
+
+// Open creates a Journal writing into the directory dir. The journal file is
+// rotated when it exceeds maxFileSize bytes, and at most maxFiles files are
+// kept, the oldest being removed.
+func Open(dir string, maxFileSize int64, maxFiles int) (*Journal, error) {
+	if maxFileSize <= 0 {
+		maxFileSize = defaultMaxFileSize
+	}
+
+	if maxFiles <= 0 {
+		maxFiles = defaultMaxFiles
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	j := &Journal{
+		dir:         dir,
+		maxFileSize: maxFileSize,
+		maxFiles:    maxFiles,
+	}
+
+	if err := j.openFile(); err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// Files returns the journal files of the directory dir, from the oldest.
+func Files(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, FileName+".*"))
+	if err != nil {
+		return nil, err
+	}
+
+	// rotated files, from the highest generation
+	files := make([]string, 0, len(matches)+1)
+
+	for gen := len(matches); gen > 0; gen-- {
+		path := rotatedPath(dir, gen)
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+
+	path := filepath.Join(dir, FileName)
+	if _, err := os.Stat(path); err == nil {
+		files = append(files, path)
+	}
+
+	return files, nil
+}
+
+// ReadDir reads the entries of all the journal files of the directory dir,
+// from the oldest.
+func ReadDir(dir string) ([]Entry, error) {
+	files, err := Files(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0)
+
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+
+		es, err := Read(f)
+		_ = f.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		entries = append(entries, es...)
+	}
+
+	return entries, nil
+}
+
+// Close flushes the journal, and closes its file.
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if err := j.w.Flush(); err != nil {
+		return err
+	}
+
+	if j.f != nil {
+		return j.f.Close()
+	}
+
+	return nil
+}
+
+// Record appends an entry to the journal. The entry is flushed right away,
+// so that the journal is complete up to a crash.
+func (j *Journal) Record(e Entry) {
+	if j == nil {
+		return
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	buf := new(bytes.Buffer)
+	if err := MarshalEntry(buf, e); err != nil {
+		log.WithError(err).Warn("could not marshal journal entry")
+		return
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if err := j.write(buf.Bytes()); err != nil {
+		log.WithError(err).Warn("could not write journal entry")
+	}
+}
+
+// RoundStarted records the state the round starts from.
+func (j *Journal) RoundStarted(round uint64, seed, hash []byte, p *user.Provisioners) {
+	if j == nil {
+		return
+	}
+
+	data := new(bytes.Buffer)
+	if err := encoding.WriteVarBytes(data, seed); err != nil {
+		log.WithError(err).Warn("could not marshal journal entry")
+		return
+	}
+
+	if err := user.MarshalProvisioners(data, p); err != nil {
+		log.WithError(err).Warn("could not marshal journal entry")
+		return
+	}
+
+	j.Record(Entry{Kind: RoundStart, Round: round, Hash: hash, Data: data.Bytes()})
+}
+
+// PhaseStarted records the transition to the phase name.
+func (j *Journal) PhaseStarted(round uint64, step uint8, name string) {
+	j.Record(Entry{Kind: PhaseStart, Round: round, Step: step, Name: name})
+}
+
+// TimedOut records the expiry of the timer of the phase name.
+func (j *Journal) TimedOut(round uint64, step uint8, name string) {
+	j.Record(Entry{Kind: Timeout, Round: round, Step: step, Name: name})
+}
+
+// Received records a consensus message, and the error of its verification.
+// A nil error means that the message has been verified.
+func (j *Journal) Received(m message.Message, verifyErr error) {
+	if j == nil {
+		return
+	}
+
+	p, ok := m.Payload().(interface{ State() header.Header })
+	if !ok {
+		return
+	}
+
+	hdr := p.State()
+
+	data, err := message.Marshal(m)
+	if err != nil {
+		log.WithError(err).Warn("could not marshal journal entry")
+		return
+	}
+
+	e := Entry{
+		Kind:   MessageReceived,
+		Round:  hdr.Round,
+		Step:   hdr.Step,
+		Name:   m.Category().String(),
+		Sender: hdr.PubKeyBLS,
+		Hash:   hdr.BlockHash,
+		Data:   data.Bytes(),
+	}
+
+	// the hash of a Score is the one of its candidate
+	if s, ok := m.Payload().(message.Score); ok {
+		e.Hash = s.VoteHash()
+	}
+
+	if verifyErr != nil {
+		e.Err = verifyErr.Error()
+	}
+
+	j.Record(e)
+}
+
+// RoundEnded records the outcome of a round: the winning block, or the error
+// the round ended with.
+func (j *Journal) RoundEnded(round uint64, blk block.Block, roundErr error) {
+	if j == nil {
+		return
+	}
+
+	e := Entry{Kind: RoundEnd, Round: round}
+
+	if roundErr != nil {
+		e.Err = roundErr.Error()
+	}
+
+	if roundErr == nil && blk.Header != nil {
+		e.Hash = blk.Header.Hash
+
+		if cert := blk.Header.Certificate; cert != nil {
+			e.Step = cert.Step
+
+			data := new(bytes.Buffer)
+			if err := message.MarshalCertificate(data, cert); err != nil {
+				log.WithError(err).Warn("could not marshal journal entry")
+				return
+			}
+
+			e.Data = data.Bytes()
+		}
+	}
+
+	j.Record(e)
+}
+
+// write appends an entry, prefixed by its length, and rotates the file when
+// full. It must be called with the lock held.
+func (j *Journal) write(entry []byte) error {
+	if j.f != nil && j.size > 0 && j.size+int64(len(entry))+4 > j.limit {
+		if err := j.rotate(); err != nil {
+			if j.f == nil {
+				return err
+			}
+
+			// Keep writing into the current file, and retry once it has
+			// grown by maxFileSize again
+			log.WithError(err).Warn("could not rotate the journal file")
+
+			j.limit = j.size + j.maxFileSize
+		}
+	}
+
+	err := binary.Write(j.w, binary.LittleEndian, uint32(len(entry)))
+	if err == nil {
+		_, err = j.w.Write(entry)
+	}
+
+	if err == nil {
+		err = j.w.Flush()
+	}
+
+	if err != nil {
+		j.discard()
+		return err
+	}
+
+	j.size += int64(len(entry)) + 4
+	return nil
+}
+
+// discard drops the part of an entry which could not be written, so that the
+// next entries are not appended to a truncated one. It must be called with
+// the lock held.
+func (j *Journal) discard() {
+	if j.f == nil {
+		return
+	}
+
+	j.w.Reset(j.f)
+
+	if err := j.f.Truncate(j.size); err != nil {
+		j.disable(err)
+	}
+}
+
+// rotate shifts the generation of the rotated files, dropping the oldest,
+// and starts a new file. If the files cannot be shifted, the current file is
+// reopened. It must be called with the lock held.
+func (j *Journal) rotate() error {
+	if err := j.w.Flush(); err != nil {
+		return err
+	}
+
+	if err := j.shift(); err != nil {
+		if openErr := j.openFile(); openErr != nil {
+			j.disable(openErr)
+		}
+
+		return err
+	}
+
+	if err := j.openFile(); err != nil {
+		j.disable(err)
+		return err
+	}
+
+	return nil
+}
+
+// shift closes the current file, and shifts the generation of the files.
+func (j *Journal) shift() error {
+	if err := j.f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Remove(rotatedPath(j.dir, j.maxFiles-1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for gen := j.maxFiles - 2; gen > 0; gen-- {
+		if err := os.Rename(rotatedPath(j.dir, gen), rotatedPath(j.dir, gen+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	path := filepath.Join(j.dir, FileName)
+
+	if j.maxFiles > 1 {
+		return os.Rename(path, rotatedPath(j.dir, 1))
+	}
+
+	return os.Remove(path)
+}
+
+// disable stops the journal after an error which left it without a file.
+// The following entries are dropped.
+func (j *Journal) disable(err error) {
+	log.WithError(err).Error("consensus journal disabled")
+
+	if j.f != nil {
+		_ = j.f.Close()
+	}
+
+	j.f = nil
+	j.w = bufio.NewWriter(ioutil.Discard)
+}
+
+// openFile opens the journal file for appending. A partial entry left at its
+// end by a crash is truncated, otherwise the following entries could not be
+// read.
+func (j *Journal) openFile() error {
+	f, err := os.OpenFile(filepath.Join(j.dir, FileName), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	size, err := entriesSize(f)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if size < info.Size() {
+		log.WithField("size", info.Size()).
+			WithField("truncated", info.Size()-size).
+			Warn("truncating a partial journal entry")
+
+		if err := f.Truncate(size); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+
+	j.f = f
+	j.w = bufio.NewWriter(f)
+	j.size = size
+	j.limit = j.maxFileSize
+	return nil
+}
+
+// entriesSize returns the size of the complete entries at the start of r.
+func entriesSize(r io.Reader) (int64, error) {
+	br := bufio.NewReader(r)
+
+	var total int64
+
+	for {
+		var size uint32
+
+		err := binary.Read(br, binary.LittleEndian, &size)
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			return total, nil
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		if size > maxEntrySize {
+			return total, nil
+		}
+
+		n, err := io.CopyN(ioutil.Discard, br, int64(size))
+		if n < int64(size) {
+			return total, nil
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		total += int64(size) + 4
+	}
+}
+
+func rotatedPath(dir string, gen int) string {
+	return filepath.Join(dir, fmt.Sprintf("%s.%d", FileName, gen))
+}
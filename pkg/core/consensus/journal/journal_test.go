@@ -0,0 +1,257 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package journal
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/key"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	crypto "github.com/dusk-network/dusk-crypto/hash"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestRecordAndRead(t *testing.T) {
+	k, err := key.NewRandKeys()
+	assert.NoError(t, err)
+
+	p := user.NewProvisioners()
+	assert.NoError(t, p.Add(k.BLSPubKeyBytes, 1000, 0, 1000))
+
+	seed, _ := crypto.RandEntropy(33)
+	prevHash, _ := crypto.RandEntropy(32)
+	hash, _ := crypto.RandEntropy(32)
+
+	buf := new(bytes.Buffer)
+	j := New(buf)
+
+	red := message.MockReduction(hash, 2, 2, []key.Keys{k})
+
+	j.RoundStarted(2, seed, prevHash, p)
+	j.PhaseStarted(2, 1, "selection")
+	j.TimedOut(2, 1, "selection")
+	j.Received(message.New(topics.Reduction, red), nil)
+	j.Received(message.New(topics.Reduction, red), ErrNotMember)
+
+	blk := block.NewBlock()
+	blk.Header.Hash = hash
+	blk.Header.Certificate.Step = 3
+	j.RoundEnded(2, *blk, nil)
+	j.RoundEnded(3, block.Block{}, errors.New("max steps reached"))
+
+	assert.NoError(t, j.Close())
+
+	entries, err := Read(buf)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 7)
+
+	kinds := make([]Kind, len(entries))
+	for i, e := range entries {
+		kinds[i] = e.Kind
+		assert.False(t, e.Time.IsZero())
+	}
+
+	assert.Equal(t, []Kind{RoundStart, PhaseStart, Timeout, MessageReceived, MessageReceived, RoundEnd, RoundEnd}, kinds)
+
+	// round state
+	s, prov, err := entries[0].RoundState()
+	assert.NoError(t, err)
+	assert.Equal(t, seed, s)
+	assert.Equal(t, prevHash, entries[0].Hash)
+	assert.Equal(t, 1, prov.Set.Len())
+
+	// phases
+	assert.Equal(t, "selection", entries[1].Name)
+	assert.Equal(t, uint8(1), entries[2].Step)
+
+	// messages, with their verification result
+	assert.Equal(t, k.BLSPubKeyBytes, entries[3].Sender)
+	assert.Equal(t, hash, entries[3].Hash)
+	assert.Empty(t, entries[3].Err)
+	assert.Equal(t, ErrNotMember.Error(), entries[4].Err)
+
+	m, err := entries[3].Message()
+	assert.NoError(t, err)
+	assert.Equal(t, topics.Reduction, m.Category())
+	assert.Equal(t, red.SignedHash, m.Payload().(message.Reduction).SignedHash)
+
+	// certificates
+	assert.Equal(t, hash, entries[5].Hash)
+	assert.Equal(t, uint8(3), entries[5].Step)
+
+	cert, err := entries[5].Certificate()
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(3), cert.Step)
+
+	assert.Equal(t, "max steps reached", entries[6].Err)
+
+	cert, err = entries[6].Certificate()
+	assert.NoError(t, err)
+	assert.Nil(t, cert)
+}
+
+func TestTruncatedJournal(t *testing.T) {
+	buf := new(bytes.Buffer)
+	j := New(buf)
+
+	j.PhaseStarted(1, 1, "selection")
+	j.PhaseStarted(1, 2, "reduction-first-step")
+
+	// a crash in the middle of a write leaves a truncated entry
+	data := buf.Bytes()[:buf.Len()-3]
+
+	entries, err := Read(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestCorruptedJournal(t *testing.T) {
+	buf := new(bytes.Buffer)
+	j := New(buf)
+
+	j.PhaseStarted(1, 1, "selection")
+	j.PhaseStarted(1, 2, "reduction-first-step")
+
+	// a corrupted length stops the decoding
+	buf.Write([]byte{0xff, 0xff, 0xff, 0xff})
+	j.PhaseStarted(1, 3, "reduction-second-step")
+
+	entries, err := Read(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestReopenTruncatedJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	j, err := Open(dir, 0, 0)
+	assert.NoError(t, err)
+
+	j.PhaseStarted(1, 1, "selection")
+	assert.NoError(t, j.Close())
+
+	// a crash in the middle of a write leaves a partial entry
+	f, err := os.OpenFile(filepath.Join(dir, FileName), os.O_WRONLY|os.O_APPEND, 0o600)
+	assert.NoError(t, err)
+
+	_, err = f.Write([]byte{0x40, 0, 0, 0, 1, 2})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	// the partial entry is dropped on reopening
+	j, err = Open(dir, 0, 0)
+	assert.NoError(t, err)
+
+	j.PhaseStarted(1, 2, "reduction-first-step")
+	assert.NoError(t, j.Close())
+
+	entries, err := ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, uint8(2), entries[1].Step)
+}
+
+func TestRotationFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	// the oldest rotated file cannot be removed
+	assert.NoError(t, os.MkdirAll(filepath.Join(rotatedPath(dir, 1), "x"), 0o700))
+
+	j, err := Open(dir, 200, 2)
+	assert.NoError(t, err)
+
+	for step := uint8(1); step <= 30; step++ {
+		j.PhaseStarted(1, step, "selection")
+	}
+
+	assert.NoError(t, j.Close())
+
+	// the entries are still written into the current file
+	f, err := os.Open(filepath.Join(dir, FileName))
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	entries, err := Read(f)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 30)
+}
+
+func TestRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	j, err := Open(dir, 200, 3)
+	assert.NoError(t, err)
+
+	for step := uint8(1); step <= 30; step++ {
+		j.PhaseStarted(1, step, "selection")
+	}
+
+	assert.NoError(t, j.Close())
+
+	files, err := Files(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 3)
+
+	// the entries are read from the oldest file, and the oldest ones were
+	// dropped
+	entries, err := ReadDir(dir)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, entries)
+	assert.Equal(t, uint8(30), entries[len(entries)-1].Step)
+	assert.NotEqual(t, uint8(1), entries[0].Step)
+
+	for i := 1; i < len(entries); i++ {
+		assert.Equal(t, entries[i-1].Step+1, entries[i].Step)
+	}
+
+	// a reopened journal appends to the existing file
+	j, err = Open(dir, 200, 3)
+	assert.NoError(t, err)
+
+	j.PhaseStarted(1, 31, "selection")
+	assert.NoError(t, j.Close())
+
+	entries, err = ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(31), entries[len(entries)-1].Step)
+}
+
+func TestNilJournal(t *testing.T) {
+	var j *Journal
+
+	j.RoundStarted(1, nil, nil, user.NewProvisioners())
+	j.PhaseStarted(1, 1, "selection")
+	j.Received(message.New(topics.Reduction, message.Reduction{}), nil)
+	j.RoundEnded(1, block.Block{}, nil)
+	assert.NoError(t, j.Close())
+}
@@ -12,20 +12,51 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 )
 
+// QueueStats reports the occupation of a Queue.
+type QueueStats struct {
+	// Size is the number of queued events.
+	Size int `json:"size"`
+	// Rounds is the number of rounds with queued events.
+	Rounds int `json:"rounds"`
+	// OldestRound is the lowest round with queued events. It is zero if the
+	// queue is empty.
+	OldestRound uint64 `json:"oldestRound"`
+	// Dropped is the number of events dropped because the queue was full.
+	Dropped uint64 `json:"dropped"`
+}
+
 // Queue is a Queue of Events grouped by rounds and steps. It is thread-safe
 // through a sync.RWMutex.
 // TODO: entries should become buntdb instead.
 type Queue struct {
 	lock    sync.RWMutex
 	entries map[uint64]map[uint8][]message.Message
+
+	// sizes is the number of events queued for each round.
+	sizes map[uint64]int
+
+	// maxRoundEvents bounds the events queued for a round, and maxRounds the
+	// number of rounds with queued events. Zero means unbounded.
+	maxRoundEvents int
+	maxRounds      int
+	dropped        uint64
 }
 
 // NewQueue creates a new Queue. It is primarily used by Collectors to
 // temporarily store messages not yet relevant to the collection process.
 func NewQueue() *Queue {
-	entries := make(map[uint64]map[uint8][]message.Message)
+	return NewBoundedQueue(0, 0)
+}
+
+// NewBoundedQueue creates a Queue holding at most maxRoundEvents events per
+// round, for at most maxRounds rounds. The events exceeding the bounds are
+// dropped. Zero means unbounded.
+func NewBoundedQueue(maxRoundEvents, maxRounds int) *Queue {
 	return &Queue{
-		entries: entries,
+		entries:        make(map[uint64]map[uint8][]message.Message),
+		sizes:          make(map[uint64]int),
+		maxRoundEvents: maxRoundEvents,
+		maxRounds:      maxRounds,
 	}
 }
 
@@ -37,35 +68,67 @@ func (eq *Queue) GetEvents(round uint64, step uint8) []message.Message {
 	if eq.entries[round][step] != nil {
 		messages := eq.entries[round][step]
 		eq.entries[round][step] = nil
+
+		eq.sizes[round] -= len(messages)
+		if eq.sizes[round] <= 0 {
+			eq.remove(round)
+		}
+
 		return messages
 	}
 
 	return nil
 }
 
-// PutEvent stores an Event at a given round and step.
+// PutEvent stores an Event at a given round and step. The event is dropped
+// if the queue is full. When all the rounds are taken, the events of the
+// highest round are dropped instead, if it is after the round of the event,
+// so that messages for far rounds cannot keep out the ones of the next round.
 func (eq *Queue) PutEvent(round uint64, step uint8, m message.Message) {
 	eq.lock.Lock()
 	defer eq.lock.Unlock()
 
 	// Initialize the map on this round if it was not yet created
 	if eq.entries[round] == nil {
+		if eq.maxRounds > 0 && len(eq.entries) >= eq.maxRounds && !eq.evictAbove(round) {
+			eq.dropped++
+			return
+		}
+
 		eq.entries[round] = make(map[uint8][]message.Message)
 	}
 
+	if eq.maxRoundEvents > 0 && eq.sizes[round] >= eq.maxRoundEvents {
+		eq.dropped++
+		return
+	}
+
 	// Initialize the array on this step if it was not yet created
 	if eq.entries[round][step] == nil {
 		eq.entries[round][step] = make([]message.Message, 0)
 	}
 
 	eq.entries[round][step] = append(eq.entries[round][step], m)
+	eq.sizes[round]++
 }
 
 // Clear the queue.
 func (eq *Queue) Clear(round uint64) {
 	eq.lock.Lock()
 	defer eq.lock.Unlock()
-	eq.entries[round] = nil
+	eq.remove(round)
+}
+
+// Prune removes the events of the rounds lower than round.
+func (eq *Queue) Prune(round uint64) {
+	eq.lock.Lock()
+	defer eq.lock.Unlock()
+
+	for r := range eq.entries {
+		if r < round {
+			eq.remove(r)
+		}
+	}
 }
 
 // Flush all events stored for a specific round from the queue, and return them.
@@ -75,13 +138,60 @@ func (eq *Queue) Flush(round uint64) []message.Message {
 
 	if eq.entries[round] != nil {
 		events := make([]message.Message, 0)
-		for step, evs := range eq.entries[round] {
+		for _, evs := range eq.entries[round] {
 			events = append(events, evs...)
-			eq.entries[round][step] = nil
 		}
 
+		eq.remove(round)
 		return events
 	}
 
 	return nil
 }
+
+// Stats returns the occupation of the queue.
+func (eq *Queue) Stats() QueueStats {
+	eq.lock.RLock()
+	defer eq.lock.RUnlock()
+
+	stats := QueueStats{
+		Rounds:  len(eq.entries),
+		Dropped: eq.dropped,
+	}
+
+	for round, size := range eq.sizes {
+		stats.Size += size
+
+		if stats.OldestRound == 0 || round < stats.OldestRound {
+			stats.OldestRound = round
+		}
+	}
+
+	return stats
+}
+
+// evictAbove drops the events of the highest round, if it is after round. It
+// returns false if there is no such round. It must be called with the lock
+// held.
+func (eq *Queue) evictAbove(round uint64) bool {
+	highest := round
+	for r := range eq.entries {
+		if r > highest {
+			highest = r
+		}
+	}
+
+	if highest == round {
+		return false
+	}
+
+	eq.dropped += uint64(eq.sizes[highest])
+	eq.remove(highest)
+	return true
+}
+
+// remove drops the events of a round. It must be called with the lock held.
+func (eq *Queue) remove(round uint64) {
+	delete(eq.entries, round)
+	delete(eq.sizes, round)
+}
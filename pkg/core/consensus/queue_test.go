@@ -0,0 +1,96 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package consensus_test
+
+import (
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueueBounds(t *testing.T) {
+	q := consensus.NewBoundedQueue(3, 2)
+	m := message.New(topics.Reduction, message.Reduction{})
+
+	for i := 0; i < 5; i++ {
+		q.PutEvent(2, uint8(i%2), m)
+	}
+
+	// a third round is dropped
+	q.PutEvent(3, 1, m)
+	q.PutEvent(4, 1, m)
+
+	stats := q.Stats()
+	assert.Equal(t, 4, stats.Size)
+	assert.Equal(t, 2, stats.Rounds)
+	assert.Equal(t, uint64(2), stats.OldestRound)
+	assert.Equal(t, uint64(3), stats.Dropped)
+
+	// the events taken out free room in their round
+	assert.Len(t, q.GetEvents(2, 0), 2)
+	q.PutEvent(2, 1, m)
+	assert.Len(t, q.GetEvents(2, 1), 2)
+
+	// an emptied round is removed
+	assert.Equal(t, 1, q.Stats().Rounds)
+	q.PutEvent(4, 1, m)
+	assert.Equal(t, uint64(3), q.Stats().OldestRound)
+}
+
+func TestQueueEvictsHighestRound(t *testing.T) {
+	q := consensus.NewBoundedQueue(3, 2)
+	m := message.New(topics.Reduction, message.Reduction{})
+
+	// far rounds take all the room
+	q.PutEvent(1000, 1, m)
+	q.PutEvent(1000, 2, m)
+	q.PutEvent(2000, 1, m)
+
+	// the next round evicts the highest one
+	q.PutEvent(2, 1, m)
+
+	stats := q.Stats()
+	assert.Equal(t, 3, stats.Size)
+	assert.Equal(t, 2, stats.Rounds)
+	assert.Equal(t, uint64(2), stats.OldestRound)
+	assert.Equal(t, uint64(1), stats.Dropped)
+	assert.Empty(t, q.Flush(2000))
+
+	// a round after all the queued ones is dropped
+	q.PutEvent(3000, 1, m)
+	assert.Empty(t, q.Flush(3000))
+	assert.Equal(t, uint64(2), q.Stats().Dropped)
+
+	assert.Len(t, q.GetEvents(2, 1), 1)
+	assert.Len(t, q.Flush(1000), 2)
+}
+
+func TestQueuePrune(t *testing.T) {
+	q := consensus.NewQueue()
+	m := message.New(topics.Reduction, message.Reduction{})
+
+	for round := uint64(1); round <= 5; round++ {
+		q.PutEvent(round, 1, m)
+	}
+
+	q.Prune(4)
+
+	stats := q.Stats()
+	assert.Equal(t, 2, stats.Size)
+	assert.Equal(t, uint64(4), stats.OldestRound)
+
+	assert.Len(t, q.Flush(4), 1)
+	q.Clear(5)
+
+	stats = q.Stats()
+	assert.Zero(t, stats.Size)
+	assert.Zero(t, stats.Rounds)
+	assert.Zero(t, stats.OldestRound)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/candidate"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/journal"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
@@ -43,6 +44,9 @@ type Phase struct {
 
 	selectionResult message.Score
 
+	// start is the time the step started, to measure the quorum delay.
+	start time.Time
+
 	verifyFn  consensus.CandidateVerificationFunc
 	requestor *candidate.Requestor
 
@@ -90,7 +94,8 @@ func (p *Phase) Run(ctx context.Context, queue *consensus.Queue, evChan chan mes
 		p.SendReduction(r.Round, step, p.selectionResult.State().BlockHash)
 	}
 
-	timeoutChan := time.After(p.TimeOut)
+	p.start = time.Now()
+	timeoutChan := time.After(p.Timeouts.Get(p.String(), p.TimeOut))
 	p.aggregator = reduction.NewAggregator(p.handler)
 
 	for _, ev := range queue.GetEvents(r.Round, step) {
@@ -102,6 +107,7 @@ func (p *Phase) Run(ctx context.Context, queue *consensus.Queue, evChan chan mes
 			// up in the Queue, is a vulnerability since an attacker could
 			// flood the queue with future non-committee reductions
 			if !p.handler.IsMember(rMsg.Sender(), r.Round, step) {
+				p.Journal.Received(ev, journal.ErrNotMember)
 				continue
 			}
 
@@ -119,6 +125,7 @@ func (p *Phase) Run(ctx context.Context, queue *consensus.Queue, evChan chan mes
 			if reduction.ShouldProcess(ev, r.Round, step, queue) {
 				rMsg := ev.Payload().(message.Reduction)
 				if !p.handler.IsMember(rMsg.Sender(), r.Round, step) {
+					p.Journal.Received(ev, journal.ErrNotMember)
 					continue
 				}
 
@@ -133,6 +140,7 @@ func (p *Phase) Run(ctx context.Context, queue *consensus.Queue, evChan chan mes
 			}
 
 		case <-timeoutChan:
+			p.Journal.TimedOut(r.Round, step, p.String())
 			// in case of timeout we proceed in the consensus with an empty hash
 			sv := p.createStepVoteMessage(reduction.EmptyResult, r.Round, step)
 			return p.next.Initialize(*sv)
@@ -148,7 +156,10 @@ func (p *Phase) Run(ctx context.Context, queue *consensus.Queue, evChan chan mes
 }
 
 func (p *Phase) collectReduction(ctx context.Context, r message.Reduction, round uint64, step uint8) *message.StepVotesMsg {
-	if err := p.handler.VerifySignature(r.Copy().(message.Reduction)); err != nil {
+	err := p.handler.VerifySignature(r.Copy().(message.Reduction))
+	p.Journal.Received(message.New(topics.Reduction, r), err)
+
+	if err != nil {
 		lg.
 			WithError(err).
 			WithField("round", r.State().Round).
@@ -159,7 +170,7 @@ func (p *Phase) collectReduction(ctx context.Context, r message.Reduction, round
 	}
 
 	// Once the event is verified, we can republish it.
-	if err := p.Emitter.Gossip(message.New(topics.Reduction, r)); err != nil {
+	if err = p.Emitter.Gossip(message.New(topics.Reduction, r)); err != nil {
 		lg.WithError(err).Error("could not republish reduction event")
 	}
 
@@ -177,6 +188,8 @@ func (p *Phase) collectReduction(ctx context.Context, r message.Reduction, round
 		return nil
 	}
 
+	p.Timeouts.Observe(p.String(), time.Since(p.start))
+
 	// if the votes converged for an empty hash we invoke halt with no
 	// StepVotes
 	if bytes.Equal(hdr.BlockHash, reduction.EmptyHash[:]) {
@@ -184,8 +197,6 @@ func (p *Phase) collectReduction(ctx context.Context, r message.Reduction, round
 	}
 
 	if !bytes.Equal(hdr.BlockHash, p.selectionResult.Candidate.Header.Hash) {
-		var err error
-
 		p.selectionResult.Candidate, err = p.fetchCandidate(ctx, hdr.BlockHash)
 		if err != nil {
 			log.
@@ -246,6 +257,7 @@ func (p *Phase) requestCandidate(ctx context.Context, hash []byte) (block.Block,
 func (p *Phase) createStepVoteMessage(r *reduction.Result, round uint64, step uint8) *message.StepVotesMsg {
 	if r.IsEmpty() {
 		p.IncreaseTimeout(round)
+		p.Timeouts.Expired(p.String())
 	}
 
 	return &message.StepVotesMsg{
@@ -54,13 +54,13 @@ type Reduction struct {
 func (r *Reduction) IncreaseTimeout(round uint64) {
 	// if we converged on an empty block hash, we increase the timeout
 	r.TimeOut = r.TimeOut * 2
-	if r.TimeOut > 60*time.Second {
+	if r.TimeOut > r.Timeouts.Max() {
 		lg.
 			WithField("timeout", r.TimeOut).
 			WithField("round", round).
 			Error("max_timeout_reached")
 
-		r.TimeOut = 60 * time.Second
+		r.TimeOut = r.Timeouts.Max()
 	}
 }
 
@@ -14,6 +14,7 @@ import (
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/journal"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
@@ -38,6 +39,9 @@ type Phase struct {
 
 	firstStepVotesMsg message.StepVotesMsg
 
+	// start is the time the step started, to measure the quorum delay.
+	start time.Time
+
 	next consensus.Phase
 }
 
@@ -86,13 +90,15 @@ func (p *Phase) Run(ctx context.Context, queue *consensus.Queue, evChan chan mes
 		p.SendReduction(r.Round, step, p.firstStepVotesMsg.BlockHash)
 	}
 
-	timeoutChan := time.After(p.TimeOut)
+	p.start = time.Now()
+	timeoutChan := time.After(p.Timeouts.Get(p.String(), p.TimeOut))
 	p.aggregator = reduction.NewAggregator(p.handler)
 
 	for _, ev := range queue.GetEvents(r.Round, step) {
 		if ev.Category() == topics.Reduction {
 			rMsg := ev.Payload().(message.Reduction)
 			if !p.handler.IsMember(rMsg.Sender(), r.Round, step) {
+				p.Journal.Received(ev, journal.ErrNotMember)
 				continue
 			}
 
@@ -117,6 +123,7 @@ func (p *Phase) Run(ctx context.Context, queue *consensus.Queue, evChan chan mes
 			if reduction.ShouldProcess(ev, r.Round, step, queue) {
 				rMsg := ev.Payload().(message.Reduction)
 				if !p.handler.IsMember(rMsg.Sender(), r.Round, step) {
+					p.Journal.Received(ev, journal.ErrNotMember)
 					continue
 				}
 
@@ -137,8 +144,10 @@ func (p *Phase) Run(ctx context.Context, queue *consensus.Queue, evChan chan mes
 			}
 
 		case <-timeoutChan:
+			p.Journal.TimedOut(r.Round, step, p.String())
 			// in case of timeout we increase the timeout and that's it
 			p.IncreaseTimeout(r.Round)
+			p.Timeouts.Expired(p.String())
 			return p.next.Initialize(nil)
 
 		case <-ctx.Done():
@@ -155,7 +164,10 @@ func (p *Phase) Run(ctx context.Context, queue *consensus.Queue, evChan chan mes
 func (p *Phase) collectReduction(r message.Reduction, round uint64, step uint8) *message.StepVotesMsg {
 	hdr := r.State()
 
-	if err := p.handler.VerifySignature(r.Copy().(message.Reduction)); err != nil {
+	err := p.handler.VerifySignature(r.Copy().(message.Reduction))
+	p.Journal.Received(message.New(topics.Reduction, r), err)
+
+	if err != nil {
 		lg.
 			WithError(err).
 			WithFields(log.Fields{
@@ -169,7 +181,7 @@ func (p *Phase) collectReduction(r message.Reduction, round uint64, step uint8)
 	}
 
 	// Once the event is verified, we can republish it.
-	if err := p.Emitter.Gossip(message.New(topics.Reduction, r)); err != nil {
+	if err = p.Emitter.Gossip(message.New(topics.Reduction, r)); err != nil {
 		lg.WithError(err).Error("could not republish reduction event")
 	}
 
@@ -196,6 +208,8 @@ func (p *Phase) createStepVoteMessage(r *reduction.Result, round uint64, step ui
 		"result_empty?": r.IsEmpty(),
 	}).Debugln("quorum reached")
 
+	p.Timeouts.Observe(p.String(), time.Since(p.start))
+
 	// quorum has been reached. However hash&votes can be empty
 	return &message.StepVotesMsg{
 		Header: header.Header{
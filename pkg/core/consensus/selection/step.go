@@ -8,6 +8,7 @@ package selection
 
 import (
 	"context"
+	"errors"
 	"os"
 	"strconv"
 	"time"
@@ -28,6 +29,10 @@ import (
 
 var lg = log.WithField("process", "selector")
 
+// errLowerScore is recorded into the journal for the scores discarded, as
+// lower than the best score collected.
+var errLowerScore = errors.New("score lower than the best one")
+
 // Phase is the implementation of the Selection step component.
 type Phase struct {
 	*consensus.Emitter
@@ -154,6 +159,7 @@ func (p *Phase) Run(parentCtx context.Context, queue *consensus.Queue, evChan ch
 			}
 
 		case <-timeoutChan:
+			p.Journal.TimedOut(r.Round, step, p.String())
 			return p.endSelection(r.Round, step)
 		case <-ctx.Done():
 			// preventing timeout leakage
@@ -194,6 +200,7 @@ func (p *Phase) collectScore(ctx context.Context, sc message.Score, msgHeader []
 	// Sanity-check the candidate message
 	if err := candidate.ValidateCandidate(sc.Candidate); err != nil {
 		lg.Warn("Invalid candidate message")
+		p.Journal.Received(message.New(topics.Score, sc), err)
 		return
 	}
 
@@ -208,12 +215,16 @@ func (p *Phase) collectScore(ctx context.Context, sc message.Score, msgHeader []
 	if !p.bestEvent.IsEmpty() {
 		if p.handler.Priority(p.bestEvent, sc) {
 			// if the current best score has priority, we return
+			p.Journal.Received(message.New(topics.Score, sc), errLowerScore)
 			return
 		}
 	}
 
 	header := sc.State()
-	if err := p.handler.Verify(ctx, header.Round, header.Step, sc); err != nil {
+	err := p.handler.Verify(ctx, header.Round, header.Step, sc)
+	p.Journal.Received(message.New(topics.Score, sc), err)
+
+	if err != nil {
 		lg.WithError(err).Warn("Invalid score message")
 		return
 	}
@@ -224,7 +235,7 @@ func (p *Phase) collectScore(ctx context.Context, sc message.Score, msgHeader []
 	// Once the event is verified, and has passed all preliminary checks,
 	// we can republish it to the network.
 
-	if err := p.Republish(message.New(topics.Score, sc), msgHeader); err != nil {
+	if err = p.Republish(message.New(topics.Score, sc), msgHeader); err != nil {
 		lg.WithError(err).WithField("kadcast_enabled", config.Get().Kadcast.Enabled).
 			Error("could not republish score event")
 	}
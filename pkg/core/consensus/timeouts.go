@@ -0,0 +1,299 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package consensus
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	log "github.com/sirupsen/logrus"
+)
+
+var lg = log.WithField("process", "consensus timeouts")
+
+const (
+	defaultTimeoutWindow     = 20
+	defaultTimeoutMultiplier = 2
+	defaultMinTimeout        = time.Second
+	defaultMaxTimeout        = 60 * time.Second
+)
+
+// StepTimeout is the current timeout of a consensus step.
+type StepTimeout struct {
+	Step string `json:"step"`
+	// Timeout is the timeout of the step, in milliseconds.
+	Timeout int64 `json:"timeout"`
+	// Observed is the 90th percentile of the recent quorum delays of the
+	// step, in milliseconds. It is zero without samples.
+	Observed int64 `json:"observed"`
+	// Samples is the number of recent quorum delays.
+	Samples int `json:"samples"`
+	// Expired is true if the step timed out during the current round.
+	Expired bool `json:"expired"`
+}
+
+// Timeouts derives the timeout of each reduction step from the time the step
+// took to reach quorum in the recent rounds. The timeout of a step is its
+// 90th percentile quorum delay, times a multiplier, bounded by a minimum and
+// a maximum. A step which times out doubles its timeout, up to the maximum,
+// until the end of a successful round.
+//
+// A step which times out records its timeout as a sample: the quorum took at
+// least as long, and leaving these rounds out would only keep the fast ones
+// in the window, and the timeout too low to ever reach quorum again.
+//
+// The selection is not driven by Timeouts. It waits for the best score until
+// its timer expires, so that the provisioners select among the same scores,
+// and it has no quorum delay to derive its timeout from.
+//
+// All the methods of a nil Timeouts are no-ops, the steps falling back to
+// their own timer.
+type Timeouts struct {
+	lock sync.Mutex
+
+	base       time.Duration
+	adaptive   bool
+	window     int
+	multiplier float64
+	min, max   time.Duration
+
+	// samples are the recent quorum delays of each step.
+	samples map[string][]time.Duration
+	// expired are the timeouts increased during the current round.
+	expired map[string]time.Duration
+	// steps are the steps seen so far, for the status.
+	steps map[string]struct{}
+}
+
+// NewTimeouts creates the step timeouts from the configuration. base is the
+// timeout of the steps without samples.
+func NewTimeouts(base time.Duration) *Timeouts {
+	conf := config.Get().Consensus.Timeouts
+
+	t := &Timeouts{
+		base:       base,
+		adaptive:   conf.Adaptive,
+		window:     conf.Window,
+		multiplier: conf.Multiplier,
+		min:        time.Duration(conf.MinTimeout) * time.Millisecond,
+		max:        time.Duration(conf.MaxTimeout) * time.Millisecond,
+		samples:    make(map[string][]time.Duration),
+		expired:    make(map[string]time.Duration),
+		steps:      make(map[string]struct{}),
+	}
+
+	if t.window <= 0 {
+		t.window = defaultTimeoutWindow
+	}
+
+	if t.multiplier <= 0 {
+		t.multiplier = defaultTimeoutMultiplier
+	}
+
+	if t.min <= 0 {
+		t.min = defaultMinTimeout
+	}
+
+	if t.max <= 0 {
+		t.max = defaultMaxTimeout
+	}
+
+	if t.max < t.min {
+		t.max = t.min
+	}
+
+	return t
+}
+
+// Get returns the timeout of the step. fallback is returned by a nil
+// Timeouts.
+func (t *Timeouts) Get(step string, fallback time.Duration) time.Duration {
+	if t == nil {
+		return fallback
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.steps[step] = struct{}{}
+	return t.get(step)
+}
+
+// Observe records the time the step took to reach quorum.
+func (t *Timeouts) Observe(step string, d time.Duration) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.steps[step] = struct{}{}
+	t.observe(step, d)
+}
+
+// Expired records the timeout of a step which timed out as a sample, and
+// doubles it, up to the maximum. The increase lasts until Reset.
+func (t *Timeouts) Expired(step string) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.steps[step] = struct{}{}
+
+	timeout := t.get(step)
+	t.observe(step, timeout)
+
+	timeout *= 2
+	if timeout > t.max {
+		lg.
+			WithField("step", step).
+			WithField("timeout", timeout).
+			Error("max_timeout_reached")
+
+		timeout = t.max
+	}
+
+	t.expired[step] = timeout
+
+	lg.
+		WithField("step", step).
+		WithField("timeout", timeout).
+		Trace("increase_timeout")
+}
+
+// Reset drops the timeouts increased during the round, so that the steps of
+// the next round start with the timeouts derived from their samples. It is
+// called at the end of a successful round.
+func (t *Timeouts) Reset() {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.expired = make(map[string]time.Duration)
+}
+
+// Max returns the maximum timeout of a step.
+func (t *Timeouts) Max() time.Duration {
+	if t == nil {
+		return defaultMaxTimeout
+	}
+
+	return t.max
+}
+
+// Status returns the current timeout of the steps seen so far, sorted by
+// step.
+func (t *Timeouts) Status() []StepTimeout {
+	status := make([]StepTimeout, 0)
+	if t == nil {
+		return status
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for step := range t.steps {
+		_, expired := t.expired[step]
+
+		status = append(status, StepTimeout{
+			Step:     step,
+			Timeout:  t.get(step).Milliseconds(),
+			Observed: percentile90(t.samples[step]).Milliseconds(),
+			Samples:  len(t.samples[step]),
+			Expired:  expired,
+		})
+	}
+
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Step < status[j].Step
+	})
+
+	return status
+}
+
+// Serve answers the topics.GetConsensusTimeouts requests of the rpcbus,
+// until the context is canceled.
+func (t *Timeouts) Serve(ctx context.Context, rpcBus *rpcbus.RPCBus) error {
+	reqChan := make(chan rpcbus.Request, 1)
+	if err := rpcBus.Register(topics.GetConsensusTimeouts, reqChan); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case r := <-reqChan:
+				r.RespChan <- rpcbus.NewResponse(t.Status(), nil)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// observe records a quorum delay of the step, dropping the oldest one beyond
+// the window. It must be called with the lock held.
+func (t *Timeouts) observe(step string, d time.Duration) {
+	samples := append(t.samples[step], d)
+	if len(samples) > t.window {
+		samples = samples[len(samples)-t.window:]
+	}
+
+	t.samples[step] = samples
+}
+
+// get returns the timeout of the step. It must be called with the lock held.
+func (t *Timeouts) get(step string) time.Duration {
+	if timeout, ok := t.expired[step]; ok {
+		return timeout
+	}
+
+	samples := t.samples[step]
+	if !t.adaptive || len(samples) == 0 {
+		return t.base
+	}
+
+	timeout := time.Duration(float64(percentile90(samples)) * t.multiplier)
+
+	switch {
+	case timeout < t.min:
+		return t.min
+	case timeout > t.max:
+		return t.max
+	default:
+		return timeout
+	}
+}
+
+func percentile90(samples []time.Duration) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	return sorted[int(math.Ceil(0.9*float64(len(sorted))))-1]
+}
@@ -0,0 +1,147 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package consensus_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockTimeoutsConfig(adaptive bool) func() {
+	conf := config.Get()
+
+	r := conf
+	r.Consensus.Timeouts.Adaptive = adaptive
+	r.Consensus.Timeouts.Window = 10
+	r.Consensus.Timeouts.Multiplier = 2
+	r.Consensus.Timeouts.MinTimeout = 1000
+	r.Consensus.Timeouts.MaxTimeout = 20000
+
+	config.Mock(&r)
+
+	return func() {
+		config.Mock(&conf)
+	}
+}
+
+func TestAdaptiveTimeouts(t *testing.T) {
+	defer mockTimeoutsConfig(true)()
+
+	timeouts := consensus.NewTimeouts(5 * time.Second)
+
+	// without samples, the base timeout is used
+	assert.Equal(t, 5*time.Second, timeouts.Get("reduction-first-step", 0))
+
+	for i := 1; i <= 10; i++ {
+		timeouts.Observe("reduction-first-step", time.Duration(i)*100*time.Millisecond)
+	}
+
+	// twice the 90th percentile
+	assert.Equal(t, 1800*time.Millisecond, timeouts.Get("reduction-first-step", 0))
+
+	// the other steps are not affected
+	assert.Equal(t, 5*time.Second, timeouts.Get("reduction-second-step", 0))
+
+	// the oldest samples are dropped
+	for i := 0; i < 10; i++ {
+		timeouts.Observe("reduction-first-step", 3*time.Second)
+	}
+
+	assert.Equal(t, 6*time.Second, timeouts.Get("reduction-first-step", 0))
+}
+
+func TestTimeoutsBounds(t *testing.T) {
+	defer mockTimeoutsConfig(true)()
+
+	timeouts := consensus.NewTimeouts(5 * time.Second)
+
+	timeouts.Observe("reduction-first-step", 10*time.Millisecond)
+	assert.Equal(t, time.Second, timeouts.Get("reduction-first-step", 0))
+
+	timeouts.Observe("reduction-second-step", time.Minute)
+	assert.Equal(t, 20*time.Second, timeouts.Get("reduction-second-step", 0))
+}
+
+func TestExpiredTimeouts(t *testing.T) {
+	defer mockTimeoutsConfig(true)()
+
+	timeouts := consensus.NewTimeouts(5 * time.Second)
+
+	timeouts.Expired("reduction-first-step")
+	assert.Equal(t, 10*time.Second, timeouts.Get("reduction-first-step", 0))
+
+	// the samples do not lower an expired timeout
+	timeouts.Observe("reduction-first-step", 100*time.Millisecond)
+	assert.Equal(t, 10*time.Second, timeouts.Get("reduction-first-step", 0))
+
+	timeouts.Expired("reduction-first-step")
+	timeouts.Expired("reduction-first-step")
+	assert.Equal(t, 20*time.Second, timeouts.Get("reduction-first-step", 0))
+
+	// the expired timeouts are recorded as samples, along with the observed
+	// one
+	status := timeouts.Status()
+	assert.Len(t, status, 1)
+	assert.Equal(t, "reduction-first-step", status[0].Step)
+	assert.Equal(t, int64(20000), status[0].Timeout)
+	assert.Equal(t, 4, status[0].Samples)
+	assert.True(t, status[0].Expired)
+
+	timeouts.Reset()
+	assert.False(t, timeouts.Status()[0].Expired)
+}
+
+func TestExpiredSamples(t *testing.T) {
+	defer mockTimeoutsConfig(true)()
+
+	timeouts := consensus.NewTimeouts(5 * time.Second)
+
+	for i := 0; i < 10; i++ {
+		timeouts.Observe("reduction-first-step", 100*time.Millisecond)
+	}
+
+	assert.Equal(t, time.Second, timeouts.Get("reduction-first-step", 0))
+
+	// the step times out twice before reaching quorum
+	timeouts.Expired("reduction-first-step")
+	timeouts.Expired("reduction-first-step")
+	assert.Equal(t, 4*time.Second, timeouts.Get("reduction-first-step", 0))
+
+	// a successful round resets the timeouts to the samples, which include
+	// the expired timeouts: twice their 90th percentile is now 2s, rather
+	// than the 200ms of the fast rounds
+	timeouts.Reset()
+	assert.Equal(t, 2*time.Second, timeouts.Get("reduction-first-step", 0))
+}
+
+func TestStaticTimeouts(t *testing.T) {
+	defer mockTimeoutsConfig(false)()
+
+	timeouts := consensus.NewTimeouts(5 * time.Second)
+
+	timeouts.Observe("reduction-first-step", 100*time.Millisecond)
+	assert.Equal(t, 5*time.Second, timeouts.Get("reduction-first-step", 0))
+
+	timeouts.Expired("reduction-first-step")
+	assert.Equal(t, 10*time.Second, timeouts.Get("reduction-first-step", 0))
+}
+
+func TestNilTimeouts(t *testing.T) {
+	var timeouts *consensus.Timeouts
+
+	timeouts.Observe("reduction-first-step", time.Second)
+	timeouts.Expired("reduction-first-step")
+	timeouts.Reset()
+
+	assert.Equal(t, 5*time.Second, timeouts.Get("reduction-first-step", 5*time.Second))
+	assert.Equal(t, 60*time.Second, timeouts.Max())
+	assert.Empty(t, timeouts.Status())
+}
@@ -0,0 +1,165 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package bbolt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	bolt "go.etcd.io/bbolt"
+)
+
+// fileName is the name of the single storage file created in the database
+// directory.
+const fileName = "chain.db"
+
+// openTimeout is the time to wait for the storage file lock.
+const openTimeout = 5 * time.Second
+
+var (
+	// See openStorage for detailed explanation.
+	_storage   *bolt.DB
+	_storageMu sync.Mutex
+)
+
+// DB on top of underlying storage go.etcd.io/bbolt.
+type DB struct {
+	// an alias to the global storage var.
+	storage *bolt.DB
+
+	// Read-only mode provided at bbolt.DB level. If true, accepts read-only
+	// Transaction.
+	readOnly bool
+}
+
+// openStorage is a wrapper around bolt.Open to provide a singleton bolt.DB
+// instance.
+//
+// bolt.Open acquires an exclusive lock on the storage file, so any subsequent
+// attempt to open the same path, even from the same process, would block.
+func openStorage(path string) (*bolt.DB, error) {
+	_storageMu.Lock()
+	defer _storageMu.Unlock()
+
+	if _storage != nil {
+		return _storage, nil
+	}
+
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, err
+	}
+
+	s, err := bolt.Open(filepath.Join(path, fileName), 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	// All buckets are created upfront, so that read-only transactions can
+	// rely on them
+	err = s.Update(func(tx *bolt.Tx) error {
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+
+	_storage = s
+	return _storage, nil
+}
+
+// closeStorage should safely close the underlying storage.
+func closeStorage() error {
+	_storageMu.Lock()
+	defer _storageMu.Unlock()
+
+	if _storage != nil {
+		err := _storage.Close()
+		_storage = nil
+		return err
+	}
+
+	return errors.New("invalid storage")
+}
+
+// NewDatabase creates or opens the backend storage (bbolt) located in the
+// specified directory. Readonly option is pseudo read-only mode implemented
+// by bbolt.DB, as the storage is shared by all DB instances.
+func NewDatabase(path string, network protocol.Magic, readonly bool) (database.DB, error) {
+	storage, err := openStorage(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return DB{storage, readonly}, nil
+}
+
+// Begin builds read-only or read-write Transaction. bbolt allows a single
+// read-write transaction at a time, so that Begin(true) blocks until any
+// other read-write transaction is done.
+func (db DB) Begin(writable bool) (database.Transaction, error) {
+	if db.readOnly && writable {
+		return nil, errors.New("database is read-only")
+	}
+
+	if db.storage == nil {
+		return nil, errors.New("database is not open")
+	}
+
+	tx, err := db.storage.Begin(writable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction{tx: tx}, nil
+}
+
+// Update a record within a transaction.
+func (db DB) Update(fn func(database.Transaction) error) error {
+	t, err := db.Begin(true)
+	if err != nil {
+		return err
+	}
+
+	// Close rolls back the transaction, unless it was committed
+	defer t.Close()
+
+	if err := fn(t); err != nil {
+		return err
+	}
+
+	return t.Commit()
+}
+
+// View is the equivalent of a Select SQL statement.
+func (db DB) View(fn func(database.Transaction) error) error {
+	t, err := db.Begin(false)
+	if err != nil {
+		return err
+	}
+
+	defer t.Close()
+	return fn(t)
+}
+
+// Close does not close the underlying storage as it is shared by all DB
+// instances. See driver.Close.
+func (db DB) Close() error {
+	db.storage = nil
+	return nil
+}
@@ -0,0 +1,37 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package bbolt
+
+import (
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	log "github.com/sirupsen/logrus"
+)
+
+// DriverName is the unique identifier for the bbolt driver.
+var DriverName = "bbolt_v0.1.0"
+
+type driver struct{}
+
+func (d *driver) Open(path string, network protocol.Magic, readonly bool) (database.DB, error) {
+	return NewDatabase(path, network, readonly)
+}
+
+func (d *driver) Close() error {
+	return closeStorage()
+}
+
+func (d *driver) Name() string {
+	return DriverName
+}
+
+func init() {
+	d := driver{}
+	if err := database.Register(&d); err != nil {
+		log.Panic(err)
+	}
+}
@@ -0,0 +1,643 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package bbolt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/utils"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// Buckets of the storage. Refer to bbolt.md for an overview of the
+	// Key-Value data schemas.
+
+	headerBucket       = []byte("header")
+	txBucket           = []byte("tx")
+	heightBucket       = []byte("height")
+	txIDBucket         = []byte("txid")
+	keyImageBucket     = []byte("keyimage")
+	stateBucket        = []byte("state")
+	outputKeyBucket    = []byte("outputkey")
+	bidValuesBucket    = []byte("bidvalues")
+	candidateBucket    = []byte("candidate")
+	txTypeBucket       = []byte("txtype")
+	provisionersBucket = []byte("provisioners")
+
+	buckets = [][]byte{
+		headerBucket, txBucket, heightBucket, txIDBucket, keyImageBucket,
+		stateBucket, outputKeyBucket, bidValuesBucket, candidateBucket,
+		txTypeBucket, provisionersBucket,
+	}
+
+	// tipKey is the key of the chain tip hash in stateBucket.
+	tipKey = []byte("tip")
+)
+
+// BidEncodingSize is the expected size of the serialized encoding of the bid.
+var BidEncodingSize = 72
+
+type transaction struct {
+	tx *bolt.Tx
+}
+
+// heightKey encodes a height as big-endian, so that keys are sorted by height.
+func heightKey(height uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, height)
+
+	return key
+}
+
+// txTypeKey builds a txTypeBucket key sorted by (txType, height, index).
+func txTypeKey(txType transactions.TxType, height uint64, txIndex uint32) []byte {
+	key := make([]byte, 1+8+4)
+
+	key[0] = byte(txType)
+	binary.BigEndian.PutUint64(key[1:], height)
+	binary.BigEndian.PutUint32(key[9:], txIndex)
+
+	return key
+}
+
+// concat returns a new slice holding a followed by b.
+func concat(a, b []byte) []byte {
+	key := make([]byte, 0, len(a)+len(b))
+	return append(append(key, a...), b...)
+}
+
+// get returns a copy of the value of key, or nil if not found. Values
+// returned by bbolt are valid for the life of the transaction only.
+func (t transaction) get(bucket, key []byte) []byte {
+	value := t.tx.Bucket(bucket).Get(key)
+	if value == nil {
+		return nil
+	}
+
+	return append([]byte{}, value...)
+}
+
+func (t transaction) put(bucket, key, value []byte) error {
+	return t.tx.Bucket(bucket).Put(key, value)
+}
+
+func (t transaction) delete(bucket, key []byte) error {
+	return t.tx.Bucket(bucket).Delete(key)
+}
+
+// StoreBlock stores the entire block data into storage. No validations are
+// applied. Storage state changes only when Commit() is called on Transaction
+// completion.
+func (t transaction) StoreBlock(b *block.Block) error {
+	if !t.tx.Writable() {
+		return errors.New("StoreBlock cannot be called on read-only transaction")
+	}
+
+	if len(b.Header.Hash) != block.HeaderHashSize {
+		return fmt.Errorf("header hash size is %d but it must be %d", len(b.Header.Hash), block.HeaderHashSize)
+	}
+
+	// Key = block.header.hash
+	// Value = encoded(block.header)
+	buf := new(bytes.Buffer)
+	if err := message.MarshalHeader(buf, b.Header); err != nil {
+		return err
+	}
+
+	if err := t.put(headerBucket, b.Header.Hash, buf.Bytes()); err != nil {
+		return err
+	}
+
+	if uint64(len(b.Txs)) > math.MaxUint32 {
+		return errors.New("too many transactions")
+	}
+
+	for i, tx := range b.Txs {
+		txID, err := tx.CalculateHash()
+		if err != nil {
+			return err
+		}
+
+		if len(txID) == 0 {
+			return fmt.Errorf("empty chain tx id")
+		}
+
+		entry, err := utils.EncodeBlockTx(tx, uint32(i))
+		if err != nil {
+			return err
+		}
+
+		// Key = block.header.hash + txID
+		// Value = index + block.transaction[index]
+		if err := t.put(txBucket, concat(b.Header.Hash, txID), entry); err != nil {
+			return err
+		}
+
+		// Key = txID
+		// Value = block.header.hash
+		if err := t.put(txIDBucket, txID, b.Header.Hash); err != nil {
+			return err
+		}
+
+		// Key = txType + block.header.height + index
+		// Value = txID
+		if err := t.put(txTypeBucket, txTypeKey(tx.Type(), b.Header.Height, uint32(i)), txID); err != nil {
+			return err
+		}
+
+		// Key = keyImage
+		// Value = txID
+		for _, keyImage := range tx.StandardTx().Nullifiers {
+			if err := t.put(keyImageBucket, keyImage, txID); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Key = block.header.height
+	// Value = block.header.hash
+	if err := t.put(heightBucket, heightKey(b.Header.Height), b.Header.Hash); err != nil {
+		return err
+	}
+
+	// Key = tipKey
+	// Value = Hash(chain tip)
+	if err := t.put(stateBucket, tipKey, b.Header.Hash); err != nil {
+		return err
+	}
+
+	// Delete expired bid values. Keys are collected first, as deleting while
+	// iterating could skip entries.
+	expired := make([][]byte, 0)
+
+	c := t.tx.Bucket(bidValuesBucket).Cursor()
+	for k, _ := c.First(); k != nil && binary.BigEndian.Uint64(k) < b.Header.Height; k, _ = c.Next() {
+		expired = append(expired, append([]byte{}, k...))
+	}
+
+	for _, k := range expired {
+		if err := t.delete(bidValuesBucket, k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteBlock removes all KV pairs put by StoreBlock for a block and sets
+// the chain tip to block.header.prevBlockHash.
+func (t transaction) DeleteBlock(b *block.Block) error {
+	if !t.tx.Writable() {
+		return errors.New("DeleteBlock cannot be called on read-only transaction")
+	}
+
+	if err := t.delete(headerBucket, b.Header.Hash); err != nil {
+		return err
+	}
+
+	for i, tx := range b.Txs {
+		txID, err := tx.CalculateHash()
+		if err != nil {
+			return err
+		}
+
+		if err := t.delete(txBucket, concat(b.Header.Hash, txID)); err != nil {
+			return err
+		}
+
+		if err := t.delete(txIDBucket, txID); err != nil {
+			return err
+		}
+
+		if err := t.delete(txTypeBucket, txTypeKey(tx.Type(), b.Header.Height, uint32(i))); err != nil {
+			return err
+		}
+
+		// Only the key images spent by the tx are deleted
+		for _, keyImage := range tx.StandardTx().Nullifiers {
+			if !bytes.Equal(t.get(keyImageBucket, keyImage), txID) {
+				continue
+			}
+
+			if err := t.delete(keyImageBucket, keyImage); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := t.delete(heightBucket, heightKey(b.Header.Height)); err != nil {
+		return err
+	}
+
+	if err := t.delete(provisionersBucket, heightKey(b.Header.Height)); err != nil {
+		return err
+	}
+
+	return t.put(stateBucket, tipKey, b.Header.PrevBlockHash)
+}
+
+// Commit writes the transaction changes to the storage file. The file is
+// synced before Commit returns.
+func (t *transaction) Commit() error {
+	if !t.tx.Writable() {
+		return errors.New("read-only transaction cannot commit changes")
+	}
+
+	return t.tx.Commit()
+}
+
+// Rollback discards the transaction changes.
+func (t transaction) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// Close releases the transaction. It must be called explicitly when a
+// transaction is run in a unmanaged way. Changes not committed are discarded.
+func (t *transaction) Close() {
+	// bolt.ErrTxClosed is returned if the transaction was committed already
+	_ = t.tx.Rollback()
+}
+
+func (t transaction) FetchBlockExists(hash []byte) (bool, error) {
+	if t.tx.Bucket(headerBucket).Get(hash) == nil {
+		return false, database.ErrBlockNotFound
+	}
+
+	return true, nil
+}
+
+// FetchOutputExists checks if an output exists in the db.
+func (t transaction) FetchOutputExists(destkey []byte) (bool, error) {
+	if t.tx.Bucket(outputKeyBucket).Get(destkey) == nil {
+		return false, database.ErrOutputNotFound
+	}
+
+	return true, nil
+}
+
+// FetchOutputUnlockHeight returns the unlockheight of an output.
+func (t transaction) FetchOutputUnlockHeight(destkey []byte) (uint64, error) {
+	value := t.tx.Bucket(outputKeyBucket).Get(destkey)
+	if value == nil {
+		return 0, database.ErrOutputNotFound
+	}
+
+	if len(value) != 8 {
+		return 0, errors.New("unlock height malformed")
+	}
+
+	return binary.LittleEndian.Uint64(value), nil
+}
+
+func (t transaction) FetchBlockHeader(hash []byte) (*block.Header, error) {
+	value := t.get(headerBucket, hash)
+	if value == nil {
+		return nil, database.ErrBlockNotFound
+	}
+
+	header := block.NewHeader()
+	if err := message.UnmarshalHeader(bytes.NewBuffer(value), header); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+func (t transaction) FetchBlockTxs(hashHeader []byte) ([]transactions.ContractCall, error) {
+	tempTxs := make(map[uint32]transactions.ContractCall)
+
+	// Read all the transactions that belong to a single block
+	c := t.tx.Bucket(txBucket).Cursor()
+	for k, v := c.Seek(hashHeader); k != nil && bytes.HasPrefix(k, hashHeader); k, v = c.Next() {
+		tx, txIndex, err := utils.DecodeBlockTx(append([]byte{}, v...), database.AnyTxType)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := tempTxs[txIndex]; ok {
+			return nil, errors.New("duplicated tx index")
+		}
+
+		tempTxs[txIndex] = tx
+	}
+
+	// Reorder Tx slice as per retrieved indexes
+	resultTxs := make([]transactions.ContractCall, len(tempTxs))
+	for k, v := range tempTxs {
+		if int(k) >= len(resultTxs) {
+			return nil, errors.New("tx index out of range")
+		}
+
+		resultTxs[k] = v
+	}
+
+	// Let's ensure coinbase tx is here
+	if len(resultTxs) > 0 {
+		// NOTE: coinbase is the last tx in the block, with the upgrade to
+		// a VM-based tx layer
+		if resultTxs[len(resultTxs)-1].Type() != transactions.Distribute {
+			return resultTxs, errors.New("missing coinbase tx")
+		}
+	}
+
+	return resultTxs, nil
+}
+
+func (t transaction) FetchBlockHashByHeight(height uint64) ([]byte, error) {
+	value := t.get(heightBucket, heightKey(height))
+	if value == nil {
+		return nil, database.ErrBlockNotFound
+	}
+
+	return value, nil
+}
+
+func (t transaction) FetchBlockTxByHash(txID []byte) (transactions.ContractCall, uint32, []byte, error) {
+	txIndex := uint32(math.MaxUint32)
+
+	// Fetch the block header hash that this Tx belongs to
+	hashHeader := t.get(txIDBucket, txID)
+	if hashHeader == nil {
+		return nil, txIndex, nil, database.ErrTxNotFound
+	}
+
+	value := t.get(txBucket, concat(hashHeader, txID))
+	if value == nil {
+		return nil, txIndex, nil, errors.New("block tx is available but fetching it fails")
+	}
+
+	tx, idx, err := utils.DecodeBlockTx(value, database.AnyTxType)
+	if err != nil {
+		return nil, idx, hashHeader, err
+	}
+
+	return tx, idx, hashHeader, nil
+}
+
+// FetchTxsByType iterates over all txs of type txType stored in the height
+// range [fromHeight, toHeight]. See also database.Transaction.
+func (t transaction) FetchTxsByType(txType transactions.TxType, fromHeight, toHeight uint64, fn func(tx transactions.ContractCall, height uint64, txIndex uint32) error) error {
+	if fromHeight > toHeight {
+		return nil
+	}
+
+	c := t.tx.Bucket(txTypeBucket).Cursor()
+
+	for k, v := c.Seek(txTypeKey(txType, fromHeight, 0)); k != nil && k[0] == byte(txType); k, v = c.Next() {
+		height := binary.BigEndian.Uint64(k[1:])
+		if height > toHeight {
+			break
+		}
+
+		txIndex := binary.BigEndian.Uint32(k[9:])
+
+		hash, err := t.FetchBlockHashByHeight(height)
+		if err != nil {
+			return err
+		}
+
+		value := t.get(txBucket, concat(hash, v))
+		if value == nil {
+			return database.ErrTxNotFound
+		}
+
+		tx, _, err := utils.DecodeBlockTx(value, txType)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(tx, height, txIndex); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FetchKeyImageExists checks if the KeyImage exists. If so, it also returns the
+// hash of its corresponding tx.
+func (t transaction) FetchKeyImageExists(keyImage []byte) (bool, []byte, error) {
+	txID := t.get(keyImageBucket, keyImage)
+	if txID == nil {
+		return false, nil, database.ErrKeyImageNotFound
+	}
+
+	return true, txID, nil
+}
+
+func (t transaction) FetchBlock(hash []byte) (*block.Block, error) {
+	header, err := t.FetchBlockHeader(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	txs, err := t.FetchBlockTxs(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &block.Block{
+		Header: header,
+		Txs:    txs,
+	}, nil
+}
+
+func (t transaction) FetchState() (*database.State, error) {
+	value := t.get(stateBucket, tipKey)
+	if len(value) == 0 {
+		return nil, database.ErrStateNotFound
+	}
+
+	return &database.State{TipHash: value}, nil
+}
+
+func (t transaction) FetchCurrentHeight() (uint64, error) {
+	state, err := t.FetchState()
+	if err != nil {
+		return 0, err
+	}
+
+	header, err := t.FetchBlockHeader(state.TipHash)
+	if err != nil {
+		return 0, err
+	}
+
+	return header.Height, nil
+}
+
+// FetchPrunedHeight returns 0, as pruning is not supported by this driver.
+func (t transaction) FetchPrunedHeight() (uint64, error) {
+	return 0, nil
+}
+
+func (t transaction) StoreBidValues(d, k []byte, index uint64, lockTime uint64) error {
+	currentHeight, err := t.FetchCurrentHeight()
+	if err != nil {
+		return err
+	}
+
+	// NOTE: this expiry height is not accurate, and is just an
+	// approximation. See also heavy driver.
+	value := make([]byte, 0, BidEncodingSize)
+	value = append(value, d...)
+	value = append(value, k...)
+
+	idxBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(idxBytes, index)
+
+	return t.put(bidValuesBucket, heightKey(lockTime+currentHeight), append(value, idxBytes...))
+}
+
+// FetchBidValues returns the bid values with the lowest expiry height, as
+// those are most likely to be valid.
+func (t transaction) FetchBidValues() ([]byte, []byte, uint64, error) {
+	_, v := t.tx.Bucket(bidValuesBucket).Cursor().First()
+	value := append([]byte{}, v...)
+
+	// Let's avoid any runtime panics by doing a sanity check on the value length before
+	if len(value) != BidEncodingSize {
+		return nil, nil, uint64(0), fmt.Errorf("bid values non-existent or incorrectly encoded, expected %d bytes but found %d", BidEncodingSize, len(value))
+	}
+
+	D := value[0:32]
+	K := value[32:64]
+	index := binary.LittleEndian.Uint64(value[64:72])
+	return D, K, index, nil
+}
+
+// FetchBlockHeightSince uses binary search to find a block height.
+func (t transaction) FetchBlockHeightSince(sinceUnixTime int64, offset uint64) (uint64, error) {
+	tip, err := t.FetchCurrentHeight()
+	if err != nil {
+		return 0, err
+	}
+
+	n := uint64(math.Min(float64(tip), float64(offset)))
+
+	pos, err := utils.Search(n, func(pos uint64) (bool, error) {
+		height := tip - n + pos
+
+		hash, heightErr := t.FetchBlockHashByHeight(height)
+		if heightErr != nil {
+			return false, heightErr
+		}
+
+		header, blockHdrErr := t.FetchBlockHeader(hash)
+		if blockHdrErr != nil {
+			return false, blockHdrErr
+		}
+
+		return header.Timestamp >= sinceUnixTime, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return tip - n + pos, nil
+}
+
+// StoreProvisioners stores a snapshot of the provisioners set at height.
+func (t transaction) StoreProvisioners(height uint64, p *user.Provisioners) error {
+	if !t.tx.Writable() {
+		return errors.New("StoreProvisioners cannot be called on read-only transaction")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := user.MarshalProvisioners(buf, p); err != nil {
+		return err
+	}
+
+	return t.put(provisionersBucket, heightKey(height), buf.Bytes())
+}
+
+// FetchProvisioners returns the most recent provisioners snapshot stored at a
+// height lower or equal to height.
+func (t transaction) FetchProvisioners(height uint64) (*user.Provisioners, uint64, error) {
+	c := t.tx.Bucket(provisionersBucket).Cursor()
+
+	// Position the cursor on the first snapshot above height, then step back
+	var k, v []byte
+	if height == math.MaxUint64 {
+		k, v = c.Last()
+	} else if k, _ = c.Seek(heightKey(height + 1)); k == nil {
+		k, v = c.Last()
+	} else {
+		k, v = c.Prev()
+	}
+
+	if k == nil {
+		return nil, 0, database.ErrProvisionersNotFound
+	}
+
+	p, err := user.UnmarshalProvisioners(bytes.NewBuffer(append([]byte{}, v...)))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &p, binary.BigEndian.Uint64(k), nil
+}
+
+func (t transaction) StoreCandidateMessage(cm block.Block) error {
+	buf := new(bytes.Buffer)
+	if err := message.MarshalBlock(buf, &cm); err != nil {
+		return err
+	}
+
+	return t.put(candidateBucket, cm.Header.Hash, buf.Bytes())
+}
+
+func (t transaction) FetchCandidateMessage(hash []byte) (block.Block, error) {
+	value := t.get(candidateBucket, hash)
+	if value == nil {
+		return block.Block{}, database.ErrBlockNotFound
+	}
+
+	cm := block.NewBlock()
+	if err := message.UnmarshalBlock(bytes.NewBuffer(value), cm); err != nil {
+		return block.Block{}, err
+	}
+
+	return *cm, nil
+}
+
+func (t transaction) ClearCandidateMessages() error {
+	return t.clearBuckets(candidateBucket)
+}
+
+// ClearDatabase will wipe all of the data currently in the database.
+func (t transaction) ClearDatabase() error {
+	return t.clearBuckets(buckets...)
+}
+
+// clearBuckets drops and re-creates the given buckets.
+func (t transaction) clearBuckets(names ...[]byte) error {
+	if !t.tx.Writable() {
+		return bolt.ErrTxNotWritable
+	}
+
+	for _, name := range names {
+		if err := t.tx.DeleteBucket(name); err != nil {
+			return err
+		}
+
+		if _, err := t.tx.CreateBucket(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
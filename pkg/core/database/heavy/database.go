@@ -9,7 +9,9 @@ package heavy
 import (
 	"os"
 	"sync"
+	"time"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -20,6 +22,9 @@ var (
 	// See openStorage for detailed explanation.
 	_storage   *leveldb.DB
 	_storageMu sync.Mutex
+
+	// _pruner is bound to the lifetime of _storage. See startPruner.
+	_pruner *pruner
 )
 
 // DB on top of underlying storage syndtr/goleveldb/leveldb.
@@ -70,6 +75,11 @@ func closeStorage() error {
 	_storageMu.Lock()
 	defer _storageMu.Unlock()
 
+	if _pruner != nil {
+		_pruner.Stop()
+		_pruner = nil
+	}
+
 	if _storage != nil {
 		err := _storage.Close()
 		_storage = nil
@@ -82,13 +92,50 @@ func closeStorage() error {
 // NewDatabase create or open backend storage (goleveldb) located at the
 // specified path. Readonly option is pseudo read-only mode implemented by
 // heavy.Database. Not to be confused with read-only goleveldb mode.
+//
+// An existing database with an older schema version is migrated in place,
+// see migrate.
 func NewDatabase(path string, network protocol.Magic, readonly bool) (database.DB, error) {
 	storage, err := openStorage(path)
 	if err != nil {
 		return nil, err
 	}
 
-	return DB{storage, readonly}, nil
+	db := DB{storage, readonly}
+
+	if err := migrate(db, path, readonly); err != nil {
+		return nil, err
+	}
+
+	if !readonly {
+		startPruner(db)
+	}
+
+	return db, nil
+}
+
+// startPruner launches the background pruning of old block bodies, if
+// enabled by configuration. A single pruner runs per underlying storage.
+func startPruner(db DB) {
+	conf := cfg.Get().Database.Pruning
+	if !conf.Enabled {
+		return
+	}
+
+	_storageMu.Lock()
+	defer _storageMu.Unlock()
+
+	if _pruner != nil {
+		return
+	}
+
+	interval := time.Duration(conf.Interval) * time.Second
+	if interval == 0 {
+		interval = time.Minute
+	}
+
+	_pruner = newPruner(db, conf.KeepBlocks, conf.BatchSize, interval)
+	go _pruner.Run()
 }
 
 // Begin builds read-only or read-write Transaction.
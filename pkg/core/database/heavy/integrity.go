@@ -0,0 +1,358 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package heavy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// IssueKind identifies an inconsistency found by CheckIntegrity.
+type IssueKind string
+
+const (
+	// IssueState means that the StatePrefix tip is missing or does not agree
+	// with the HeightPrefix entries.
+	IssueState IssueKind = "state"
+	// IssueMissingHeight means that no HeightPrefix entry exists for a
+	// height below the tip.
+	IssueMissingHeight IssueKind = "missing_height"
+	// IssueStaleHeight means that a HeightPrefix entry exists above the tip.
+	IssueStaleHeight IssueKind = "stale_height"
+	// IssueMissingHeader means that a HeightPrefix entry points to a missing
+	// header.
+	IssueMissingHeader IssueKind = "missing_header"
+	// IssueHeight means that a header is mapped to a different height.
+	IssueHeight IssueKind = "height"
+	// IssueHash means that the header hash does not match its fields.
+	IssueHash IssueKind = "hash"
+	// IssuePrevHash means that the header does not link to the block
+	// stored at the previous height.
+	IssuePrevHash IssueKind = "prev_hash"
+	// IssueTxs means that the block transactions cannot be read.
+	IssueTxs IssueKind = "txs"
+	// IssueTxRoot means that the header tx root does not match the stored
+	// transactions.
+	IssueTxRoot IssueKind = "tx_root"
+	// IssueMissingTxID means that a TxPrefix entry has no TxIDPrefix entry.
+	IssueMissingTxID IssueKind = "missing_tx_id"
+	// IssueWrongTxID means that a TxIDPrefix entry points to another block.
+	IssueWrongTxID IssueKind = "wrong_tx_id"
+	// IssueDanglingTxID means that a TxIDPrefix entry has no TxPrefix entry.
+	IssueDanglingTxID IssueKind = "dangling_tx_id"
+)
+
+// IntegrityIssue is an inconsistency found by CheckIntegrity.
+type IntegrityIssue struct {
+	Kind     IssueKind `json:"kind"`
+	Height   uint64    `json:"height"`
+	Key      string    `json:"key,omitempty"`
+	Detail   string    `json:"detail"`
+	Repaired bool      `json:"repaired"`
+}
+
+// IntegrityReport is the outcome of CheckIntegrity.
+type IntegrityReport struct {
+	TipHeight    uint64           `json:"tip_height"`
+	TipHash      string           `json:"tip_hash"`
+	PrunedHeight uint64           `json:"pruned_height"`
+	Blocks       uint64           `json:"blocks"`
+	Txs          uint64           `json:"txs"`
+	Issues       []IntegrityIssue `json:"issues"`
+}
+
+// integrityChecker walks the whole database through a single snapshot, and
+// collects the fixes of the derived indexes.
+type integrityChecker struct {
+	t      *transaction
+	report *IntegrityReport
+
+	puts    map[string][]byte
+	deletes map[string]struct{}
+}
+
+// CheckIntegrity walks every height from genesis up to the tip, and verifies:
+//
+// - the HeightPrefix entries and the StatePrefix tip agree
+// - the header hashes and the prev-hash links
+// - the tx roots of the non-pruned blocks (see block.Block.CalculateRoot)
+// - the TxIDPrefix index matches the TxPrefix entries, in both directions
+//
+// If repair is true, the derived indexes (TxIDPrefix and stale HeightPrefix
+// entries) are fixed in a single atomic update. Other issues are reported
+// only, as they cannot be recovered from the database itself.
+func CheckIntegrity(db database.DB, repair bool) (*IntegrityReport, error) {
+	c := &integrityChecker{
+		report:  &IntegrityReport{Issues: make([]IntegrityIssue, 0)},
+		puts:    make(map[string][]byte),
+		deletes: make(map[string]struct{}),
+	}
+
+	err := db.View(func(t database.Transaction) error {
+		c.t = t.(*transaction)
+		return c.run()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !repair || (len(c.puts) == 0 && len(c.deletes) == 0) {
+		return c.report, nil
+	}
+
+	err = db.Update(func(t database.Transaction) error {
+		tx := t.(*transaction)
+
+		for k, v := range c.puts {
+			tx.put([]byte(k), v)
+		}
+
+		for k := range c.deletes {
+			tx.batch.Delete([]byte(k))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range c.report.Issues {
+		switch c.report.Issues[i].Kind {
+		case IssueMissingTxID, IssueWrongTxID, IssueDanglingTxID, IssueStaleHeight:
+			c.report.Issues[i].Repaired = true
+		}
+	}
+
+	return c.report, nil
+}
+
+func (c *integrityChecker) issue(kind IssueKind, height uint64, key []byte, format string, args ...interface{}) {
+	issue := IntegrityIssue{
+		Kind:   kind,
+		Height: height,
+		Detail: fmt.Sprintf(format, args...),
+	}
+
+	if key != nil {
+		issue.Key = hex.EncodeToString(key)
+	}
+
+	c.report.Issues = append(c.report.Issues, issue)
+}
+
+func (c *integrityChecker) run() error {
+	var err error
+
+	c.report.PrunedHeight, err = c.t.FetchPrunedHeight()
+	if err != nil {
+		return err
+	}
+
+	state, err := c.t.FetchState()
+	if err == database.ErrStateNotFound {
+		c.issue(IssueState, 0, StatePrefix, "chain tip not found")
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	tip, err := c.t.FetchBlockHeader(state.TipHash)
+	if err != nil {
+		c.issue(IssueState, 0, StatePrefix, "chain tip header %x: %v", state.TipHash, err)
+		return nil
+	}
+
+	c.report.TipHeight = tip.Height
+	c.report.TipHash = hex.EncodeToString(tip.Hash)
+
+	var prevHash []byte
+
+	for height := uint64(0); height <= tip.Height; height++ {
+		hash, err := c.checkHeight(height, prevHash)
+		if err != nil {
+			return err
+		}
+
+		prevHash = hash
+	}
+
+	if prevHash != nil && !bytes.Equal(prevHash, tip.Hash) {
+		c.issue(IssueState, tip.Height, StatePrefix, "chain tip %x is not the block at height %d", tip.Hash, tip.Height)
+	}
+
+	if err := c.checkHeightEntries(tip.Height); err != nil {
+		return err
+	}
+
+	return c.checkTxIDEntries()
+}
+
+// checkHeight verifies the block mapped at height, and returns its hash.
+func (c *integrityChecker) checkHeight(height uint64, prevHash []byte) ([]byte, error) {
+	hash, err := c.t.FetchBlockHashByHeight(height)
+	if err == database.ErrBlockNotFound {
+		c.issue(IssueMissingHeight, height, nil, "no block mapped at height")
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	hdr, err := c.t.FetchBlockHeader(hash)
+	if err == database.ErrBlockNotFound {
+		c.issue(IssueMissingHeader, height, append(HeaderPrefix, hash...), "header %x not found", hash)
+		return hash, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.report.Blocks++
+
+	if hdr.Height != height {
+		c.issue(IssueHeight, height, append(HeaderPrefix, hash...), "header has height %d", hdr.Height)
+	}
+
+	calculated, err := hdr.CalculateHash()
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(calculated, hash) || !bytes.Equal(hdr.Hash, hash) {
+		c.issue(IssueHash, height, append(HeaderPrefix, hash...), "calculated hash is %x", calculated)
+	}
+
+	if height > 0 && prevHash != nil && !bytes.Equal(hdr.PrevBlockHash, prevHash) {
+		c.issue(IssuePrevHash, height, append(HeaderPrefix, hash...), "prev hash %x does not match %x", hdr.PrevBlockHash, prevHash)
+	}
+
+	if c.report.PrunedHeight > 0 && height > 0 && height < c.report.PrunedHeight {
+		// Block body has been pruned
+		return hash, nil
+	}
+
+	txs, err := c.t.FetchBlockTxs(hash)
+	if err != nil {
+		c.issue(IssueTxs, height, append(TxPrefix, hash...), "%v", err)
+		return hash, nil
+	}
+
+	c.report.Txs += uint64(len(txs))
+
+	if len(txs) > 0 {
+		blk := block.Block{Header: hdr, Txs: txs}
+
+		root, err := blk.CalculateRoot()
+		if err != nil {
+			c.issue(IssueTxRoot, height, nil, "%v", err)
+		} else if !bytes.Equal(root, hdr.TxRoot) {
+			c.issue(IssueTxRoot, height, nil, "calculated tx root is %x", root)
+		}
+	}
+
+	return hash, c.checkTxIDs(height, hash)
+}
+
+// checkTxIDs verifies that each TxPrefix entry of a block is indexed by a
+// TxIDPrefix entry.
+func (c *integrityChecker) checkTxIDs(height uint64, hash []byte) error {
+	scanFilter := append(TxPrefix, hash...)
+
+	iterator := c.t.snapshot.NewIterator(util.BytesPrefix(scanFilter), nil)
+	defer iterator.Release()
+
+	for iterator.Next() {
+		txID := iterator.Key()[len(scanFilter):]
+		key := append(append([]byte{}, TxIDPrefix...), txID...)
+
+		value, err := c.t.snapshot.Get(key, nil)
+		if err == leveldb.ErrNotFound {
+			c.issue(IssueMissingTxID, height, key, "tx %x is not indexed", txID)
+			c.puts[string(key)] = append([]byte{}, hash...)
+
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(value, hash) {
+			c.issue(IssueWrongTxID, height, key, "tx %x is indexed to block %x", txID, value)
+			c.puts[string(key)] = append([]byte{}, hash...)
+		}
+	}
+
+	return iterator.Error()
+}
+
+// checkHeightEntries reports the HeightPrefix entries above the tip.
+func (c *integrityChecker) checkHeightEntries(tipHeight uint64) error {
+	iterator := c.t.snapshot.NewIterator(util.BytesPrefix(HeightPrefix), nil)
+	defer iterator.Release()
+
+	for iterator.Next() {
+		// Key = HeightPrefix + block.header.height, see utils.WriteUint64
+		if len(iterator.Key()) != len(HeightPrefix)+8 {
+			continue
+		}
+
+		height := binary.LittleEndian.Uint64(iterator.Key()[len(HeightPrefix):])
+		if height > tipHeight {
+			key := append([]byte{}, iterator.Key()...)
+
+			c.issue(IssueStaleHeight, height, key, "height is above the tip")
+			c.deletes[string(key)] = struct{}{}
+		}
+	}
+
+	return iterator.Error()
+}
+
+// checkTxIDEntries reports the TxIDPrefix entries without a TxPrefix entry.
+func (c *integrityChecker) checkTxIDEntries() error {
+	iterator := c.t.snapshot.NewIterator(util.BytesPrefix(TxIDPrefix), nil)
+	defer iterator.Release()
+
+	for iterator.Next() {
+		txID := iterator.Key()[len(TxIDPrefix):]
+		hash := iterator.Value()
+
+		txKey := append(append(append([]byte{}, TxPrefix...), hash...), txID...)
+
+		exists, err := c.t.snapshot.Has(txKey, nil)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			key := append([]byte{}, iterator.Key()...)
+
+			// A wrongly indexed tx is re-indexed, not deleted
+			if _, ok := c.puts[string(key)]; ok {
+				continue
+			}
+
+			c.issue(IssueDanglingTxID, 0, key, "tx %x not found in block %x", txID, hash)
+			c.deletes[string(key)] = struct{}{}
+		}
+	}
+
+	return iterator.Error()
+}
@@ -0,0 +1,99 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package heavy
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/utils"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(os.TempDir(), "heavy_integrity_")
+	assert.NoError(err)
+
+	defer func() {
+		_ = closeStorage()
+		_ = os.RemoveAll(dir)
+	}()
+
+	db, err := NewDatabase(dir, protocol.DevNet, false)
+	assert.NoError(err)
+
+	blocks := make([]*block.Block, 5)
+	for i := range blocks {
+		blk := helper.RandomBlock(uint64(i), 1)
+
+		if i > 0 {
+			blk.Header.PrevBlockHash = blocks[i-1].Header.Hash
+
+			blk.Header.Hash, err = blk.CalculateHash()
+			assert.NoError(err)
+		}
+
+		blocks[i] = blk
+	}
+
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		for _, blk := range blocks {
+			if err := t.StoreBlock(blk); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	report, err := CheckIntegrity(db, false)
+	assert.NoError(err)
+	assert.Empty(report.Issues)
+	assert.Equal(uint64(4), report.TipHeight)
+	assert.Equal(uint64(5), report.Blocks)
+
+	// Corrupt the derived indexes
+	txID, err := blocks[2].Txs[0].CalculateHash()
+	assert.NoError(err)
+
+	stale := new(bytes.Buffer)
+	assert.NoError(utils.WriteUint64(stale, 10))
+
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		tx := t.(*transaction)
+		tx.batch.Delete(append(TxIDPrefix, txID...))
+		tx.put(append(TxIDPrefix, make([]byte, 32)...), blocks[1].Header.Hash)
+		tx.put(append(HeightPrefix, stale.Bytes()...), blocks[1].Header.Hash)
+		return nil
+	}))
+
+	report, err = CheckIntegrity(db, true)
+	assert.NoError(err)
+	assert.Len(report.Issues, 3)
+
+	kinds := make(map[IssueKind]bool)
+	for _, issue := range report.Issues {
+		kinds[issue.Kind] = issue.Repaired
+	}
+
+	assert.Equal(map[IssueKind]bool{
+		IssueMissingTxID:  true,
+		IssueDanglingTxID: true,
+		IssueStaleHeight:  true,
+	}, kinds)
+
+	report, err = CheckIntegrity(db, false)
+	assert.NoError(err)
+	assert.Empty(report.Issues)
+}
@@ -0,0 +1,203 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package heavy
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	log "github.com/sirupsen/logrus"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// SchemaVersion is the version of the K/V storage schema implemented by this
+// driver. It must be increased, together with a new entry in migrations, on
+// any change of the key prefixes or of the value encodings.
+const SchemaVersion uint32 = 2
+
+// legacySchemaVersion is the version of the databases created before the
+// schema version was persisted.
+const legacySchemaVersion uint32 = 1
+
+// backupBatchSize is the number of KV pairs copied per write by backup.
+const backupBatchSize = 10000
+
+// ErrIncompatibleSchema is returned on opening a database which schema
+// cannot be handled by this driver.
+var ErrIncompatibleSchema = errors.New("heavy: incompatible database schema")
+
+// migration upgrades a database from version-1 to version. A migration is
+// not required to be atomic, though it must be resumable, as the schema
+// version is updated only after it completes.
+type migration struct {
+	version     uint32
+	description string
+	migrate     func(db DB) error
+}
+
+// migrations lists all schema upgrades, sorted by version.
+var migrations = []migration{
+	{
+		version:     2,
+		description: "index transactions by type and height",
+		migrate: func(db DB) error {
+			return ReindexTxTypes(db)
+		},
+	},
+}
+
+// fetchSchemaVersion returns the schema version of the database. Non-empty
+// databases without a version are considered legacySchemaVersion.
+func fetchSchemaVersion(db DB) (version uint32, empty bool, err error) {
+	err = db.View(func(t database.Transaction) error {
+		snapshot := t.(*transaction).snapshot
+
+		value, err := snapshot.Get(SchemaVersionPrefix, nil)
+		if err == nil {
+			if len(value) != 4 {
+				return errors.New("schema version malformed")
+			}
+
+			version = binary.LittleEndian.Uint32(value)
+			return nil
+		}
+
+		if err != leveldb.ErrNotFound {
+			return err
+		}
+
+		iterator := snapshot.NewIterator(nil, nil)
+		defer iterator.Release()
+
+		empty = !iterator.Next()
+		version = legacySchemaVersion
+
+		return iterator.Error()
+	})
+
+	return version, empty, err
+}
+
+func storeSchemaVersion(db DB, version uint32) error {
+	return db.Update(func(t database.Transaction) error {
+		value := make([]byte, 4)
+		binary.LittleEndian.PutUint32(value, version)
+
+		t.(*transaction).put(SchemaVersionPrefix, value)
+		return nil
+	})
+}
+
+// migrate upgrades the database at path to SchemaVersion. A backup of the
+// database is made before applying the first migration.
+//
+// Databases with a newer schema, or an older one when readonly is true, are
+// refused with ErrIncompatibleSchema.
+func migrate(db DB, path string, readonly bool) error {
+	version, empty, err := fetchSchemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	if empty {
+		if readonly {
+			return nil
+		}
+
+		return storeSchemaVersion(db, SchemaVersion)
+	}
+
+	if version > SchemaVersion {
+		return fmt.Errorf("%w: database version is %d, while the driver supports up to %d. Please upgrade the node", ErrIncompatibleSchema, version, SchemaVersion)
+	}
+
+	if version == SchemaVersion {
+		return nil
+	}
+
+	if readonly {
+		return fmt.Errorf("%w: database version %d must be migrated to %d, which requires a read-write database", ErrIncompatibleSchema, version, SchemaVersion)
+	}
+
+	l := log.WithField("process", "database").
+		WithField("from", version).
+		WithField("to", SchemaVersion)
+
+	backupPath := fmt.Sprintf("%s.backup-v%d-%d", filepath.Clean(path), version, time.Now().Unix())
+
+	l.WithField("backup", backupPath).Info("backing up database before migration")
+
+	if err := backup(db, backupPath); err != nil {
+		return fmt.Errorf("database backup failed: %v", err)
+	}
+
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+
+		l.WithField("version", m.version).Infof("migrating database: %s", m.description)
+
+		if err := m.migrate(db); err != nil {
+			return fmt.Errorf("database migration to version %d failed: %v", m.version, err)
+		}
+
+		if err := storeSchemaVersion(db, m.version); err != nil {
+			return err
+		}
+	}
+
+	l.Info("database migrated")
+	return nil
+}
+
+// backup copies all KV pairs of the database, as seen by a single snapshot,
+// into a new leveldb database at path.
+func backup(db DB, path string) error {
+	dst, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = dst.Close()
+	}()
+
+	snapshot, err := db.storage.GetSnapshot()
+	if err != nil {
+		return err
+	}
+
+	defer snapshot.Release()
+
+	iterator := snapshot.NewIterator(nil, nil)
+	defer iterator.Release()
+
+	batch := new(leveldb.Batch)
+
+	for iterator.Next() {
+		batch.Put(iterator.Key(), iterator.Value())
+
+		if batch.Len() >= backupBatchSize {
+			if err := dst.Write(batch, writeOptions); err != nil {
+				return err
+			}
+
+			batch.Reset()
+		}
+	}
+
+	if err := iterator.Error(); err != nil {
+		return err
+	}
+
+	return dst.Write(batch, writeOptions)
+}
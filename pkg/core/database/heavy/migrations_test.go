@@ -0,0 +1,114 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package heavy
+
+import (
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	assert "github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+func TestSchemaMigrations(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(os.TempDir(), "heavy_migrations_")
+	assert.NoError(err)
+
+	defer func() {
+		_ = closeStorage()
+
+		backups, _ := filepath.Glob(dir + ".backup-*")
+		for _, b := range backups {
+			_ = os.RemoveAll(b)
+		}
+
+		_ = os.RemoveAll(dir)
+	}()
+
+	// A new database is created with the current schema version
+	db, err := NewDatabase(dir, protocol.DevNet, false)
+	assert.NoError(err)
+
+	version, empty, err := fetchSchemaVersion(db.(DB))
+	assert.NoError(err)
+	assert.False(empty)
+	assert.Equal(SchemaVersion, version)
+
+	blk := helper.RandomBlock(0, 3)
+
+	// Turn it into a legacy database, with no version and no tx type index
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		if err := t.StoreBlock(blk); err != nil {
+			return err
+		}
+
+		tx := t.(*transaction)
+
+		iterator := tx.snapshot.NewIterator(util.BytesPrefix(TxTypePrefix), nil)
+		defer iterator.Release()
+
+		for iterator.Next() {
+			tx.batch.Delete(append([]byte{}, iterator.Key()...))
+		}
+
+		tx.batch.Delete(TxTypeIndexedPrefix)
+		tx.batch.Delete(SchemaVersionPrefix)
+		return iterator.Error()
+	}))
+
+	assert.NoError(closeStorage())
+
+	// A legacy database cannot be opened in read-only mode
+	_, err = NewDatabase(dir, protocol.DevNet, true)
+	assert.True(errors.Is(err, ErrIncompatibleSchema))
+
+	// Opening it in read-write mode migrates it, after a backup
+	db, err = NewDatabase(dir, protocol.DevNet, false)
+	assert.NoError(err)
+
+	version, _, err = fetchSchemaVersion(db.(DB))
+	assert.NoError(err)
+	assert.Equal(SchemaVersion, version)
+
+	backups, err := filepath.Glob(dir + ".backup-v1-*")
+	assert.NoError(err)
+	assert.Len(backups, 1)
+
+	var indexed int
+
+	assert.NoError(db.View(func(t database.Transaction) error {
+		return t.FetchTxsByType(blk.Txs[0].Type(), 0, 0, func(_ transactions.ContractCall, _ uint64, _ uint32) error {
+			indexed++
+			return nil
+		})
+	}))
+	assert.NotZero(indexed)
+
+	// A database with a newer schema version is refused
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		value := make([]byte, 4)
+		binary.LittleEndian.PutUint32(value, SchemaVersion+1)
+
+		t.(*transaction).put(SchemaVersionPrefix, value)
+		return nil
+	}))
+
+	assert.NoError(closeStorage())
+
+	_, err = NewDatabase(dir, protocol.DevNet, false)
+	assert.True(errors.Is(err, ErrIncompatibleSchema))
+}
@@ -0,0 +1,75 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package heavy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// provisionersKey builds a ProvisionersPrefix key. Height is big-endian
+// encoded so that snapshots are sorted by height.
+func provisionersKey(height uint64) []byte {
+	key := make([]byte, len(ProvisionersPrefix)+8)
+	n := copy(key, ProvisionersPrefix)
+	binary.BigEndian.PutUint64(key[n:], height)
+
+	return key
+}
+
+// StoreProvisioners stores a snapshot of the provisioners set at height.
+//
+// Key = ProvisionersPrefix + height
+// Value = user.MarshalProvisioners(p)
+func (t transaction) StoreProvisioners(height uint64, p *user.Provisioners) error {
+	if t.batch == nil {
+		return errors.New("StoreProvisioners cannot be called on read-only transaction")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := user.MarshalProvisioners(buf, p); err != nil {
+		return err
+	}
+
+	t.put(provisionersKey(height), buf.Bytes())
+	return nil
+}
+
+// FetchProvisioners returns the most recent provisioners snapshot stored at a
+// height lower or equal to height.
+func (t transaction) FetchProvisioners(height uint64) (*user.Provisioners, uint64, error) {
+	r := util.BytesPrefix(ProvisionersPrefix)
+	if height < math.MaxUint64 {
+		r.Limit = provisionersKey(height + 1)
+	}
+
+	iterator := t.snapshot.NewIterator(r, nil)
+	defer iterator.Release()
+
+	if !iterator.Last() {
+		if err := iterator.Error(); err != nil {
+			return nil, 0, err
+		}
+
+		return nil, 0, database.ErrProvisionersNotFound
+	}
+
+	snapshotHeight := binary.BigEndian.Uint64(iterator.Key()[len(ProvisionersPrefix):])
+
+	p, err := user.UnmarshalProvisioners(bytes.NewBuffer(append([]byte{}, iterator.Value()...)))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &p, snapshotHeight, nil
+}
@@ -0,0 +1,222 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package heavy
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// pruner deletes in background the bodies of all blocks older than the last
+// keepBlocks blocks. Block bodies are all TxPrefix, TxIDPrefix, TxTypePrefix
+// and KeyImagePrefix entries of a block. Headers (incl. certificates), height
+// mappings and chain state are never pruned.
+type pruner struct {
+	db database.DB
+
+	keepBlocks uint64
+	batchSize  uint64
+	interval   time.Duration
+
+	quit chan struct{}
+}
+
+func newPruner(db database.DB, keepBlocks, batchSize uint64, interval time.Duration) *pruner {
+	if batchSize == 0 {
+		batchSize = 1
+	}
+
+	return &pruner{
+		db:         db,
+		keepBlocks: keepBlocks,
+		batchSize:  batchSize,
+		interval:   interval,
+		quit:       make(chan struct{}),
+	}
+}
+
+// Run prunes on each interval tick as many batches as needed to reach the
+// configured depth.
+func (p *pruner) Run() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.pruneAll(); err != nil {
+				log.WithField("process", "database").
+					WithError(err).Error("pruning failed")
+			}
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// Stop terminates the pruning loop.
+func (p *pruner) Stop() {
+	close(p.quit)
+}
+
+// pruneAll applies pruning in batches until there is nothing left to prune.
+// Each batch is committed in a separate atomic update so that the storage is
+// never locked for too long.
+func (p *pruner) pruneAll() error {
+	for {
+		select {
+		case <-p.quit:
+			return nil
+		default:
+		}
+
+		var pruned uint64
+
+		err := p.db.Update(func(t database.Transaction) error {
+			var err error
+			pruned, err = t.(*transaction).pruneBlocks(p.keepBlocks, p.batchSize)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		if pruned == 0 {
+			return nil
+		}
+
+		log.WithField("process", "database").
+			WithField("blocks", pruned).Debug("pruned block bodies")
+	}
+}
+
+// pruneBlocks deletes the bodies of up to batchSize blocks older than the
+// last keepBlocks blocks. It returns the number of blocks pruned. The genesis
+// block body is always kept.
+func (t transaction) pruneBlocks(keepBlocks, batchSize uint64) (uint64, error) {
+	tip, err := t.FetchCurrentHeight()
+	if err == database.ErrStateNotFound {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if tip <= keepBlocks {
+		return 0, nil
+	}
+
+	// Blocks in range [from, target) are to be pruned, so that exactly the
+	// last keepBlocks blocks (target to tip) keep their bodies
+	target := tip - keepBlocks + 1
+
+	from, err := t.FetchPrunedHeight()
+	if err != nil {
+		return 0, err
+	}
+
+	if from == 0 {
+		from = 1
+	}
+
+	if from >= target {
+		return 0, nil
+	}
+
+	to := from + batchSize
+	if to > target {
+		to = target
+	}
+
+	for height := from; height < to; height++ {
+		hash, err := t.FetchBlockHashByHeight(height)
+		if err == database.ErrBlockNotFound {
+			continue
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		if err := t.pruneBlockBody(hash, height); err != nil {
+			return 0, err
+		}
+	}
+
+	heightBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(heightBytes, to)
+	t.put(PrunedHeightPrefix, heightBytes)
+
+	return to - from, nil
+}
+
+// pruneBlockBody deletes the transactions of a block, with their TxIDPrefix,
+// TxTypePrefix and KeyImagePrefix entries.
+func (t transaction) pruneBlockBody(hash []byte, height uint64) error {
+	scanFilter := append(TxPrefix, hash...)
+	iterator := t.snapshot.NewIterator(util.BytesPrefix(scanFilter), nil)
+	defer iterator.Release()
+
+	for iterator.Next() {
+		// Key = TxPrefix + block.header.hash + txID
+		txID := iterator.Key()[len(scanFilter):]
+
+		t.batch.Delete(iterator.Key())
+		t.batch.Delete(append(TxIDPrefix, txID...))
+
+		// Value = txType + index + tx
+		value := iterator.Value()
+		if len(value) < 5 {
+			continue
+		}
+
+		txIndex := binary.LittleEndian.Uint32(value[1:5])
+		t.batch.Delete(txTypeKey(transactions.TxType(value[0]), height, txIndex))
+
+		// The key images of the tx are collected from its nullifiers, so that
+		// the KeyImagePrefix entries are never scanned
+		tx, _, err := utils.DecodeBlockTx(value, database.AnyTxType)
+		if err != nil {
+			return err
+		}
+
+		for _, keyImage := range tx.StandardTx().Nullifiers {
+			if err := t.deleteKeyImage(keyImage, txID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return iterator.Error()
+}
+
+// isPruned returns true if the header of the block is stored but its body
+// has been pruned.
+func (t transaction) isPruned(hash []byte) (bool, error) {
+	prunedHeight, err := t.FetchPrunedHeight()
+	if err != nil || prunedHeight == 0 {
+		return false, err
+	}
+
+	header, err := t.FetchBlockHeader(hash)
+	if err == database.ErrBlockNotFound {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return header.Height > 0 && header.Height < prunedHeight, nil
+}
@@ -0,0 +1,89 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package heavy
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestPruneBlocks(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(os.TempDir(), "heavy_pruner_")
+	assert.NoError(err)
+
+	defer func() {
+		_ = closeStorage()
+		_ = os.RemoveAll(dir)
+	}()
+
+	db, err := NewDatabase(dir, protocol.DevNet, false)
+	assert.NoError(err)
+
+	blocks := make([]*block.Block, 20)
+	for i := range blocks {
+		blocks[i] = helper.RandomBlock(uint64(i), 1)
+	}
+
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		for _, blk := range blocks {
+			if err := t.StoreBlock(blk); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	// Keep the last 5 blocks, prune at most 10 blocks per batch
+	p := newPruner(db, 5, 10, time.Second)
+	assert.NoError(p.pruneAll())
+
+	assert.NoError(db.View(func(t database.Transaction) error {
+		prunedHeight, err := t.FetchPrunedHeight()
+		assert.NoError(err)
+		// Exactly the last 5 blocks (15 to 19) keep their bodies
+		assert.Equal(uint64(15), prunedHeight)
+
+		for _, blk := range blocks {
+			// Headers are never pruned
+			_, err := t.FetchBlockHeader(blk.Header.Hash)
+			assert.NoError(err)
+
+			txID, err := blk.Txs[0].CalculateHash()
+			assert.NoError(err)
+
+			_, _, _, txErr := t.FetchBlockTxByHash(txID)
+			_, blkErr := t.FetchBlock(blk.Header.Hash)
+			pruned := blk.Header.Height > 0 && blk.Header.Height < prunedHeight
+
+			for _, nullifier := range blk.Txs[0].StandardTx().Nullifiers {
+				exists, _, _ := t.FetchKeyImageExists(nullifier)
+				assert.Equal(!pruned, exists)
+			}
+
+			if pruned {
+				assert.Equal(database.ErrBlockPruned, blkErr)
+				assert.Equal(database.ErrTxNotFound, txErr)
+				continue
+			}
+
+			assert.NoError(blkErr)
+			assert.NoError(txErr)
+		}
+
+		return nil
+	}))
+}
@@ -60,6 +60,21 @@ var (
 	BidValuesPrefix = []byte{0x08}
 	// CandidatePrefix is the prefix to identify Candidate messages.
 	CandidatePrefix = []byte{0x09}
+	// PrunedHeightPrefix is the prefix to identify the lowest height with a
+	// non-pruned block body.
+	PrunedHeightPrefix = []byte{0x0A}
+	// TxTypePrefix is the prefix to identify the index of Transactions by
+	// type and height.
+	TxTypePrefix = []byte{0x0B}
+	// TxTypeIndexedPrefix is the prefix to identify the marker of a
+	// completed TxTypePrefix reindex.
+	TxTypeIndexedPrefix = []byte{0x0C}
+	// ProvisionersPrefix is the prefix to identify the Provisioners
+	// snapshots.
+	ProvisionersPrefix = []byte{0x0D}
+	// SchemaVersionPrefix is the prefix to identify the version of the K/V
+	// storage schema.
+	SchemaVersionPrefix = []byte{0x0E}
 )
 
 type transaction struct {
@@ -149,6 +164,24 @@ func (t transaction) StoreBlock(b *block.Block) error {
 		//
 		// For the retrival of a single transaction by TxId
 		t.put(append(TxIDPrefix, txID...), b.Header.Hash)
+
+		// Schema
+		//
+		// Key = TxTypePrefix + txType + block.header.height + index
+		// Value = txID
+		//
+		// For the retrival of transactions by type in a height range
+		t.put(txTypeKey(tx.Type(), b.Header.Height, uint32(i)), txID)
+
+		// Schema
+		//
+		// Key = KeyImagePrefix + keyImage
+		// Value = txID
+		//
+		// For the lookup of the key images spent by the chain
+		for _, keyImage := range tx.StandardTx().Nullifiers {
+			t.put(append(KeyImagePrefix, keyImage...), txID)
+		}
 	}
 
 	// Key = HeightPrefix + block.header.height
@@ -206,6 +239,51 @@ func (t transaction) StoreBlock(b *block.Block) error {
 	return iterator.Error()
 }
 
+// DeleteBlock removes all KV pairs put by StoreBlock for a block and sets
+// the chain tip to block.header.prevBlockHash. As for StoreBlock, storage
+// state changes only when Commit() is called on Transaction completion.
+func (t transaction) DeleteBlock(b *block.Block) error {
+	if t.batch == nil {
+		return errors.New("DeleteBlock cannot be called on read-only transaction")
+	}
+
+	t.batch.Delete(append(HeaderPrefix, b.Header.Hash...))
+
+	for i, tx := range b.Txs {
+		txID, err := tx.CalculateHash()
+		if err != nil {
+			return err
+		}
+
+		keys := append(TxPrefix, b.Header.Hash...)
+		keys = append(keys, txID...)
+
+		t.batch.Delete(keys)
+		t.batch.Delete(append(TxIDPrefix, txID...))
+		t.batch.Delete(txTypeKey(tx.Type(), b.Header.Height, uint32(i)))
+
+		for _, keyImage := range tx.StandardTx().Nullifiers {
+			if err := t.deleteKeyImage(keyImage, txID); err != nil {
+				return err
+			}
+		}
+	}
+
+	heightBuf := new(bytes.Buffer)
+	if err := utils.WriteUint64(heightBuf, b.Header.Height); err != nil {
+		return err
+	}
+
+	t.batch.Delete(append(HeightPrefix, heightBuf.Bytes()...))
+	t.batch.Delete(provisionersKey(b.Header.Height))
+
+	// Key = StatePrefix
+	// Value = Hash(chain tip)
+	t.put(StatePrefix, b.Header.PrevBlockHash)
+
+	return nil
+}
+
 // Commit writes a batch to LevelDB storage. See also fsyncEnabled variable.
 func (t *transaction) Commit() error {
 	if !t.writable {
@@ -333,6 +411,19 @@ func (t transaction) FetchBlockTxs(hashHeader []byte) ([]transactions.ContractCa
 		tempTxs[txIndex] = tx
 	}
 
+	if len(tempTxs) == 0 {
+		// No txs found. Either the block does not exist or its body has
+		// been pruned.
+		pruned, err := t.isPruned(hashHeader)
+		if err != nil {
+			return nil, err
+		}
+
+		if pruned {
+			return nil, database.ErrBlockPruned
+		}
+	}
+
 	// Reorder Tx slice as per retrieved indexes
 	resultTxs := make([]transactions.ContractCall, len(tempTxs))
 	for k, v := range tempTxs {
@@ -439,6 +530,29 @@ func (t transaction) FetchBlockTxByHash(txID []byte) (transactions.ContractCall,
 	return nil, txIndex, nil, errors.New("block tx is available but fetching it fails")
 }
 
+// deleteKeyImage deletes the KeyImagePrefix entry of a key image, if it has
+// been spent by the tx.
+func (t transaction) deleteKeyImage(keyImage, txID []byte) error {
+	// Key = KeyImagePrefix + keyImage
+	// Value = txID
+	key := append(KeyImagePrefix, keyImage...)
+
+	owner, err := t.snapshot.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(owner, txID) {
+		t.batch.Delete(key)
+	}
+
+	return nil
+}
+
 // FetchKeyImageExists checks if the KeyImage exists. If so, it also returns the
 // hash of its corresponding tx.
 //
@@ -507,6 +621,25 @@ func (t transaction) FetchCurrentHeight() (uint64, error) {
 	return header.Height, nil
 }
 
+// FetchPrunedHeight returns the lowest height with a non-pruned block body.
+func (t transaction) FetchPrunedHeight() (uint64, error) {
+	value, err := t.snapshot.Get(PrunedHeightPrefix, nil)
+	if err == leveldb.ErrNotFound {
+		// Nothing pruned so far
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(value) != 8 {
+		return 0, errors.New("pruned height malformed")
+	}
+
+	return binary.LittleEndian.Uint64(value), nil
+}
+
 func (t transaction) StoreBidValues(d, k []byte, index uint64, lockTime uint64) error {
 	// First, delete the old values (if any)
 	heightBytes := make([]byte, 8)
@@ -0,0 +1,215 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package heavy
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// reindexBatchSize is the number of blocks indexed per atomic update by
+// ReindexTxTypes.
+const reindexBatchSize = 1000
+
+// txTypeKey builds a TxTypePrefix key. Height and index are big-endian
+// encoded so that keys of the same type are sorted by (height, index).
+func txTypeKey(txType transactions.TxType, height uint64, txIndex uint32) []byte {
+	key := make([]byte, len(TxTypePrefix)+1+8+4)
+	n := copy(key, TxTypePrefix)
+
+	key[n] = byte(txType)
+	binary.BigEndian.PutUint64(key[n+1:], height)
+	binary.BigEndian.PutUint32(key[n+9:], txIndex)
+
+	return key
+}
+
+// FetchTxsByType iterates over all txs of type txType stored in the height
+// range [fromHeight, toHeight]. See also database.Transaction.
+func (t transaction) FetchTxsByType(txType transactions.TxType, fromHeight, toHeight uint64, fn func(tx transactions.ContractCall, height uint64, txIndex uint32) error) error {
+	if fromHeight > toHeight {
+		return nil
+	}
+
+	r := &util.Range{Start: txTypeKey(txType, fromHeight, 0)}
+	if toHeight == math.MaxUint64 {
+		r.Limit = util.BytesPrefix(append(TxTypePrefix, byte(txType))).Limit
+	} else {
+		r.Limit = txTypeKey(txType, toHeight+1, 0)
+	}
+
+	iterator := t.snapshot.NewIterator(r, nil)
+	defer iterator.Release()
+
+	var (
+		hash       []byte
+		hashHeight uint64
+	)
+
+	for iterator.Next() {
+		// Key = TxTypePrefix + txType + height + index
+		key := iterator.Key()[len(TxTypePrefix)+1:]
+		height := binary.BigEndian.Uint64(key)
+		txIndex := binary.BigEndian.Uint32(key[8:])
+
+		if hash == nil || hashHeight != height {
+			var err error
+
+			hash, err = t.FetchBlockHashByHeight(height)
+			if err != nil {
+				return err
+			}
+
+			hashHeight = height
+		}
+
+		txKey := append(append(TxPrefix, hash...), iterator.Value()...)
+
+		value, err := t.snapshot.Get(txKey, nil)
+		if err == leveldb.ErrNotFound {
+			return database.ErrTxNotFound
+		}
+
+		if err != nil {
+			return err
+		}
+
+		tx, _, err := utils.DecodeBlockTx(value, txType)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(tx, height, txIndex); err != nil {
+			return err
+		}
+	}
+
+	return iterator.Error()
+}
+
+// indexTxTypes adds the TxTypePrefix entries of all blocks in range
+// [from, to). Blocks with pruned or missing bodies are skipped.
+func (t transaction) indexTxTypes(from, to uint64) error {
+	for height := from; height < to; height++ {
+		hash, err := t.FetchBlockHashByHeight(height)
+		if err == database.ErrBlockNotFound {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		scanFilter := append(TxPrefix, hash...)
+		iterator := t.snapshot.NewIterator(util.BytesPrefix(scanFilter), nil)
+
+		for iterator.Next() {
+			// Key = TxPrefix + block.header.hash + txID
+			// Value = txType + index + tx
+			value := iterator.Value()
+			if len(value) < 5 {
+				continue
+			}
+
+			txID := iterator.Key()[len(scanFilter):]
+			txIndex := binary.LittleEndian.Uint32(value[1:5])
+
+			t.put(txTypeKey(transactions.TxType(value[0]), height, txIndex), append([]byte{}, txID...))
+		}
+
+		iterator.Release()
+
+		if err := iterator.Error(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReindexTxTypes builds the TxTypePrefix index for all blocks stored before
+// the index was introduced. It is a no-op if the index is already complete.
+// Blocks are indexed in batches, each one committed atomically together with
+// the reindex progress, so that an interrupted reindex can be resumed.
+func ReindexTxTypes(db database.DB) error {
+	var (
+		from  uint64
+		tip   uint64
+		empty bool
+	)
+
+	err := db.View(func(t database.Transaction) error {
+		// Key = TxTypeIndexedPrefix
+		// Value = next height to index, math.MaxUint64 if completed
+		value, err := t.(*transaction).snapshot.Get(TxTypeIndexedPrefix, nil)
+		if err == nil && len(value) == 8 {
+			from = binary.LittleEndian.Uint64(value)
+		}
+
+		if from == math.MaxUint64 {
+			return nil
+		}
+
+		tip, err = t.FetchCurrentHeight()
+		if err == database.ErrStateNotFound {
+			// Empty database, all blocks are going to be indexed on storing
+			empty = true
+			return nil
+		}
+
+		return err
+	})
+	if err != nil || from == math.MaxUint64 {
+		return err
+	}
+
+	if !empty {
+		log.WithField("process", "database").
+			WithField("from", from).
+			WithField("to", tip).
+			Info("reindexing transactions by type")
+	}
+
+	for {
+		to := uint64(math.MaxUint64)
+		if from <= tip && tip-from >= reindexBatchSize {
+			to = from + reindexBatchSize
+		}
+
+		err := db.Update(func(t database.Transaction) error {
+			tx := t.(*transaction)
+
+			if from <= tip {
+				end := to
+				if end > tip {
+					end = tip + 1
+				}
+
+				if err := tx.indexTxTypes(from, end); err != nil {
+					return err
+				}
+			}
+
+			next := make([]byte, 8)
+			binary.LittleEndian.PutUint64(next, to)
+			tx.put(TxTypeIndexedPrefix, next)
+			return nil
+		})
+		if err != nil || to == math.MaxUint64 {
+			return err
+		}
+
+		from = to
+	}
+}
@@ -0,0 +1,87 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package heavy
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	assert "github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+func TestReindexTxTypes(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(os.TempDir(), "heavy_txindex_")
+	assert.NoError(err)
+
+	defer func() {
+		_ = closeStorage()
+		_ = os.RemoveAll(dir)
+	}()
+
+	db, err := NewDatabase(dir, protocol.DevNet, false)
+	assert.NoError(err)
+
+	blocks := make([]*block.Block, 5)
+	for i := range blocks {
+		blocks[i] = helper.RandomBlock(uint64(i), 1)
+	}
+
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		for _, blk := range blocks {
+			if err := t.StoreBlock(blk); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	countDistribute := func() int {
+		var count int
+
+		assert.NoError(db.View(func(t database.Transaction) error {
+			return t.FetchTxsByType(transactions.Distribute, 0, math.MaxUint64, func(transactions.ContractCall, uint64, uint32) error {
+				count++
+				return nil
+			})
+		}))
+
+		return count
+	}
+
+	indexed := countDistribute()
+	assert.NotZero(indexed)
+
+	// Simulate a database created before the index was introduced
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		tx := t.(*transaction)
+
+		iterator := tx.snapshot.NewIterator(util.BytesPrefix(TxTypePrefix), nil)
+		defer iterator.Release()
+
+		for iterator.Next() {
+			tx.batch.Delete(iterator.Key())
+		}
+
+		tx.batch.Delete(TxTypeIndexedPrefix)
+		return iterator.Error()
+	}))
+
+	assert.Zero(countDistribute())
+
+	assert.NoError(ReindexTxTypes(db))
+	assert.Equal(indexed, countDistribute())
+}
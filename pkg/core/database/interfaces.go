@@ -10,6 +10,7 @@ import (
 	"errors"
 	"math"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
@@ -29,6 +30,11 @@ var (
 	ErrStateNotFound = errors.New("database: state not found")
 	// ErrOutputNotFound returned on output lookup during tx verification.
 	ErrOutputNotFound = errors.New("database: output not found")
+	// ErrBlockPruned returned on a block lookup when the header is still
+	// stored but the block body has been pruned.
+	ErrBlockPruned = errors.New("database: block pruned")
+	// ErrProvisionersNotFound returned on a provisioners snapshot lookup.
+	ErrProvisionersNotFound = errors.New("database: provisioners not found")
 
 	// AnyTxType is used as a filter value on FetchBlockTxByHash.
 	AnyTxType = transactions.TxType(math.MaxUint8)
@@ -64,6 +70,11 @@ type Transaction interface {
 	// Fetch tx by txID. If succeeds, it returns tx data, tx index and
 	// hash of the block it belongs to.
 	FetchBlockTxByHash(txID []byte) (tx transactions.ContractCall, txIndex uint32, blockHeaderHash []byte, err error)
+	// FetchTxsByType calls fn for each tx of type txType stored in the
+	// height range [fromHeight, toHeight], in ascending (height, index)
+	// order. Iteration stops at the first error returned by fn, which is
+	// then returned to the caller.
+	FetchTxsByType(txType transactions.TxType, fromHeight, toHeight uint64, fn func(tx transactions.ContractCall, height uint64, txIndex uint32) error) error
 	FetchBlockHashByHeight(height uint64) ([]byte, error)
 	FetchBlockExists(hash []byte) (bool, error)
 	// Fetch chain state information (chain tip hash).
@@ -79,6 +90,13 @@ type Transaction interface {
 	// Not to be called concurrently, as it updates chain tip.
 	StoreBlock(block *block.Block) error
 
+	// DeleteBlock removes all data stored by StoreBlock for the chain tip
+	// block, as well as the provisioners snapshot at its height, and moves
+	// the chain tip to its previous block. It is the only exception to the
+	// append-only manner of the store, used to roll back the chain.
+	// Not to be called concurrently, as it updates chain tip.
+	DeleteBlock(block *block.Block) error
+
 	// FetchBlock will return a block, given a hash.
 	FetchBlock(hash []byte) (*block.Block, error)
 
@@ -86,6 +104,10 @@ type Transaction interface {
 	// block in the database.
 	FetchCurrentHeight() (uint64, error)
 
+	// FetchPrunedHeight returns the lowest height from which on block bodies
+	// are still stored. It returns 0 if no block has been pruned.
+	FetchPrunedHeight() (uint64, error)
+
 	// FetchOutputExists returns whether or not an output exists for the
 	// given destination public key.
 	FetchOutputExists(destkey []byte) (bool, error)
@@ -111,6 +133,15 @@ type Transaction interface {
 	// sinceUnixTime starting the search from height (tip - offset).
 	FetchBlockHeightSince(sinceUnixTime int64, offset uint64) (uint64, error)
 
+	// StoreProvisioners stores a snapshot of the provisioners set resulting
+	// from the execution of the block at height.
+	StoreProvisioners(height uint64, p *user.Provisioners) error
+
+	// FetchProvisioners returns the most recent provisioners snapshot stored
+	// at a height lower or equal to height, together with the height of the
+	// snapshot. It returns ErrProvisionersNotFound if there is none.
+	FetchProvisioners(height uint64) (p *user.Provisioners, snapshotHeight uint64, err error)
+
 	// StoreCandidateMessage will...
 	StoreCandidateMessage(cm block.Block) error
 
@@ -30,11 +30,19 @@ const (
 	bidValuesInd
 	outputKeyInd
 	candidateInd
+	txTypeInd
+	provisionersInd
 	maxInd
 )
 
 var stateKey = []byte{1}
 
+// delete marks an entry of a batch as deleted. The entry is removed from the
+// storage when the batch is committed.
+func (m memdb) delete(ind int, k []byte) {
+	m[ind][toKey(k)] = nil
+}
+
 // DB represents the db struct.
 type DB struct {
 	storage  memdb
@@ -12,7 +12,9 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
@@ -67,6 +69,11 @@ func (t *transaction) StoreBlock(b *block.Block) error {
 
 		t.batch[txsInd][toKey(txID)] = data
 		t.batch[txHashInd][toKey(txID)] = b.Header.Hash
+		t.batch[txTypeInd][toKey(txTypeKey(tx.Type(), b.Header.Height, uint32(i)))] = txID
+
+		for _, keyImage := range tx.StandardTx().Nullifiers {
+			t.batch[keyImagesInd][toKey(keyImage)] = txID
+		}
 	}
 
 	// Map height to buffer bytes
@@ -95,6 +102,47 @@ func (t *transaction) StoreBlock(b *block.Block) error {
 	return nil
 }
 
+// DeleteBlock removes all the entries of a block and sets the chain tip to its
+// previous block. As for StoreBlock, the deletions are written to the batch
+// and applied to the storage on Commit.
+func (t *transaction) DeleteBlock(b *block.Block) error {
+	if !t.writable {
+		return errors.New("read-only transaction")
+	}
+
+	t.batch.delete(blocksInd, b.Header.Hash)
+
+	for i, tx := range b.Txs {
+		txID, err := tx.CalculateHash()
+		if err != nil {
+			return err
+		}
+
+		t.batch.delete(txsInd, txID)
+		t.batch.delete(txHashInd, txID)
+		t.batch.delete(txTypeInd, txTypeKey(tx.Type(), b.Header.Height, uint32(i)))
+
+		// Only the key images spent by the tx are deleted
+		for _, keyImage := range tx.StandardTx().Nullifiers {
+			if bytes.Equal(t.db.storage[keyImagesInd][toKey(keyImage)], txID) {
+				t.batch.delete(keyImagesInd, keyImage)
+			}
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := utils.WriteUint64(buf, b.Header.Height); err != nil {
+		return err
+	}
+
+	t.batch.delete(heightInd, buf.Bytes())
+	t.batch.delete(provisionersInd, provisionersKey(b.Header.Height))
+
+	t.batch[stateInd][toKey(stateKey)] = b.Header.PrevBlockHash
+
+	return nil
+}
+
 // Commit writes a batch to LevelDB storage. See also fsyncEnabled variable.
 func (t *transaction) Commit() error {
 	if !t.writable {
@@ -104,6 +152,12 @@ func (t *transaction) Commit() error {
 	/// commit changes
 	for i := range t.db.storage {
 		for k, v := range t.batch[i] {
+			if v == nil {
+				// deleted entry, see memdb.delete
+				delete(t.db.storage[i], k)
+				continue
+			}
+
 			t.db.storage[i][k] = v
 		}
 	}
@@ -195,6 +249,100 @@ func (t transaction) FetchBlockTxByHash(txID []byte) (transactions.ContractCall,
 	return tx, txIndex, hash, err
 }
 
+// FetchTxsByType iterates over all txs of type txType stored in the height
+// range [fromHeight, toHeight]. See also database.Transaction.
+func (t transaction) FetchTxsByType(txType transactions.TxType, fromHeight, toHeight uint64, fn func(tx transactions.ContractCall, height uint64, txIndex uint32) error) error {
+	keys := make([]key, 0)
+
+	for k := range t.db.storage[txTypeInd] {
+		if k[0] != byte(txType) {
+			continue
+		}
+
+		height := binary.BigEndian.Uint64(k[1:])
+		if height >= fromHeight && height <= toHeight {
+			keys = append(keys, k)
+		}
+	}
+
+	// Keys are sorted by (height, index) as both are big-endian encoded
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i][:], keys[j][:]) < 0
+	})
+
+	for _, k := range keys {
+		tx, txIndex, _, err := t.FetchBlockTxByHash(t.db.storage[txTypeInd][k])
+		if err != nil {
+			return err
+		}
+
+		if err := fn(tx, binary.BigEndian.Uint64(k[1:]), txIndex); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StoreProvisioners stores a snapshot of the provisioners set at height.
+func (t *transaction) StoreProvisioners(height uint64, p *user.Provisioners) error {
+	if !t.writable {
+		return errors.New("read-only transaction")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := user.MarshalProvisioners(buf, p); err != nil {
+		return err
+	}
+
+	t.batch[provisionersInd][toKey(provisionersKey(height))] = buf.Bytes()
+	return nil
+}
+
+// FetchProvisioners returns the most recent provisioners snapshot stored at a
+// height lower or equal to height.
+func (t transaction) FetchProvisioners(height uint64) (*user.Provisioners, uint64, error) {
+	var (
+		data           []byte
+		snapshotHeight uint64
+	)
+
+	for k, v := range t.db.storage[provisionersInd] {
+		h := binary.BigEndian.Uint64(k[:8])
+		if h <= height && (data == nil || h > snapshotHeight) {
+			data, snapshotHeight = v, h
+		}
+	}
+
+	if data == nil {
+		return nil, 0, database.ErrProvisionersNotFound
+	}
+
+	p, err := user.UnmarshalProvisioners(bytes.NewBuffer(data))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &p, snapshotHeight, nil
+}
+
+func provisionersKey(height uint64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, height)
+
+	return k
+}
+
+// txTypeKey builds a txTypeInd key as txType + height + index.
+func txTypeKey(txType transactions.TxType, height uint64, txIndex uint32) []byte {
+	k := make([]byte, 1+8+4)
+	k[0] = byte(txType)
+	binary.BigEndian.PutUint64(k[1:], height)
+	binary.BigEndian.PutUint32(k[9:], txIndex)
+
+	return k
+}
+
 func (t transaction) FetchKeyImageExists(keyImage []byte) (bool, []byte, error) {
 	var txID []byte
 	var exists bool
@@ -283,6 +431,11 @@ func (t *transaction) FetchCurrentHeight() (uint64, error) {
 	return header.Height, nil
 }
 
+// FetchPrunedHeight always returns 0 as lite driver does not support pruning.
+func (t *transaction) FetchPrunedHeight() (uint64, error) {
+	return 0, nil
+}
+
 func (t *transaction) StoreBidValues(d, k []byte, index uint64, lockTime uint64) error {
 	currentHeight, err := t.FetchCurrentHeight()
 	if err != nil {
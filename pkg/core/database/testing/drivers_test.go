@@ -19,6 +19,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
@@ -30,6 +31,7 @@ import (
 
 	// Import here any supported drivers to verify if they are fully compliant
 	// to the blockchain database layer requirements.
+	_ "github.com/dusk-network/dusk-blockchain/pkg/core/database/bbolt"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
@@ -193,6 +195,99 @@ func TestStoreBlock(test *testing.T) {
 	}
 }
 
+func TestDeleteBlock(test *testing.T) {
+	genBlocks, err := generateChainBlocks(2)
+	require.NoError(test, err)
+
+	// Link the new blocks to the current chain tip
+	var prevTip []byte
+
+	require.NoError(test, db.View(func(t database.Transaction) error {
+		s, err1 := t.FetchState()
+		if err1 != nil {
+			return err1
+		}
+
+		prevTip = s.TipHash
+		return nil
+	}))
+
+	genBlocks[0].Header.PrevBlockHash = prevTip
+	genBlocks[1].Header.PrevBlockHash = genBlocks[0].Header.Hash
+
+	require.NoError(test, storeBlocks(db, genBlocks))
+
+	// Collect the key images spent by the txs of the tip
+	deleted := genBlocks[1]
+	keyImages := make([][]byte, 0)
+
+	require.NoError(test, db.View(func(t database.Transaction) error {
+		for _, tx := range deleted.Txs {
+			txID, _ := tx.CalculateHash()
+
+			for _, keyImage := range tx.StandardTx().Nullifiers {
+				exists, owner, err1 := t.FetchKeyImageExists(keyImage)
+				require.NoError(test, err1)
+				require.True(test, exists)
+
+				if bytes.Equal(owner, txID) {
+					keyImages = append(keyImages, keyImage)
+				}
+			}
+		}
+
+		return nil
+	}))
+
+	require.NotEmpty(test, keyImages)
+
+	// Remove the tip and ensure it is not reachable anymore
+	require.NoError(test, db.Update(func(t database.Transaction) error {
+		return t.DeleteBlock(deleted)
+	}))
+
+	require.NoError(test, db.View(func(t database.Transaction) error {
+		s, err1 := t.FetchState()
+		require.NoError(test, err1)
+		require.Equal(test, genBlocks[0].Header.Hash, s.TipHash)
+
+		_, err1 = t.FetchBlockExists(deleted.Header.Hash)
+		require.Equal(test, database.ErrBlockNotFound, err1)
+
+		_, err1 = t.FetchBlockHashByHeight(deleted.Header.Height)
+		require.Equal(test, database.ErrBlockNotFound, err1)
+
+		for _, tx := range deleted.Txs {
+			txID, _ := tx.CalculateHash()
+			_, _, _, err1 = t.FetchBlockTxByHash(txID)
+			require.Equal(test, database.ErrTxNotFound, err1)
+		}
+
+		// The key images are not spent anymore
+		for _, keyImage := range keyImages {
+			exists, _, err1 := t.FetchKeyImageExists(keyImage)
+			require.Equal(test, database.ErrKeyImageNotFound, err1)
+			require.False(test, exists)
+		}
+
+		return t.FetchTxsByType(transactions.Distribute, deleted.Header.Height, deleted.Header.Height, func(transactions.ContractCall, uint64, uint32) error {
+			return errors.New("deleted tx fetched by type")
+		})
+	}))
+
+	// Restore the initial chain tip
+	require.NoError(test, db.Update(func(t database.Transaction) error {
+		return t.DeleteBlock(genBlocks[0])
+	}))
+
+	require.NoError(test, db.View(func(t database.Transaction) error {
+		s, err1 := t.FetchState()
+		require.NoError(test, err1)
+		require.Equal(test, prevTip, s.TipHash)
+		return nil
+	}))
+}
+
 func TestFetchBlockExists(test *testing.T) {
 	test.Parallel()
 
@@ -409,6 +504,41 @@ func TestAtomicUpdates(test *testing.T) {
 	}
 }
 
+// TestFailedUpdateStoresNoBlock ensures that none of the blocks stored by a
+// failed read-write Tx can be fetched. Unlike TestAtomicUpdates, it applies
+// to the drivers without storage snapshots too.
+func TestFailedUpdateStoresNoBlock(test *testing.T) {
+	genBlocks := generateRandomBlocks(2)
+
+	forcedError := errors.New("force majeure situation")
+	err := db.Update(func(t database.Transaction) error {
+		for _, block := range genBlocks {
+			if err := t.StoreBlock(block); err != nil {
+				return err
+			}
+		}
+
+		return forcedError
+	})
+
+	if err != forcedError {
+		test.Fatalf("ForcedError must be returned from previous statement")
+	}
+
+	err = db.View(func(t database.Transaction) error {
+		for _, block := range genBlocks {
+			if _, err := t.FetchBlockExists(block.Header.Hash); err != database.ErrBlockNotFound {
+				return fmt.Errorf("block %d of a failed Tx is stored", block.Header.Height)
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+}
+
 // TestReadOnlyTx ensures that a read-only DB tx cannot touch the storage state.
 func TestReadOnlyTx(test *testing.T) {
 	test.Parallel()
@@ -630,6 +760,132 @@ func TestFetchBlockTxByHash(test *testing.T) {
 	})
 }
 
+func TestFetchTxsByType(test *testing.T) {
+	test.Parallel()
+
+	fromHeight := blocks[2].Header.Height
+	toHeight := blocks[6].Header.Height
+
+	for _, txType := range []transactions.TxType{transactions.Distribute, transactions.Stake, transactions.Bid} {
+		// Collect the expected txs in (height, index) order
+		expected := make([][]byte, 0)
+
+		for _, blk := range blocks {
+			if blk.Header.Height < fromHeight || blk.Header.Height > toHeight {
+				continue
+			}
+
+			for _, tx := range blk.Txs {
+				if tx.Type() == txType {
+					txID, _ := tx.CalculateHash()
+					expected = append(expected, txID)
+				}
+			}
+		}
+
+		fetched := make([][]byte, 0)
+		lastHeight := fromHeight
+
+		err := db.View(func(t database.Transaction) error {
+			return t.FetchTxsByType(txType, fromHeight, toHeight, func(tx transactions.ContractCall, height uint64, txIndex uint32) error {
+				if tx.Type() != txType {
+					return fmt.Errorf("unexpected tx type %d", tx.Type())
+				}
+
+				if height < lastHeight || height > toHeight {
+					return fmt.Errorf("unexpected height %d", height)
+				}
+
+				lastHeight = height
+
+				txID, err := tx.CalculateHash()
+				if err != nil {
+					return err
+				}
+
+				fetched = append(fetched, txID)
+				return nil
+			})
+		})
+
+		require.NoError(test, err)
+		require.Equal(test, expected, fetched)
+	}
+
+	// Ensure iteration stops on the first error returned by the callback
+	errStop := errors.New("stop")
+	calls := 0
+
+	err := db.View(func(t database.Transaction) error {
+		return t.FetchTxsByType(transactions.Distribute, fromHeight, toHeight, func(transactions.ContractCall, uint64, uint32) error {
+			calls++
+			return errStop
+		})
+	})
+
+	require.Equal(test, errStop, err)
+	require.Equal(test, 1, calls)
+}
+
+func TestStoreFetchProvisioners(test *testing.T) {
+	test.Parallel()
+
+	// Use heights far above the sample chain not to interfere with other tests
+	base := uint64(1000000)
+	snapshots := make(map[uint64]*user.Provisioners)
+
+	for _, height := range []uint64{base, base + 10, base + 20} {
+		p := user.NewProvisioners()
+
+		for i := 0; i < 3; i++ {
+			blsKey, _ := crypto.RandEntropy(129)
+			require.NoError(test, p.Add(blsKey, height+uint64(i), height, height+1000))
+		}
+
+		snapshots[height] = p
+	}
+
+	require.NoError(test, db.Update(func(t database.Transaction) error {
+		for height, p := range snapshots {
+			if err := t.StoreProvisioners(height, p); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+
+	var tests = []struct {
+		height         uint64
+		snapshotHeight uint64
+	}{
+		{base, base},
+		{base + 5, base},
+		{base + 10, base + 10},
+		{base + 19, base + 10},
+		{base + 1000, base + 20},
+	}
+
+	require.NoError(test, db.View(func(t database.Transaction) error {
+		for _, tt := range tests {
+			p, snapshotHeight, err := t.FetchProvisioners(tt.height)
+			require.NoError(test, err)
+			require.Equal(test, tt.snapshotHeight, snapshotHeight)
+
+			expected := snapshots[tt.snapshotHeight]
+			require.Equal(test, expected.Set, p.Set)
+
+			for k, m := range expected.Members {
+				require.Equal(test, m.Stakes, p.Members[k].Stakes)
+			}
+		}
+
+		_, _, err := t.FetchProvisioners(base - 1)
+		require.Equal(test, database.ErrProvisionersNotFound, err)
+		return nil
+	}))
+}
+
 func TestClearDatabase(test *testing.T) {
 	err := db.Update(func(t database.Transaction) error {
 		return t.ClearDatabase()
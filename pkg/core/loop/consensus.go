@@ -24,7 +24,6 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
-	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -51,6 +50,7 @@ type Consensus struct {
 
 	agreementChan chan message.Message
 	eventChan     chan message.Message
+	dropped       *dropCounters
 }
 
 // CreateStateMachine creates and link the steps in the consensus. It is kept separated from
@@ -83,17 +83,22 @@ func CreateInitialStep(e *consensus.Emitter, consensusTimeOut time.Duration, bg
 // New creates a new Consensus struct. The legacy StopConsensus and RoundUpdate
 // are now replaced with context cancellation and direct function call operated
 // by the chain component.
+//
+// The capacities of the message channels and queues are configured in
+// [consensus.queues].
 func New(e *consensus.Emitter, pubKey *keys.PublicKey) *Consensus {
-	// TODO: channel size should be configurable
-	agreementChan := make(chan message.Message, 1000)
-	eventChan := make(chan message.Message, 1000)
+	agreementChanSize, eventChanSize, maxRoundEvents, maxRounds := queuesConfiguration()
+
+	agreementChan := make(chan message.Message, agreementChanSize)
+	eventChan := make(chan message.Message, eventChanSize)
+	dropped := new(dropCounters)
 
 	// subscribe agreement phase to message.Agreement
-	aChan := eventbus.NewChanListener(agreementChan)
+	aChan := newCountingListener(agreementChan, &dropped.agreement)
 	e.EventBus.Subscribe(topics.Agreement, aChan)
 
 	// subscribe topics to eventChan
-	evSub := eventbus.NewChanListener(eventChan)
+	evSub := newCountingListener(eventChan, &dropped.event)
 
 	e.EventBus.AddDefaultTopic(topics.Reduction, topics.Score)
 	e.EventBus.SubscribeDefault(evSub)
@@ -102,10 +107,11 @@ func New(e *consensus.Emitter, pubKey *keys.PublicKey) *Consensus {
 		Emitter:       e,
 		Requestor:     candidate.NewRequestor(e.EventBus),
 		pubKey:        pubKey,
-		eventQueue:    consensus.NewQueue(),
-		roundQueue:    consensus.NewQueue(),
+		eventQueue:    consensus.NewBoundedQueue(maxRoundEvents, maxRounds),
+		roundQueue:    consensus.NewBoundedQueue(maxRoundEvents, maxRounds),
 		agreementChan: agreementChan,
 		eventChan:     eventChan,
+		dropped:       dropped,
 	}
 
 	return c
@@ -117,13 +123,34 @@ func (c *Consensus) CreateStateMachine(db database.DB, consensusTimeOut time.Dur
 	return CreateStateMachine(c.Emitter, db, consensusTimeOut, c.pubKey.Copy(), verifyFn, c.Requestor)
 }
 
-//nolint:wsl
 // Spin the consensus state machine. The consensus runs for the whole round
 // until either a new round is produced or the node needs to re-sync. The
 // Agreement loop (acting roundwise) runs concurrently with the generation-selection-reduction
 // loop (acting step-wise).
-// TODO: consider stopping the phase loop with a Done phase, instead of nil.
+// The start and the outcome of the round are recorded into the journal. The
+// step timeouts increased during a successful round are reset.
+// The messages queued for the past rounds are dropped.
 func (c *Consensus) Spin(ctx context.Context, scr consensus.Phase, ag consensus.Controller, round consensus.RoundUpdate) consensus.Results {
+	c.Journal.RoundStarted(round.Round, round.Seed, round.Hash, &round.P)
+
+	c.eventQueue.Prune(round.Round)
+	c.roundQueue.Prune(round.Round)
+
+	results := c.spin(ctx, scr, ag, round)
+
+	c.Journal.RoundEnded(round.Round, results.Blk, results.Err)
+
+	if results.Err == nil {
+		c.Timeouts.Reset()
+	}
+
+	return results
+}
+
+// TODO: consider stopping the phase loop with a Done phase, instead of nil.
+//
+//nolint:wsl
+func (c *Consensus) spin(ctx context.Context, scr consensus.Phase, ag consensus.Controller, round consensus.RoundUpdate) consensus.Results {
 	// we create two context cancelation from the same parent context. This way
 	// we can let the agreement interrupt the stateMachine's loop cycle.
 	// Similarly, the loop can invoke the Agreement cancelation if it throws
@@ -158,6 +185,8 @@ func (c *Consensus) Spin(ctx context.Context, scr consensus.Phase, ag consensus.
 	// synchronous consensus loop keeps running until the agreement invokes
 	// context.Done or the context is canceled some other way
 	for step := uint8(1); ; step++ {
+		c.Journal.PhaseStarted(round.Round, step, phaseFunction.String())
+
 		phaseFunction = phaseFunction.Run(stepCtx, c.eventQueue, c.eventChan, round, step)
 		// if result is nil, this round is over
 		if phaseFunction == nil {
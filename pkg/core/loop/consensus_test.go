@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/agreement"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
@@ -20,6 +21,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/loop"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/stretchr/testify/require"
 )
 
@@ -153,3 +155,29 @@ func TestStall(t *testing.T) {
 	l := loop.New(e, keys.NewPublicKey())
 	_ = l.Spin(ctx, &stallingStep{}, &unsuccesfulAgreement{}, consensus.RoundUpdate{Round: uint64(1)})
 }
+
+// TestChannelDrops tests that the messages dropped by a full channel are
+// counted.
+func TestChannelDrops(t *testing.T) {
+	conf := config.Get()
+
+	r := conf
+	r.Consensus.Queues.AgreementChanSize = 2
+	config.Mock(&r)
+
+	defer config.Mock(&conf)
+
+	e := consensus.MockEmitter(time.Second, nil)
+	l := loop.New(e, keys.NewPublicKey())
+
+	for i := 0; i < 5; i++ {
+		e.EventBus.Publish(topics.Agreement, message.New(topics.Agreement, message.Agreement{}))
+	}
+
+	stats := l.Stats()
+	require.Equal(t, 2, stats.AgreementChan.Len)
+	require.Equal(t, 2, stats.AgreementChan.Cap)
+	require.Equal(t, uint64(3), stats.AgreementChan.Dropped)
+	require.Equal(t, 1000, stats.EventChan.Cap)
+	require.Zero(t, stats.EventChan.Dropped)
+}
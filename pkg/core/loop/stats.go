@@ -0,0 +1,136 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package loop
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+)
+
+const (
+	defaultChanSize       = 1000
+	defaultMaxRoundEvents = 10000
+	defaultMaxRounds      = 10
+)
+
+// Stats reports the occupation of the consensus channels and queues.
+type Stats struct {
+	AgreementChan ChanStats `json:"agreementChan"`
+	EventChan     ChanStats `json:"eventChan"`
+	// EventQueue holds the Score and Reduction messages of the steps and the
+	// rounds ahead, and RoundQueue the Agreement messages of the rounds
+	// ahead.
+	EventQueue consensus.QueueStats `json:"eventQueue"`
+	RoundQueue consensus.QueueStats `json:"roundQueue"`
+}
+
+// ChanStats reports the occupation of a consensus channel.
+type ChanStats struct {
+	Len int `json:"len"`
+	Cap int `json:"cap"`
+	// Dropped is the number of messages dropped because the channel was
+	// full.
+	Dropped uint64 `json:"dropped"`
+}
+
+// Stats returns the occupation of the consensus channels and queues.
+func (c *Consensus) Stats() Stats {
+	return Stats{
+		AgreementChan: ChanStats{
+			Len:     len(c.agreementChan),
+			Cap:     cap(c.agreementChan),
+			Dropped: atomic.LoadUint64(&c.dropped.agreement),
+		},
+		EventChan: ChanStats{
+			Len:     len(c.eventChan),
+			Cap:     cap(c.eventChan),
+			Dropped: atomic.LoadUint64(&c.dropped.event),
+		},
+		EventQueue: c.eventQueue.Stats(),
+		RoundQueue: c.roundQueue.Stats(),
+	}
+}
+
+// ServeStats answers the topics.GetConsensusQueues requests of the rpcbus,
+// until the context is canceled.
+func (c *Consensus) ServeStats(ctx context.Context, rpcBus *rpcbus.RPCBus) error {
+	reqChan := make(chan rpcbus.Request, 1)
+	if err := rpcBus.Register(topics.GetConsensusQueues, reqChan); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case r := <-reqChan:
+				r.RespChan <- rpcbus.NewResponse(c.Stats(), nil)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// dropCounters are the messages dropped by the consensus channels. They are
+// allocated apart from the Consensus, to keep them 64-bit aligned for the
+// atomic operations.
+type dropCounters struct {
+	agreement uint64
+	event     uint64
+}
+
+// countingListener counts the messages a ChanListener drops because its
+// channel is full.
+type countingListener struct {
+	eventbus.Listener
+	dropped *uint64
+}
+
+func newCountingListener(msgChan chan<- message.Message, dropped *uint64) eventbus.Listener {
+	return &countingListener{
+		Listener: eventbus.NewChanListener(msgChan),
+		dropped:  dropped,
+	}
+}
+
+// Notify forwards the message to the channel, counting the drops.
+func (l *countingListener) Notify(m message.Message) error {
+	err := l.Listener.Notify(m)
+	if err != nil {
+		atomic.AddUint64(l.dropped, 1)
+	}
+
+	return err
+}
+
+// queuesConfiguration returns the configured capacities of the consensus
+// channels and queues, with the defaults for the unset ones.
+func queuesConfiguration() (agreementChanSize, eventChanSize, maxRoundEvents, maxRounds int) {
+	conf := config.Get().Consensus.Queues
+
+	orDefault := func(v, d int) int {
+		if v <= 0 {
+			return d
+		}
+
+		return v
+	}
+
+	return orDefault(conf.AgreementChanSize, defaultChanSize),
+		orDefault(conf.EventChanSize, defaultChanSize),
+		orDefault(conf.MaxRoundEvents, defaultMaxRoundEvents),
+		orDefault(conf.MaxRounds, defaultMaxRounds)
+}
@@ -0,0 +1,152 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package mempool
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+)
+
+// maxRejections is the number of rejected txs remembered to be reported by
+// SelectTx.
+const maxRejections = 1000
+
+var (
+	// ErrQuotaExceeded the submitter has too many txs in the mempool.
+	ErrQuotaExceeded = errors.New("admission quota exceeded")
+	// ErrReplacementUnderpriced transaction spends the nullifiers of
+	// mempool txs, without paying enough to replace them.
+	ErrReplacementUnderpriced = errors.New("replacement fee too low")
+)
+
+// TxRequest is the topics.SendMempoolTx request submitted on behalf of a RPC
+// client. A bare transactions.ContractCall is accepted as well, and is not
+// subject to the client quota.
+type TxRequest struct {
+	Tx transactions.ContractCall
+	// Client is the address of the RPC client. Empty for txs created by the
+	// node itself.
+	Client string
+}
+
+// checkQuota ensures that the submitter has not reached its maximum number of
+// txs in the mempool. The txs of the submitter about to be replaced do not
+// count, so that a submitter at its quota can still bump a fee.
+func (m *Mempool) checkQuota(source txSource, replaced map[txHash]TxDesc) error {
+	var limit int
+
+	switch source.kind {
+	case sourcePeer:
+		limit = config.Get().Mempool.MaxTxsPerPeer
+	case sourceRPC:
+		limit = config.Get().Mempool.MaxTxsPerClient
+	}
+
+	if limit <= 0 {
+		return nil
+	}
+
+	count := m.verified.CountBySource(source)
+
+	for _, t := range replaced {
+		if t.source == source {
+			count--
+		}
+	}
+
+	if count >= limit {
+		return fmt.Errorf("%w: %s has %d pending txs", ErrQuotaExceeded, source.addr, limit)
+	}
+
+	return nil
+}
+
+// replacedTxs returns the mempool txs spending any of the nullifiers spent by
+// tx. The fee of tx must be higher than the fee of each of them by the
+// configured margin, otherwise ErrReplacementUnderpriced is returned.
+func (m *Mempool) replacedTxs(tx transactions.ContractCall) (map[txHash]TxDesc, error) {
+	conflicts := m.verified.Conflicts(tx)
+	if len(conflicts) == 0 {
+		return conflicts, nil
+	}
+
+	_, fee := tx.Values()
+	margin := config.Get().Mempool.ReplaceFeeMargin
+
+	for k, t := range conflicts {
+		_, pendingFee := t.tx.Values()
+
+		required := replacementFee(pendingFee, margin)
+		if fee < required {
+			return nil, fmt.Errorf("%w: fee %d is lower than %d required to replace tx %s", ErrReplacementUnderpriced, fee, required, toHex(k[:]))
+		}
+	}
+
+	return conflicts, nil
+}
+
+// replacementFee returns the minimum fee to replace a tx paying fee. It is
+// always higher than fee, even with a zero margin.
+func replacementFee(fee, margin uint64) uint64 {
+	bump := fee/100*margin + fee%100*margin/100
+	if bump == 0 {
+		bump = 1
+	}
+
+	if fee > math.MaxUint64-bump {
+		return math.MaxUint64
+	}
+
+	return fee + bump
+}
+
+// rejections remembers the reasons of the latest rejected txs.
+type rejections struct {
+	lock    sync.Mutex
+	reasons map[txHash]string
+	order   []txHash
+}
+
+func newRejections() *rejections {
+	return &rejections{reasons: make(map[txHash]string)}
+}
+
+func (r *rejections) add(txid []byte, reason string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var k txHash
+	copy(k[:], txid)
+
+	if _, ok := r.reasons[k]; !ok {
+		r.order = append(r.order, k)
+	}
+
+	r.reasons[k] = reason
+
+	// Forget the oldest rejection
+	if len(r.order) > maxRejections {
+		delete(r.reasons, r.order[0])
+		r.order = r.order[1:]
+	}
+}
+
+func (r *rejections) get(txid []byte) (string, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var k txHash
+	copy(k[:], txid)
+
+	reason, ok := r.reasons[k]
+	return reason, ok
+}
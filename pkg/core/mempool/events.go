@@ -0,0 +1,160 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package mempool
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+)
+
+// TxEventKind is the stage of the tx lifecycle notified by a TxEvent.
+type TxEventKind string
+
+const (
+	// TxAdmitted means that the tx has been verified and added to the mempool.
+	TxAdmitted TxEventKind = "admitted"
+	// TxEvicted means that the tx has been removed from the mempool without
+	// being accepted into a block. See TxEvent.Reason.
+	TxEvicted TxEventKind = "evicted"
+	// TxReplaced means that the tx has been removed from the mempool, as a
+	// tx spending the same nullifiers with a higher fee has been admitted.
+	TxReplaced TxEventKind = "replaced"
+	// TxAccepted means that the tx has been included in an accepted block.
+	TxAccepted TxEventKind = "accepted"
+)
+
+// TxEvent notifies a change in the mempool status of a tx.
+type TxEvent struct {
+	Kind   TxEventKind         `json:"event"`
+	TxID   string              `json:"txid"`
+	TxType transactions.TxType `json:"txtype"`
+	// Reason is set on TxEvicted only.
+	Reason message.EvictionReason `json:"reason,omitempty"`
+	// Height is set on TxAccepted only.
+	Height uint64 `json:"height,omitempty"`
+}
+
+// TxFilter selects the TxEvents a subscriber is interested in. An empty
+// list matches any value, so that the zero TxFilter matches all events.
+type TxFilter struct {
+	Types []transactions.TxType `json:"types,omitempty"`
+	// TxIDs are hex-encoded.
+	TxIDs []string `json:"txids,omitempty"`
+}
+
+// Match returns true if the event satisfies both the type and the txid
+// criteria of the filter.
+func (f TxFilter) Match(e TxEvent) bool {
+	if len(f.Types) > 0 {
+		found := false
+
+		for _, t := range f.Types {
+			if t == e.TxType {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if len(f.TxIDs) > 0 {
+		for _, id := range f.TxIDs {
+			if strings.EqualFold(id, e.TxID) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// txEventTopics are the eventbus topics describing the tx lifecycle.
+var txEventTopics = []topics.Topic{topics.AdmittedTx, topics.EvictedTx, topics.AcceptedBlock}
+
+// TxEventSubscription collects the messages needed to build the TxEvents.
+// Messages are dropped, rather than blocking the eventbus, when a subscriber
+// does not keep up with the buffer of its subscription.
+type TxEventSubscription struct {
+	// C receives the messages to be converted with ToTxEvents.
+	C <-chan message.Message
+
+	subscriber eventbus.Subscriber
+	ids        map[topics.Topic]uint32
+}
+
+// SubscribeTxEvents subscribes to the topics of the tx lifecycle.
+func SubscribeTxEvents(subscriber eventbus.Subscriber, bufSize int) *TxEventSubscription {
+	msgChan := make(chan message.Message, bufSize)
+	listener := eventbus.NewChanListener(msgChan)
+
+	s := &TxEventSubscription{
+		C:          msgChan,
+		subscriber: subscriber,
+		ids:        make(map[topics.Topic]uint32, len(txEventTopics)),
+	}
+
+	for _, topic := range txEventTopics {
+		s.ids[topic] = subscriber.Subscribe(topic, listener)
+	}
+
+	return s
+}
+
+// Unsubscribe from all topics.
+func (s *TxEventSubscription) Unsubscribe() {
+	for topic, id := range s.ids {
+		s.subscriber.Unsubscribe(topic, id)
+	}
+}
+
+// ToTxEvents converts a message received by a TxEventSubscription. An
+// accepted block results into an event per tx.
+func ToTxEvents(msg message.Message) ([]TxEvent, error) {
+	switch p := msg.Payload().(type) {
+	case message.AdmittedTx:
+		return []TxEvent{{Kind: TxAdmitted, TxID: hex.EncodeToString(p.TxID), TxType: p.TxType}}, nil
+	case message.EvictedTx:
+		e := TxEvent{Kind: TxEvicted, TxID: hex.EncodeToString(p.TxID), TxType: p.TxType, Reason: p.Reason}
+		if p.Reason == message.EvictedReplaced {
+			e.Kind = TxReplaced
+		}
+
+		return []TxEvent{e}, nil
+	case block.Block:
+		events := make([]TxEvent, 0, len(p.Txs))
+
+		for _, tx := range p.Txs {
+			txid, err := tx.CalculateHash()
+			if err != nil {
+				return nil, err
+			}
+
+			events = append(events, TxEvent{
+				Kind:   TxAccepted,
+				TxID:   hex.EncodeToString(txid),
+				TxType: tx.Type(),
+				Height: p.Header.Height,
+			})
+		}
+
+		return events, nil
+	default:
+		return nil, fmt.Errorf("unexpected tx event payload %T", p)
+	}
+}
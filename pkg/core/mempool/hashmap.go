@@ -31,8 +31,11 @@ type (
 		// Block Generator to fetch highest-fee txs without delays in sorting.
 		sorted []keyFee
 
-		// spent key images from the transactions in the pool
-		// spentkeyImages map[keyImage]bool.
+		// nullifiers spent by the transactions in the pool
+		nullifiers map[string]txHash
+		// number of transactions in the pool per submitter
+		sources map[txSource]int
+
 		Capacity uint32
 		txsSize  uint32
 	}
@@ -47,6 +50,8 @@ func (m *HashMap) Put(t TxDesc) error {
 	if m.data == nil {
 		m.data = make(map[txHash]TxDesc, m.Capacity)
 		m.sorted = make([]keyFee, 0, m.Capacity)
+		m.nullifiers = make(map[string]txHash, m.Capacity)
+		m.sources = make(map[txSource]int)
 	}
 
 	// store tx
@@ -61,6 +66,12 @@ func (m *HashMap) Put(t TxDesc) error {
 	m.data[k] = t
 	m.txsSize += uint32(t.size)
 
+	for _, n := range nullifiers(t.tx) {
+		m.nullifiers[string(n)] = k
+	}
+
+	m.sources[t.source]++
+
 	// sort keys by Fee
 	// Bulk sort like (sort.Slice) performs a few times slower than
 	// a simple binarysearch&shift algorithm.
@@ -155,9 +166,7 @@ func (m *HashMap) Delete(txID []byte) {
 		return
 	}
 
-	m.txsSize -= uint32(tx.size)
-
-	delete(m.data, k)
+	m.remove(k, tx)
 
 	// TODO: this is naive, and may be improved upon.
 	for i, entry := range m.sorted {
@@ -167,6 +176,73 @@ func (m *HashMap) Delete(txID []byte) {
 	}
 }
 
+// remove deletes the entry from data and from the indexes, except sorted.
+func (m *HashMap) remove(k txHash, t TxDesc) {
+	m.txsSize -= uint32(t.size)
+
+	delete(m.data, k)
+
+	for _, n := range nullifiers(t.tx) {
+		if m.nullifiers[string(n)] == k {
+			delete(m.nullifiers, string(n))
+		}
+	}
+
+	m.sources[t.source]--
+	if m.sources[t.source] <= 0 {
+		delete(m.sources, t.source)
+	}
+}
+
+// Conflicts returns the txs spending any of the nullifiers spent by tx.
+func (m *HashMap) Conflicts(tx transactions.ContractCall) map[txHash]TxDesc {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	conflicts := make(map[txHash]TxDesc)
+
+	for _, n := range nullifiers(tx) {
+		if k, ok := m.nullifiers[string(n)]; ok {
+			conflicts[k] = m.data[k]
+		}
+	}
+
+	return conflicts
+}
+
+// CountBySource returns the number of txs submitted by source.
+func (m *HashMap) CountBySource(source txSource) int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.sources[source]
+}
+
+// EvictLowestFee deletes the lowest-fee entries until the pool size is not
+// bigger than maxSize, and returns them. Among entries with the same fee, the
+// latest received is evicted first.
+func (m *HashMap) EvictLowestFee(maxSize uint32) []TxDesc {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	evicted := make([]TxDesc, 0)
+
+	for m.txsSize > maxSize && len(m.sorted) > 0 {
+		entry := m.sorted[len(m.sorted)-1]
+		m.sorted = m.sorted[:len(m.sorted)-1]
+
+		tx, ok := m.data[entry.k]
+		if !ok {
+			continue
+		}
+
+		m.remove(entry.k, tx)
+
+		evicted = append(evicted, tx)
+	}
+
+	return evicted
+}
+
 // Size of the txs.
 func (m *HashMap) Size() uint32 {
 	m.lock.RLock()
@@ -215,3 +291,13 @@ func (m *HashMap) RangeSort(fn func(k txHash, t TxDesc) (bool, error)) error {
 
 	return nil
 }
+
+// nullifiers returns the nullifiers spent by tx.
+func nullifiers(tx transactions.ContractCall) [][]byte {
+	p := tx.StandardTx()
+	if p == nil {
+		return nil
+	}
+
+	return p.Nullifiers
+}
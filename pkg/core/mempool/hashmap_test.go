@@ -108,6 +108,40 @@ func TestGet(t *testing.T) {
 	assert.Nil(pool.Get(hash))
 }
 
+func TestEvictLowestFee(t *testing.T) {
+	assert := assert.New(t)
+	pool := HashMap{lock: &sync.RWMutex{}, Capacity: 10}
+
+	for i := 0; i < 10; i++ {
+		td := TxDesc{tx: transactions.RandTx(), received: time.Now(), size: 100}
+		assert.NoError(pool.Put(td))
+	}
+
+	evicted := pool.EvictLowestFee(600)
+	assert.Len(evicted, 4)
+	assert.Equal(6, pool.Len())
+	assert.Equal(uint32(600), pool.Size())
+
+	// All evicted txs have a fee not higher than the remaining ones
+	var maxEvictedFee uint64
+
+	for _, td := range evicted {
+		if _, fee := td.tx.Values(); fee > maxEvictedFee {
+			maxEvictedFee = fee
+		}
+	}
+
+	assert.NoError(pool.Range(func(k txHash, td TxDesc) error {
+		if _, fee := td.tx.Values(); fee < maxEvictedFee {
+			return errors.New("a higher fee tx has been evicted")
+		}
+		return nil
+	}))
+
+	// No eviction is needed below the maximum size
+	assert.Empty(pool.EvictLowestFee(600))
+}
+
 func BenchmarkPut(b *testing.B) {
 	txs := transactions.RandContractCalls(50000, 0, false)
 
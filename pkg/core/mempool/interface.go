@@ -14,6 +14,25 @@ import (
 
 type txHash [32]byte
 
+// sourceKind tells how a tx has been submitted to the mempool.
+type sourceKind uint8
+
+const (
+	// sourceInternal txs are created by the node itself, or re-admitted
+	// after a rollback. They are not subject to admission quotas.
+	sourceInternal sourceKind = iota
+	// sourcePeer txs are received from a network peer.
+	sourcePeer
+	// sourceRPC txs are submitted by a RPC client.
+	sourceRPC
+)
+
+// txSource identifies the submitter of a tx, for admission quotas.
+type txSource struct {
+	kind sourceKind
+	addr string
+}
+
 // TxDesc encapsulates both tx raw and meta data.
 type TxDesc struct {
 	tx transactions.ContractCall
@@ -24,6 +43,9 @@ type TxDesc struct {
 	verified time.Time
 	size     uint
 
+	// the submitter of the tx.
+	source txSource
+
 	// Kadcast transport-specific field.
 	kadHeight byte
 }
@@ -39,9 +61,17 @@ type Pool interface {
 	Contains(key []byte) bool
 	// Delete a key in the pool.
 	Delete(key []byte)
+	// EvictLowestFee deletes the lowest-fee entries until the pool size is
+	// not bigger than maxSize, and returns them.
+	EvictLowestFee(maxSize uint32) []TxDesc
 	// Clone the entire pool.
 	Clone() []transactions.ContractCall
 
+	// Conflicts returns the txs spending any of the nullifiers spent by tx.
+	Conflicts(tx transactions.ContractCall) map[txHash]TxDesc
+	// CountBySource returns the number of txs submitted by source.
+	CountBySource(source txSource) int
+
 	// FilterByType returns all verified transactions for a specific type.
 	FilterByType(transactions.TxType) []transactions.ContractCall
 
@@ -0,0 +1,307 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package mempool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+)
+
+// journalVersion is the version of the journal encoding, written as first
+// byte of the journal file.
+const journalVersion uint8 = 2
+
+// journalRetryInterval is the interval between two attempts to verify the
+// journaled txs, while the verifier is not available.
+const journalRetryInterval = 10 * time.Second
+
+// unverifiedTxs holds the journaled txs that could not be verified on
+// reload, as the verifier was not available. They are journaled again until
+// verified, so that they are not lost if the node restarts meanwhile.
+type unverifiedTxs struct {
+	lock sync.Mutex
+	txs  []TxDesc
+}
+
+func (u *unverifiedTxs) get() []TxDesc {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	return append([]TxDesc{}, u.txs...)
+}
+
+func (u *unverifiedTxs) set(txs []TxDesc) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	u.txs = txs
+}
+
+// journalInterval returns the configured interval between two journal
+// writes.
+func journalInterval() time.Duration {
+	interval := time.Duration(config.Get().Mempool.JournalInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return interval
+}
+
+// writeJournal writes all verified txs, and the journaled txs not verified
+// yet, into the journal file. The journal is written into a temporary file
+// first, and then renamed, so that a crash cannot leave a truncated journal.
+//
+// Journal encoding is the version, followed by one record per tx:
+// received (unix nanoseconds, uint64 LE) + source kind (uint8) +
+// VarBytes(source address) + VarBytes(transactions.Marshal()).
+func (m *Mempool) writeJournal() error {
+	path := config.Get().Mempool.JournalFile
+	if path == "" {
+		return nil
+	}
+
+	buf := new(bytes.Buffer)
+	if err := encoding.WriteUint8(buf, journalVersion); err != nil {
+		return err
+	}
+
+	var count int
+
+	write := func(t TxDesc) error {
+		txBuf := new(bytes.Buffer)
+		if err := transactions.Marshal(txBuf, t.tx); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteUint64LE(buf, uint64(t.received.UnixNano())); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteUint8(buf, uint8(t.source.kind)); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteVarBytes(buf, []byte(t.source.addr)); err != nil {
+			return err
+		}
+
+		count++
+		return encoding.WriteVarBytes(buf, txBuf.Bytes())
+	}
+
+	err := m.verified.Range(func(k txHash, t TxDesc) error {
+		return write(t)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, t := range m.unverified.get() {
+		if err := write(t); err != nil {
+			return err
+		}
+	}
+
+	tmpPath := path + ".tmp"
+
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+
+	log.WithField("txs_count", count).
+		WithField("file", path).
+		Debug("mempool journal written")
+
+	return nil
+}
+
+// readJournal decodes the txs stored in the journal file. A missing file is
+// an empty journal.
+func readJournal(path string) ([]TxDesc, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(data)
+
+	var version uint8
+	if err := encoding.ReadUint8(buf, &version); err != nil {
+		return nil, err
+	}
+
+	if version != journalVersion {
+		return nil, fmt.Errorf("unsupported mempool journal version %d", version)
+	}
+
+	txs := make([]TxDesc, 0)
+
+	for buf.Len() > 0 {
+		var received uint64
+		if err := encoding.ReadUint64LE(buf, &received); err != nil {
+			return txs, err
+		}
+
+		var kind uint8
+		if err := encoding.ReadUint8(buf, &kind); err != nil {
+			return txs, err
+		}
+
+		addr := make([]byte, 0)
+		if err := encoding.ReadVarBytes(buf, &addr); err != nil {
+			return txs, err
+		}
+
+		txBytes := make([]byte, 0)
+		if err := encoding.ReadVarBytes(buf, &txBytes); err != nil {
+			return txs, err
+		}
+
+		tx := transactions.NewTransaction()
+		if err := transactions.Unmarshal(bytes.NewBuffer(txBytes), tx); err != nil {
+			return txs, err
+		}
+
+		txs = append(txs, TxDesc{
+			tx:        tx,
+			received:  time.Unix(0, int64(received)),
+			size:      uint(len(txBytes)),
+			source:    txSource{kind: sourceKind(kind), addr: string(addr)},
+			kadHeight: config.KadcastInitialHeight,
+		})
+	}
+
+	return txs, nil
+}
+
+// loadJournal re-admits the txs journaled by the previous run. Each tx goes
+// through the full verification procedure again, so that the txs already
+// accepted into a block, or not valid anymore, are dropped. Expired txs are
+// dropped without verification.
+//
+// As the verification can take long, loadJournal is run by Run in its own
+// goroutine, and the txs are admitted as the submitted ones are. The txs that
+// could not be verified, as the verifier was not available, are kept and
+// verified again in background until ctx is done.
+func (m *Mempool) loadJournal(ctx context.Context) {
+	path := config.Get().Mempool.JournalFile
+	if path == "" {
+		return
+	}
+
+	txs, err := readJournal(path)
+	if err != nil {
+		// A corrupted journal must not prevent the node from starting. The
+		// txs decoded so far are still re-admitted.
+		log.WithError(err).
+			WithField("file", path).
+			Warn("could not read mempool journal")
+	}
+
+	m.unverified.set(txs)
+	unverified := m.reloadJournaled(ctx)
+
+	log.WithField("journaled", len(txs)).
+		WithField("unverified", unverified).
+		Info("mempool journal loaded")
+
+	if unverified > 0 {
+		go m.retryJournaled(ctx)
+	}
+}
+
+// retryJournaled verifies again the journaled txs not verified yet, until
+// all of them are verified or ctx is done.
+func (m *Mempool) retryJournaled(ctx context.Context) {
+	ticker := time.NewTicker(journalRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if m.reloadJournaled(ctx) == 0 {
+				log.Info("mempool journal reloaded")
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reloadJournaled re-admits the journaled txs not verified yet. It returns
+// the number of txs still not verified, as the verifier is not available or
+// ctx is done.
+func (m *Mempool) reloadJournaled(ctx context.Context) int {
+	ttl := time.Duration(config.Get().Mempool.TxTTL) * time.Second
+	unverified := make([]TxDesc, 0)
+
+	txs := m.unverified.get()
+
+	for i, t := range txs {
+		if ctx.Err() != nil {
+			// keep the remaining txs journaled
+			unverified = append(unverified, txs[i:]...)
+			break
+		}
+
+		if ttl > 0 && time.Since(t.received) > ttl {
+			continue
+		}
+
+		txid, err := m.processTx(t)
+		if errors.Is(err, ErrVerifierFailure) {
+			unverified = append(unverified, t)
+			continue
+		}
+
+		if err != nil {
+			log.WithError(err).
+				WithField("txid", toHex(txid)).
+				WithField("txtype", t.tx.Type()).
+				Debug("could not reload journaled transaction")
+		}
+	}
+
+	m.unverified.set(unverified)
+
+	return len(unverified)
+}
@@ -0,0 +1,207 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package mempool
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestJournal(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(os.TempDir(), "mempool_journal_")
+	assert.NoError(err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	conf := config.Get()
+
+	r := conf
+	r.Mempool.JournalFile = filepath.Join(dir, "mempool.dat")
+	r.Mempool.TxTTL = 3600
+	config.Mock(&r)
+
+	defer config.Mock(&conf)
+
+	v := &transactions.MockProxy{}
+	m := NewMempool(eventbus.New(), rpcbus.New(), v.Prober(), nil)
+
+	received := time.Now().Add(-time.Minute).Round(0)
+
+	// put stores a tx without verification
+	put := func(tx transactions.ContractCall, received time.Time) []byte {
+		txid, err := tx.CalculateHash()
+		assert.NoError(err)
+
+		assert.NoError(m.verified.Put(TxDesc{tx: tx, received: received, verified: time.Now(), size: 100}))
+		return txid
+	}
+
+	valid := make([][]byte, 3)
+	for i := range valid {
+		valid[i] = put(transactions.RandTx(), received)
+	}
+
+	// A tx submitted by a peer
+	fromPeer := transactions.RandTx()
+	fromPeerID, err := fromPeer.CalculateHash()
+	assert.NoError(err)
+
+	peer := txSource{kind: sourcePeer, addr: "peerA"}
+	assert.NoError(m.verified.Put(TxDesc{tx: fromPeer, received: received, verified: time.Now(), size: 100, source: peer}))
+
+	// A tx not valid anymore
+	invalidTx := transactions.RandTx()
+	transactions.Invalidate(invalidTx)
+	invalid := put(invalidTx, received)
+
+	// An expired tx
+	expired := put(transactions.RandTx(), time.Now().Add(-2*time.Hour))
+
+	assert.NoError(m.writeJournal())
+
+	// Reload the journal into a new mempool
+	m = NewMempool(eventbus.New(), rpcbus.New(), v.Prober(), nil)
+	m.loadJournal(context.Background())
+
+	assert.Equal(len(valid)+1, m.verified.Len())
+	assert.False(m.verified.Contains(invalid))
+	assert.False(m.verified.Contains(expired))
+
+	for _, txid := range valid {
+		assert.True(m.verified.Contains(txid))
+	}
+
+	// Reception times and sources are preserved
+	assert.NoError(m.verified.Range(func(k txHash, td TxDesc) error {
+		assert.True(received.Equal(td.received))
+
+		if bytes.Equal(k[:], fromPeerID) {
+			assert.Equal(peer, td.source)
+		}
+
+		return nil
+	}))
+}
+
+// TestJournalVerifierFailure ensures that the journaled txs are kept, and
+// journaled again, while the verifier is not available.
+func TestJournalVerifierFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(os.TempDir(), "mempool_journal_")
+	assert.NoError(err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	conf := config.Get()
+
+	r := conf
+	r.Mempool.JournalFile = filepath.Join(dir, "mempool.dat")
+	r.Mempool.TxTTL = 3600
+	config.Mock(&r)
+
+	defer config.Mock(&conf)
+
+	v := &transactions.MockProxy{}
+	m := NewMempool(eventbus.New(), rpcbus.New(), v.Prober(), nil)
+
+	tx := transactions.RandTx()
+	txid, err := tx.CalculateHash()
+	assert.NoError(err)
+
+	assert.NoError(m.verified.Put(TxDesc{tx: tx, received: time.Now(), verified: time.Now(), size: 100}))
+	assert.NoError(m.writeJournal())
+
+	// Reload the journal while the verifier is not available
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m = NewMempool(eventbus.New(), rpcbus.New(), unavailableVerifier{}, nil)
+	m.loadJournal(ctx)
+
+	assert.Equal(0, m.verified.Len())
+	assert.Len(m.unverified.get(), 1)
+
+	// The unverified tx is not lost when the journal is written again
+	assert.NoError(m.writeJournal())
+
+	txs, err := readJournal(r.Mempool.JournalFile)
+	assert.NoError(err)
+	assert.Len(txs, 1)
+
+	// The tx is admitted once the verifier is available
+	m.verifier = v.Prober()
+
+	assert.Equal(0, m.reloadJournaled(ctx))
+	assert.True(m.verified.Contains(txid))
+}
+
+// TestJournalReloadInBackground ensures that the mempool serves requests
+// while the journaled txs are verified.
+func TestJournalReloadInBackground(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(os.TempDir(), "mempool_journal_")
+	assert.NoError(err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	conf := config.Get()
+
+	r := conf
+	r.Mempool.JournalFile = filepath.Join(dir, "mempool.dat")
+	r.Mempool.TxTTL = 3600
+	config.Mock(&r)
+
+	defer config.Mock(&conf)
+
+	v := &transactions.MockProxy{}
+	m := NewMempool(eventbus.New(), rpcbus.New(), v.Prober(), nil)
+
+	tx := transactions.RandTx()
+	txid, err := tx.CalculateHash()
+	assert.NoError(err)
+
+	assert.NoError(m.verified.Put(TxDesc{tx: tx, received: time.Now(), verified: time.Now(), size: 100}))
+	assert.NoError(m.writeJournal())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The verification of the journaled tx takes a second
+	rpcBus := rpcbus.New()
+	m = NewMempool(eventbus.New(), rpcBus, v.ProberWithParams(time.Second), nil)
+	m.Run(ctx)
+
+	resp, err := rpcBus.Call(topics.GetMempoolTxs, rpcbus.NewRequest(bytes.Buffer{}), 500*time.Millisecond)
+	assert.NoError(err)
+	assert.Empty(resp.([]transactions.ContractCall))
+
+	assert.Eventually(func() bool {
+		return m.verified.Contains(txid)
+	}, 5*time.Second, 100*time.Millisecond)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/reputation"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
@@ -30,6 +31,8 @@ import (
 	"github.com/dusk-network/dusk-protobuf/autogen/go/node"
 	logger "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var log = logger.WithFields(logger.Fields{"prefix": "mempool"})
@@ -43,6 +46,10 @@ var (
 	ErrAlreadyExists = errors.New("already exists")
 	// ErrDoubleSpending transaction uses outputs spent in other mempool txs.
 	ErrDoubleSpending = errors.New("double-spending in mempool")
+	// ErrMempoolFull transaction fee is too low to be kept in a full mempool.
+	ErrMempoolFull = errors.New("mempool is full")
+	// ErrVerifierFailure the verifier could not process the transaction.
+	ErrVerifierFailure = errors.New("verifier failure")
 )
 
 // Mempool is a storage for the chain transactions that are valid according to the
@@ -58,6 +65,9 @@ type Mempool struct {
 	// the collector to listen for new accepted blocks.
 	acceptedBlockChan <-chan block.Block
 
+	// the collector to listen for blocks removed by a chain rollback.
+	rolledBackBlockChan <-chan block.Block
+
 	// used by tx verification procedure.
 	latestBlockTimestamp int64
 
@@ -65,6 +75,28 @@ type Mempool struct {
 
 	// the magic function that knows best what is valid chain Tx.
 	verifier transactions.UnconfirmedTxProber
+
+	// the latest rejected txs, reported by SelectTx.
+	rejections *rejections
+
+	// the journaled txs not verified yet, as the verifier was not available.
+	unverified *unverifiedTxs
+
+	// admission serializes the admission checks and the insertion of the
+	// txs verified concurrently.
+	admission *sync.Mutex
+
+	// reverifiedChan receives the outcome of the re-verification run in the
+	// background, and reverifying is true while it runs.
+	reverifiedChan chan []reverifyResult
+	reverifying    bool
+}
+
+// reverifyResult is the outcome of the re-verification of a tx.
+type reverifyResult struct {
+	txid []byte
+	t    TxDesc
+	err  error
 }
 
 // checkTx is responsible to determine if a tx is valid or not.
@@ -102,15 +134,21 @@ func NewMempool(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus, verifier tra
 	}
 
 	acceptedBlockChan, _ := consensus.InitAcceptedBlockUpdate(eventBus)
+	rolledBackBlockChan, _ := consensus.InitRolledBackBlockUpdate(eventBus)
 
 	m := &Mempool{
 		eventBus:                eventBus,
 		latestBlockTimestamp:    math.MinInt32,
 		acceptedBlockChan:       acceptedBlockChan,
+		rolledBackBlockChan:     rolledBackBlockChan,
 		getMempoolTxsChan:       getMempoolTxsChan,
 		getMempoolTxsBySizeChan: getMempoolTxsBySizeChan,
 		sendTxChan:              sendTxChan,
 		verifier:                verifier,
+		rejections:              newRejections(),
+		admission:               &sync.Mutex{},
+		unverified:              &unverifiedTxs{},
+		reverifiedChan:          make(chan []reverifyResult, 1),
 	}
 
 	// Setting the pool where to cache verified transactions.
@@ -121,6 +159,7 @@ func NewMempool(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus, verifier tra
 
 	if srv != nil {
 		node.RegisterMempoolServer(srv, m)
+		RegisterTxEventsServer(srv, m)
 	}
 
 	return m
@@ -133,10 +172,24 @@ func NewMempool(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus, verifier tra
 // All operations are always executed in a single go-routine so no
 // protection-by-mutex needed.
 func (m *Mempool) Run(ctx context.Context) {
+	// restore the verified txs of the previous run
+	go m.loadJournal(ctx)
+
 	go func() {
+
 		ticker := time.NewTicker(idleTime)
 		defer ticker.Stop()
 
+		// journalChan stays nil if the journal is disabled
+		var journalChan <-chan time.Time
+
+		if config.Get().Mempool.JournalFile != "" {
+			journalTicker := time.NewTicker(journalInterval())
+			defer journalTicker.Stop()
+
+			journalChan = journalTicker.C
+		}
+
 		for {
 			select {
 			// rpcbus methods.
@@ -148,11 +201,18 @@ func (m *Mempool) Run(ctx context.Context) {
 				handleRequest(r, m.processGetMempoolTxsBySizeRequest, "GetMempoolTxsBySize")
 			case b := <-m.acceptedBlockChan:
 				m.onBlock(b)
+			case b := <-m.rolledBackBlockChan:
+				m.onRolledBackBlock(b)
 			case <-ticker.C:
 				m.onIdle()
+			case results := <-m.reverifiedChan:
+				m.onReverified(results)
+			case <-journalChan:
+				m.onJournal()
 			// Mempool terminating.
 			case <-ctx.Done():
 				// m.eventBus.Unsubscribe(topics.Tx, m.txSubscriberID)
+				m.onJournal()
 				return
 			}
 
@@ -161,16 +221,23 @@ func (m *Mempool) Run(ctx context.Context) {
 	}()
 }
 
-// ProcessTx handles a submitted tx from any source (rpcBus or eventBus).
-func (m *Mempool) ProcessTx(srcPeerID string, msg message.Message) ([]bytes.Buffer, error) {
-	maxSizeBytes := config.Get().Mempool.MaxSizeMB * 1000 * 1000
-	if m.verified.Size() > maxSizeBytes {
-		log.WithField("max_size_mb", maxSizeBytes).
-			WithField("current_size", m.verified.Size()).
-			Warn("mempool is full, dropping transaction")
-		return nil, errors.New("mempool is full, dropping transaction")
+// isVerifierFailure returns true if the verification failed because the
+// verifier could not process the tx, rather than because of the tx itself.
+func isVerifierFailure(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return true
 	}
 
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled, codes.ResourceExhausted, codes.Internal:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProcessTx handles a submitted tx from any source (rpcBus or eventBus).
+func (m *Mempool) ProcessTx(srcPeerID string, msg message.Message) ([]bytes.Buffer, error) {
 	var h byte
 	if len(msg.Header()) > 0 {
 		h = msg.Header()[0]
@@ -178,6 +245,10 @@ func (m *Mempool) ProcessTx(srcPeerID string, msg message.Message) ([]bytes.Buff
 
 	t := TxDesc{tx: msg.Payload().(transactions.ContractCall), received: time.Now(), size: uint(len(msg.Id())), kadHeight: h}
 
+	if srcPeerID != "" {
+		t.source = txSource{kind: sourcePeer, addr: srcPeerID}
+	}
+
 	start := time.Now()
 	txid, err := m.processTx(t)
 	elapsed := time.Since(start)
@@ -213,17 +284,78 @@ func (m *Mempool) processTx(t TxDesc) ([]byte, error) {
 
 	if t.tx.Type() == transactions.Distribute {
 		// coinbase tx should be built by block generator only
-		return txid, ErrCoinbaseTxNotAllowed
+		return txid, reputation.Wrap(reputation.InvalidTx, ErrCoinbaseTxNotAllowed)
 	}
 
-	// expect it is not already a verified tx
-	if m.verified.Contains(txid) {
-		return txid, ErrAlreadyExists
+	// expect the tx to be admitted before verifying it, as the verification
+	// is the most expensive check. The checks are repeated by admit.
+	if _, err := m.checkAdmission(txid, t); err != nil {
+		return txid, err
 	}
 
 	// execute tx verification procedure
 	if err := m.checkTx(t.tx); err != nil {
-		return txid, fmt.Errorf("verification err - %v", err)
+		if isVerifierFailure(err) {
+			return txid, fmt.Errorf("verification err - %w: %v", ErrVerifierFailure, err)
+		}
+
+		verr := fmt.Errorf("verification err - %v", err)
+
+		// the tx itself is invalid, which is held against the peer sending it
+		return txid, reputation.Wrap(reputation.InvalidTx, verr)
+	}
+
+	if err := m.admit(txid, t); err != nil {
+		return txid, err
+	}
+
+	// try to (re)propagate transaction in both gossip and kadcast networks
+	m.propagateTx(t, txid)
+
+	return txid, nil
+}
+
+// checkAdmission ensures the tx is not in the mempool yet, its submitter has
+// not reached its quota and it pays enough to replace the txs spending the
+// same nullifiers. It returns the txs to be replaced.
+func (m *Mempool) checkAdmission(txid []byte, t TxDesc) (map[txHash]TxDesc, error) {
+	// expect it is not already a verified tx
+	if m.verified.Contains(txid) {
+		return nil, ErrAlreadyExists
+	}
+
+	// expect a higher fee than the txs spending the same nullifiers
+	replaced, err := m.replacedTxs(t.tx)
+	if err != nil {
+		m.rejections.add(txid, err.Error())
+		return nil, err
+	}
+
+	// expect the submitter has not reached its quota
+	if err := m.checkQuota(t.source, replaced); err != nil {
+		m.rejections.add(txid, err.Error())
+		return nil, err
+	}
+
+	return replaced, nil
+}
+
+// admit stores a verified tx. The admission checks and the insertion happen
+// under the admission lock, so that txs verified concurrently can not both
+// spend the same nullifiers or exceed the quota of their submitter. The
+// replaced txs are removed before the tx is stored, so that they make room
+// for it, and are restored if the tx is not admitted.
+func (m *Mempool) admit(txid []byte, t TxDesc) error {
+	m.admission.Lock()
+	defer m.admission.Unlock()
+
+	replaced, err := m.checkAdmission(txid, t)
+	if err != nil {
+		return err
+	}
+
+	for k := range replaced {
+		m.verified.Delete(k[:])
 	}
 
 	// if consumer's verification passes, mark it as verified
@@ -231,13 +363,35 @@ func (m *Mempool) processTx(t TxDesc) ([]byte, error) {
 
 	// we've got a valid transaction pushed
 	if err := m.verified.Put(t); err != nil {
-		return txid, fmt.Errorf("store err - %v", err)
+		m.restore(replaced)
+		return fmt.Errorf("store err - %v", err)
 	}
 
-	// try to (re)propagate transaction in both gossip and kadcast networks
-	m.propagateTx(t, txid)
+	// make room for the new tx, unless it has the lowest fee
+	if !m.enforceMaxSize(txid) {
+		m.restore(replaced)
+		m.rejections.add(txid, ErrMempoolFull.Error())
+		return ErrMempoolFull
+	}
 
-	return txid, nil
+	for k, r := range replaced {
+		m.onEvicted(append([]byte{}, k[:]...), r, message.EvictedReplaced, nil)
+	}
+
+	m.onAdmitted(txid, t)
+
+	return nil
+}
+
+// restore puts back the txs removed for a tx which has not been admitted.
+func (m *Mempool) restore(txs map[txHash]TxDesc) {
+	for k, t := range txs {
+		if err := m.verified.Put(t); err != nil {
+			log.WithError(err).
+				WithField("txid", toHex(k[:])).
+				Error("could not restore replaced transaction")
+		}
+	}
 }
 
 // propagateTx (re)-propagate tx in gossip or kadcast network but not in both.
@@ -264,6 +418,10 @@ func (m *Mempool) propagateTx(t TxDesc, txid []byte) {
 func (m *Mempool) onBlock(b block.Block) {
 	m.latestBlockTimestamp = b.Header.Timestamp
 	m.removeAccepted(b)
+
+	// onIdle is not triggered as long as the mempool is busy, so that
+	// expired txs are also evicted here
+	m.evictExpired()
 }
 
 // removeAccepted to clean up all txs from the mempool that have been already
@@ -297,15 +455,236 @@ func (m *Mempool) removeAccepted(b block.Block) {
 	l.Info("processing_block_completed")
 }
 
-// TODO: Get rid of stuck/expired transactions
-// TODO: Check periodically the oldest txs if somehow were accepted into the
-// blockchain but were not removed from mempool verified list.
+// onRolledBackBlock re-admits the txs of a block removed from the chain by a
+// rollback. Each tx goes through the full verification procedure again, as
+// it might not be valid anymore against the rolled back chain state.
+func (m *Mempool) onRolledBackBlock(b block.Block) {
+	var readmitted int
+
+	for _, tx := range b.Txs {
+		if tx.Type() == transactions.Distribute {
+			// coinbase txs belong to the removed block only
+			continue
+		}
+
+		buf := new(bytes.Buffer)
+		if err := transactions.Marshal(buf, tx); err != nil {
+			log.WithError(err).Error("could not marshal rolled back tx")
+			continue
+		}
+
+		t := TxDesc{tx: tx, received: time.Now(), size: uint(buf.Len()), kadHeight: config.KadcastInitialHeight}
+
+		txid, err := m.processTx(t)
+		if err != nil {
+			log.WithError(err).
+				WithField("txid", toHex(txid)).
+				WithField("txtype", tx.Type()).
+				Debug("could not re-admit rolled back transaction")
+			continue
+		}
+
+		readmitted++
+	}
+
+	log.WithField("blk_height", b.Header.Height).
+		WithField("blk_txs_count", len(b.Txs)).
+		WithField("readmitted", readmitted).
+		Info("processing_rolled_back_block_completed")
+}
+
+// onIdle evicts the expired txs, and starts the re-verification of the txs
+// against the latest chain state. The latter also covers txs that were
+// accepted into the blockchain, but were not removed from the mempool
+// verified list.
 func (m *Mempool) onIdle() {
+	m.evictExpired()
+	m.reverify()
+
 	log.
 		WithField("mempool_alloc_size_kB", int64(m.verified.Size())/1000).
 		WithField("mempool_txs_count", m.verified.Len()).Info("process_on_idle")
 }
 
+// onJournal journals the verified txs, to be reloaded on restart.
+func (m *Mempool) onJournal() {
+	if err := m.writeJournal(); err != nil {
+		log.WithError(err).Error("could not write mempool journal")
+	}
+}
+
+// enforceMaxSize evicts the lowest-fee txs until the verified pool fits
+// into the configured maximum size. It returns false if the tx with the
+// given txid has been evicted as well. Such tx is not reported as evicted,
+// as it has never been accepted.
+func (m *Mempool) enforceMaxSize(txid []byte) bool {
+	maxSizeBytes := config.Get().Mempool.MaxSizeMB * 1000 * 1000
+	if maxSizeBytes == 0 {
+		return true
+	}
+
+	kept := true
+
+	for _, t := range m.verified.EvictLowestFee(maxSizeBytes) {
+		id, err := t.tx.CalculateHash()
+		if err != nil {
+			log.WithError(err).Error("could not calculate evicted tx hash")
+			continue
+		}
+
+		if bytes.Equal(id, txid) {
+			kept = false
+			continue
+		}
+
+		m.onEvicted(id, t, message.EvictedSize, nil)
+	}
+
+	if !kept {
+		log.WithField("max_size_mb", config.Get().Mempool.MaxSizeMB).
+			WithField("current_size", m.verified.Size()).
+			Warn("mempool is full, dropping transaction")
+	}
+
+	return kept
+}
+
+// evictExpired evicts the txs received earlier than the configured TTL.
+func (m *Mempool) evictExpired() {
+	ttl := config.Get().Mempool.TxTTL
+	if ttl <= 0 {
+		return
+	}
+
+	deadline := time.Now().Add(-time.Duration(ttl) * time.Second)
+
+	for k, t := range m.collect(func(t TxDesc) bool { return t.received.Before(deadline) }) {
+		txid := append([]byte{}, k[:]...)
+
+		m.verified.Delete(txid)
+		m.onEvicted(txid, t, message.EvictedExpired, nil)
+	}
+}
+
+// reverify verifies again, in background, the txs verified earlier than the
+// configured age. The verifier is not called from the Run loop, as each call
+// may last up to the Rusk contract timeout. The outcome is applied by
+// onReverified once received on reverifiedChan.
+func (m *Mempool) reverify() {
+	age := config.Get().Mempool.ReverifyAge
+	if age <= 0 || m.reverifying {
+		return
+	}
+
+	deadline := time.Now().Add(-time.Duration(age) * time.Second)
+
+	stale := m.collect(func(t TxDesc) bool { return t.verified.Before(deadline) })
+	if len(stale) == 0 {
+		return
+	}
+
+	m.reverifying = true
+
+	go func() {
+		results := make([]reverifyResult, 0, len(stale))
+
+		for k, t := range stale {
+			results = append(results, reverifyResult{
+				txid: append([]byte{}, k[:]...),
+				t:    t,
+				err:  m.checkTx(t.tx),
+			})
+		}
+
+		m.reverifiedChan <- results
+	}()
+}
+
+// onReverified evicts the txs found not valid anymore by reverify, and
+// refreshes the verification time of the others. A tx the verifier could not
+// process, e.g. because Rusk is unreachable, is kept and verified again on
+// the next run.
+func (m *Mempool) onReverified(results []reverifyResult) {
+	m.reverifying = false
+
+	m.admission.Lock()
+	defer m.admission.Unlock()
+
+	for _, r := range results {
+		// the tx may have been removed meanwhile
+		if !m.verified.Contains(r.txid) {
+			continue
+		}
+
+		if r.err != nil {
+			if isVerifierFailure(r.err) {
+				log.WithError(r.err).
+					WithField("txid", toHex(r.txid)).
+					Warn("could not re-verify transaction")
+				continue
+			}
+
+			m.verified.Delete(r.txid)
+			m.onEvicted(r.txid, r.t, message.EvictedInvalid, r.err)
+			continue
+		}
+
+		// Re-insert the tx to refresh its verification time
+		r.t.verified = time.Now()
+
+		m.verified.Delete(r.txid)
+
+		if err := m.verified.Put(r.t); err != nil {
+			log.WithError(err).
+				WithField("txid", toHex(r.txid)).
+				Error("could not store re-verified transaction")
+		}
+	}
+}
+
+// collect returns the verified txs matching the filter.
+func (m *Mempool) collect(filter func(t TxDesc) bool) map[txHash]TxDesc {
+	matching := make(map[txHash]TxDesc)
+
+	_ = m.verified.Range(func(k txHash, t TxDesc) error {
+		if filter(t) {
+			matching[k] = t
+		}
+
+		return nil
+	})
+
+	return matching
+}
+
+// onEvicted logs and publishes the eviction of a tx from the verified pool.
+func (m *Mempool) onEvicted(txid []byte, t TxDesc, reason message.EvictionReason, err error) {
+	l := log.WithField("txid", toHex(txid)).
+		WithField("txtype", t.tx.Type()).
+		WithField("txsize", t.size).
+		WithField("reason", reason).
+		WithField("age_sec", int64(time.Since(t.received).Seconds()))
+
+	if err != nil {
+		l = l.WithError(err)
+	}
+
+	l.Info("evicted transaction")
+
+	msg := message.New(topics.EvictedTx, message.EvictedTx{TxID: txid, TxType: t.tx.Type(), Reason: reason})
+	errList := m.eventBus.Publish(topics.EvictedTx, msg)
+
+	diagnostics.LogPublishErrors("mempool.go, topics.EvictedTx", errList)
+}
+
+// onAdmitted publishes the admission of a tx into the verified pool.
+func (m *Mempool) onAdmitted(txid []byte, t TxDesc) {
+	msg := message.New(topics.AdmittedTx, message.AdmittedTx{TxID: txid, TxType: t.tx.Type()})
+	errList := m.eventBus.Publish(topics.AdmittedTx, msg)
+
+	diagnostics.LogPublishErrors("mempool.go, topics.AdmittedTx", errList)
+}
+
 func (m *Mempool) newPool() Pool {
 	preallocTxs := config.Get().Mempool.PreallocTxs
 
@@ -395,6 +774,10 @@ func (m Mempool) SelectTx(ctx context.Context, req *node.SelectRequest) (*node.S
 
 		tx := m.verified.Get(hash)
 		if tx == nil {
+			if reason, ok := m.rejections.get(hash); ok {
+				return nil, fmt.Errorf("tx not found, rejected: %s", reason)
+			}
+
 			return nil, errors.New("tx not found")
 		}
 
@@ -481,15 +864,30 @@ func (m Mempool) processGetMempoolTxsBySizeRequest(r rpcbus.Request) (interface{
 }
 
 // processSendMempoolTxRequest utilizes rpcbus to allow submitting a tx to mempool with.
+// Params are either a TxRequest or a transactions.ContractCall.
 func (m Mempool) processSendMempoolTxRequest(r rpcbus.Request) (interface{}, error) {
-	tx := r.Params.(transactions.ContractCall)
+	var req TxRequest
+
+	switch p := r.Params.(type) {
+	case TxRequest:
+		req = p
+	case transactions.ContractCall:
+		req = TxRequest{Tx: p}
+	default:
+		return nil, errors.New("invalid SendMempoolTx request")
+	}
 
 	buf := new(bytes.Buffer)
-	if err := transactions.Marshal(buf, tx); err != nil {
+	if err := transactions.Marshal(buf, req.Tx); err != nil {
 		return nil, err
 	}
 
-	t := TxDesc{tx: tx, received: time.Now(), size: uint(buf.Len()), kadHeight: config.KadcastInitialHeight}
+	t := TxDesc{tx: req.Tx, received: time.Now(), size: uint(buf.Len()), kadHeight: config.KadcastInitialHeight}
+
+	if req.Client != "" {
+		t.source = txSource{kind: sourceRPC, addr: req.Client}
+	}
+
 	return m.processTx(t)
 }
 
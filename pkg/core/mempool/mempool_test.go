@@ -9,8 +9,11 @@ package mempool
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
+	"errors"
 	"math"
 	"os"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -24,7 +27,10 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-protobuf/autogen/go/node"
 	assert "github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestMain(m *testing.M) {
@@ -295,6 +301,384 @@ func TestSendMempoolTx(t *testing.T) {
 	assert.Equal(m.verified.Size(), totalSize)
 }
 
+func TestEvictions(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.Get()
+
+	r := conf
+	r.Mempool.TxTTL = 60
+	r.Mempool.ReverifyAge = 60
+	config.Mock(&r)
+
+	defer config.Mock(&conf)
+
+	bus := eventbus.New()
+	v := &transactions.MockProxy{}
+	m := NewMempool(bus, rpcbus.New(), v.Prober(), nil)
+
+	evictedChan := make(chan message.Message, 10)
+	bus.Subscribe(topics.EvictedTx, eventbus.NewChanListener(evictedChan))
+
+	// put stores a tx received and verified the given durations ago
+	put := func(tx transactions.ContractCall, received, verified time.Duration) []byte {
+		txid, err := tx.CalculateHash()
+		assert.NoError(err)
+
+		td := TxDesc{tx: tx, received: time.Now().Add(-received), verified: time.Now().Add(-verified), size: 100}
+		assert.NoError(m.verified.Put(td))
+		return txid
+	}
+
+	fresh := put(transactions.RandTx(), 0, 0)
+	expired := put(transactions.RandTx(), 2*time.Minute, 2*time.Minute)
+	stale := put(transactions.RandTx(), 0, 2*time.Minute)
+
+	// A tx verified long ago, and not valid anymore
+	tx := transactions.RandTx()
+	invalid := put(tx, 0, 2*time.Minute)
+	transactions.Invalidate(tx)
+
+	m.onIdle()
+
+	// the re-verification runs in background
+	select {
+	case results := <-m.reverifiedChan:
+		m.onReverified(results)
+	case <-time.After(time.Second):
+		t.Fatal("re-verification not completed")
+	}
+
+	assert.True(m.verified.Contains(fresh))
+	assert.True(m.verified.Contains(stale))
+	assert.False(m.verified.Contains(expired))
+	assert.False(m.verified.Contains(invalid))
+	assert.Equal(2, m.verified.Len())
+
+	reasons := make(map[string]message.EvictionReason)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-evictedChan:
+			e := msg.Payload().(message.EvictedTx)
+			reasons[string(e.TxID)] = e.Reason
+		case <-time.After(time.Second):
+			t.Fatal("eviction not published")
+		}
+	}
+
+	assert.Equal(map[string]message.EvictionReason{
+		string(expired): message.EvictedExpired,
+		string(invalid): message.EvictedInvalid,
+	}, reasons)
+}
+
+// unavailableVerifier fails as an unreachable Rusk would.
+type unavailableVerifier struct{}
+
+func (unavailableVerifier) VerifyTransaction(context.Context, transactions.ContractCall) error {
+	return status.Error(codes.Unavailable, "connection refused")
+}
+
+func (unavailableVerifier) CalculateBalance(context.Context, []byte, []transactions.ContractCall) (uint64, error) {
+	return 0, status.Error(codes.Unavailable, "connection refused")
+}
+
+// TestReverifyVerifierFailure ensures that the txs are kept when the
+// verifier can not be reached.
+func TestReverifyVerifierFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.Get()
+
+	r := conf
+	r.Mempool.ReverifyAge = 60
+	config.Mock(&r)
+
+	defer config.Mock(&conf)
+
+	m := NewMempool(eventbus.New(), rpcbus.New(), unavailableVerifier{}, nil)
+
+	tx := transactions.RandTx()
+	txid, err := tx.CalculateHash()
+	assert.NoError(err)
+
+	assert.NoError(m.verified.Put(TxDesc{tx: tx, received: time.Now(), verified: time.Now().Add(-2 * time.Minute), size: 100}))
+
+	m.reverify()
+	assert.True(m.reverifying)
+
+	// a single re-verification runs at a time
+	m.reverify()
+
+	m.onReverified(<-m.reverifiedChan)
+
+	assert.False(m.reverifying)
+	assert.True(m.verified.Contains(txid))
+	assert.Empty(m.reverifiedChan)
+}
+
+func TestReplaceByFee(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.Get()
+
+	r := conf
+	r.Mempool.ReplaceFeeMargin = 10
+	config.Mock(&r)
+
+	defer config.Mock(&conf)
+
+	v := &transactions.MockProxy{}
+	m := NewMempool(eventbus.New(), rpcbus.New(), v.Prober(), nil)
+
+	// withFee returns a tx spending the same nullifiers as spent, if any
+	withFee := func(fee uint64, spent *transactions.Transaction) *transactions.Transaction {
+		tx := transactions.RandTx()
+		tx.Payload.Fee.GasLimit = 1
+		tx.Payload.Fee.GasPrice = fee
+
+		if spent != nil {
+			tx.Payload.Nullifiers = spent.Payload.Nullifiers
+		}
+
+		return tx
+	}
+
+	pending := withFee(1000, nil)
+	_, err := m.ProcessTx("", message.New(topics.Tx, pending))
+	assert.NoError(err)
+
+	// A fee bump lower than the margin is rejected
+	underpriced := withFee(1050, pending)
+	_, err = m.ProcessTx("", message.New(topics.Tx, underpriced))
+	assert.True(errors.Is(err, ErrReplacementUnderpriced))
+
+	id, err := underpriced.CalculateHash()
+	assert.NoError(err)
+
+	_, err = m.SelectTx(context.Background(), &node.SelectRequest{Id: hex.EncodeToString(id)})
+	assert.Error(err)
+	assert.Contains(err.Error(), ErrReplacementUnderpriced.Error())
+
+	// A fee bump matching the margin replaces the pending tx
+	replacement := withFee(1100, pending)
+	_, err = m.ProcessTx("", message.New(topics.Tx, replacement))
+	assert.NoError(err)
+
+	pendingID, err := pending.CalculateHash()
+	assert.NoError(err)
+
+	replacementID, err := replacement.CalculateHash()
+	assert.NoError(err)
+
+	assert.False(m.verified.Contains(pendingID))
+	assert.True(m.verified.Contains(replacementID))
+	assert.Equal(1, m.verified.Len())
+}
+
+// TestReplaceByFeeFullPool ensures that a fee bump in a full mempool takes the
+// room of the replaced tx, instead of evicting other txs.
+func TestReplaceByFeeFullPool(t *testing.T) {
+	assert := assert.New(t)
+
+	v := &transactions.MockProxy{}
+	m := NewMempool(eventbus.New(), rpcbus.New(), v.Prober(), nil)
+
+	withFee := func(fee uint64) *transactions.Transaction {
+		tx := transactions.RandTx()
+		tx.Payload.Fee.GasLimit = 1
+		tx.Payload.Fee.GasPrice = fee
+		return tx
+	}
+
+	// Fill the 1MB mempool with two txs
+	cheap := withFee(10)
+	pending := withFee(1000)
+
+	for _, tx := range []*transactions.Transaction{cheap, pending} {
+		assert.NoError(m.verified.Put(TxDesc{tx: tx, received: time.Now(), verified: time.Now(), size: 500 * 1000}))
+	}
+
+	replacement := withFee(2000)
+	replacement.Payload.Nullifiers = pending.Payload.Nullifiers
+
+	_, err := m.ProcessTx("", message.New(topics.Tx, replacement))
+	assert.NoError(err)
+
+	cheapID, err := cheap.CalculateHash()
+	assert.NoError(err)
+
+	pendingID, err := pending.CalculateHash()
+	assert.NoError(err)
+
+	assert.True(m.verified.Contains(cheapID))
+	assert.False(m.verified.Contains(pendingID))
+	assert.Equal(2, m.verified.Len())
+}
+
+// TestConcurrentReplacements ensures that only one of the txs spending the
+// same nullifiers is admitted, when they are submitted concurrently.
+func TestConcurrentReplacements(t *testing.T) {
+	assert := assert.New(t)
+
+	v := &transactions.MockProxy{}
+	m := NewMempool(eventbus.New(), rpcbus.New(), v.Prober(), nil)
+
+	spent := transactions.RandTx()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		tx := transactions.RandTx()
+		tx.Payload.Fee.GasLimit = 1
+		tx.Payload.Fee.GasPrice = 1000
+		tx.Payload.Nullifiers = spent.Payload.Nullifiers
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, _ = m.ProcessTx("", message.New(topics.Tx, tx))
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(1, m.verified.Len())
+}
+
+func TestTxEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := eventbus.New()
+	v := &transactions.MockProxy{}
+	m := NewMempool(bus, rpcbus.New(), v.Prober(), nil)
+
+	sub := SubscribeTxEvents(bus, 100)
+	defer sub.Unsubscribe()
+
+	// next returns the events of the next message received
+	next := func() []TxEvent {
+		select {
+		case msg := <-sub.C:
+			events, err := ToTxEvents(msg)
+			assert.NoError(err)
+			return events
+		case <-time.After(time.Second):
+			t.Fatal("tx event not published")
+		}
+
+		return nil
+	}
+
+	pending := transactions.RandTx()
+	pending.Payload.Fee.GasLimit = 1
+	pending.Payload.Fee.GasPrice = 1000
+
+	replacement := transactions.RandTx()
+	replacement.Payload.Fee.GasLimit = 1
+	replacement.Payload.Fee.GasPrice = 2000
+	replacement.Payload.Nullifiers = pending.Payload.Nullifiers
+
+	pendingID, err := pending.CalculateHash()
+	assert.NoError(err)
+
+	replacementID, err := replacement.CalculateHash()
+	assert.NoError(err)
+
+	_, err = m.ProcessTx("", message.New(topics.Tx, pending))
+	assert.NoError(err)
+
+	admitted := TxEvent{Kind: TxAdmitted, TxID: hex.EncodeToString(pendingID), TxType: pending.Type()}
+	assert.Equal([]TxEvent{admitted}, next())
+
+	_, err = m.ProcessTx("", message.New(topics.Tx, replacement))
+	assert.NoError(err)
+
+	replaced := TxEvent{Kind: TxReplaced, TxID: hex.EncodeToString(pendingID), TxType: pending.Type(), Reason: message.EvictedReplaced}
+	assert.Equal([]TxEvent{replaced}, next())
+	assert.Equal(TxAdmitted, next()[0].Kind)
+
+	blk := helper.RandomBlock(5, 1)
+	blk.Txs = append(blk.Txs, replacement)
+
+	errList := bus.Publish(topics.AcceptedBlock, message.New(topics.AcceptedBlock, *blk))
+	assert.Empty(errList)
+
+	events := next()
+	assert.Len(events, len(blk.Txs))
+
+	accepted := events[len(events)-1]
+	assert.Equal(TxEvent{Kind: TxAccepted, TxID: hex.EncodeToString(replacementID), TxType: replacement.Type(), Height: 5}, accepted)
+
+	// Filters
+	assert.True(TxFilter{}.Match(accepted))
+	assert.True(TxFilter{TxIDs: []string{strings.ToUpper(accepted.TxID)}}.Match(accepted))
+	assert.False(TxFilter{TxIDs: []string{admitted.TxID}}.Match(accepted))
+	assert.True(TxFilter{Types: []transactions.TxType{transactions.Distribute, replacement.Type()}}.Match(accepted))
+	assert.False(TxFilter{Types: []transactions.TxType{transactions.Distribute}}.Match(accepted))
+	assert.False(TxFilter{Types: []transactions.TxType{replacement.Type()}, TxIDs: []string{admitted.TxID}}.Match(accepted))
+}
+
+func TestAdmissionQuotas(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.Get()
+
+	r := conf
+	r.Mempool.MaxTxsPerPeer = 2
+	r.Mempool.MaxTxsPerClient = 1
+	config.Mock(&r)
+
+	defer config.Mock(&conf)
+
+	rpcBus := rpcbus.New()
+	v := &transactions.MockProxy{}
+	m := NewMempool(eventbus.New(), rpcBus, v.Prober(), nil)
+
+	pending := transactions.RandTx()
+	pending.Payload.Fee.GasLimit = 1
+	pending.Payload.Fee.GasPrice = 1000
+
+	for _, tx := range []*transactions.Transaction{pending, transactions.RandTx()} {
+		_, err := m.ProcessTx("peerA", message.New(topics.Tx, tx))
+		assert.NoError(err)
+	}
+
+	_, err := m.ProcessTx("peerA", message.New(topics.Tx, transactions.RandTx()))
+	assert.True(errors.Is(err, ErrQuotaExceeded))
+
+	// A peer at its quota can still bump the fee of its own tx
+	replacement := transactions.RandTx()
+	replacement.Payload.Fee.GasLimit = 1
+	replacement.Payload.Fee.GasPrice = 2000
+	replacement.Payload.Nullifiers = pending.Payload.Nullifiers
+
+	_, err = m.ProcessTx("peerA", message.New(topics.Tx, replacement))
+	assert.NoError(err)
+	assert.Equal(2, m.verified.Len())
+
+	_, err = m.ProcessTx("peerB", message.New(topics.Tx, transactions.RandTx()))
+	assert.NoError(err)
+
+	// RPC clients have their own quota
+	send := func(client string) error {
+		req := rpcbus.NewRequest(TxRequest{Tx: transactions.RandTx(), Client: client})
+		_, err := m.processSendMempoolTxRequest(req)
+		return err
+	}
+
+	assert.NoError(send("127.0.0.1:1"))
+	assert.True(errors.Is(send("127.0.0.1:1"), ErrQuotaExceeded))
+	assert.NoError(send("127.0.0.1:2"))
+
+	// Txs created by the node itself are not subject to quotas
+	assert.NoError(send(""))
+	assert.NoError(send(""))
+}
+
 func BenchmarkProcessTx_0(b *testing.B) {
 	// Recent result
 	// BenchmarkProcessTx_0-8             50475             33671 ns/op
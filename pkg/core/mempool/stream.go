@@ -0,0 +1,116 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package mempool
+
+import (
+	"context"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/server"
+	"google.golang.org/grpc"
+)
+
+// StreamEventsMethod is the full gRPC method name of the mempool events
+// stream. The stream belongs to the node.Mempool service, but the
+// dusk-protobuf version the node is built with has no such method. Until it
+// is released there, the stream is served by the node.MempoolEvents service,
+// a server.JSONService.
+const StreamEventsMethod = "/node.MempoolEvents/StreamEvents"
+
+// eventsBufSize is the number of messages buffered per stream subscriber.
+const eventsBufSize = 1000
+
+// TxEventsServer is the server API of the node.MempoolEvents service.
+type TxEventsServer interface {
+	// StreamEvents sends the TxEvents matching the filter until the client
+	// cancels the stream.
+	StreamEvents(filter *TxFilter, stream grpc.ServerStream) error
+}
+
+var txEventsService = server.JSONService{
+	Name:        "node.MempoolEvents",
+	HandlerType: (*TxEventsServer)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       streamEventsHandler,
+			ServerStreams: true,
+		},
+	},
+}
+
+func streamEventsHandler(srv interface{}, stream grpc.ServerStream) error {
+	filter := new(TxFilter)
+	if err := stream.RecvMsg(filter); err != nil {
+		return err
+	}
+
+	return srv.(TxEventsServer).StreamEvents(filter, stream)
+}
+
+// RegisterTxEventsServer registers the node.MempoolEvents service.
+func RegisterTxEventsServer(s *grpc.Server, srv TxEventsServer) {
+	txEventsService.Register(s, srv)
+}
+
+// StreamEvents implements TxEventsServer.
+func (m *Mempool) StreamEvents(filter *TxFilter, stream grpc.ServerStream) error {
+	sub := SubscribeTxEvents(m.eventBus, eventsBufSize)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case msg := <-sub.C:
+			events, err := ToTxEvents(msg)
+			if err != nil {
+				log.WithError(err).Warn("could not convert tx event")
+				continue
+			}
+
+			for _, e := range events {
+				if !filter.Match(e) {
+					continue
+				}
+
+				if err := stream.SendMsg(&e); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// TxEventsClient receives the TxEvents of a node.MempoolEvents stream.
+type TxEventsClient struct {
+	stream grpc.ClientStream
+}
+
+// NewTxEventsClient opens a node.MempoolEvents stream with the given filter.
+func NewTxEventsClient(ctx context.Context, cc grpc.ClientConnInterface, filter TxFilter) (*TxEventsClient, error) {
+	stream, err := server.NewJSONStream(ctx, cc, &txEventsService.Streams[0], StreamEventsMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := stream.SendMsg(&filter); err != nil {
+		return nil, err
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	return &TxEventsClient{stream}, nil
+}
+
+// Recv blocks until the next TxEvent is received.
+func (c *TxEventsClient) Recv() (TxEvent, error) {
+	var e TxEvent
+	err := c.stream.RecvMsg(&e)
+	return e, err
+}
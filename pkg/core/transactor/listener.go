@@ -16,6 +16,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/keys"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 
@@ -73,7 +74,7 @@ func (t *Transactor) handleGetTxHistory() (*node.TxHistoryResponse, error) {
 	return resp, nil
 }
 
-func (t *Transactor) handleSendBidTx(req *node.BidRequest) (*node.TransactionResponse, error) {
+func (t *Transactor) handleSendBidTx(req *node.BidRequest, client string) (*node.TransactionResponse, error) {
 	if t.w == nil {
 		return nil, errWalletNotLoaded
 	}
@@ -148,16 +149,15 @@ func (t *Transactor) handleSendBidTx(req *node.BidRequest) (*node.TransactionRes
 		return nil, err
 	}
 
-	hash, err := t.publishTx(tx.Tx)
+	hash, err := t.publishTx(tx.Tx, client)
 	if err != nil {
-		// DB operations should never fail. If for some reason during runtime we
-		// can no longer use the DB, we should panic.
-		// TODO: maybe we can figure out a recovery procedure if this is the case.
+		// The mempool may reject the tx, e.g. if the client quota is reached
 		log.
 			WithError(err).
 			WithField("amount", req.Amount).
 			WithField("locktime", req.Locktime).
-			Panic("handleSendBidTx, failed to create publishTx")
+			Error("handleSendBidTx, failed to create publishTx")
+		return nil, err
 	}
 
 	// create and sign transaction
@@ -169,7 +169,7 @@ func (t *Transactor) handleSendBidTx(req *node.BidRequest) (*node.TransactionRes
 	return &node.TransactionResponse{Hash: hash}, nil
 }
 
-func (t *Transactor) handleSendStakeTx(req *node.StakeRequest) (*node.TransactionResponse, error) {
+func (t *Transactor) handleSendStakeTx(req *node.StakeRequest, client string) (*node.TransactionResponse, error) {
 	if t.w == nil {
 		return nil, errWalletNotLoaded
 	}
@@ -199,7 +199,7 @@ func (t *Transactor) handleSendStakeTx(req *node.StakeRequest) (*node.Transactio
 		return nil, err
 	}
 
-	hash, err := t.publishTx(tx)
+	hash, err := t.publishTx(tx, client)
 	if err != nil {
 		log.
 			WithField("amount", req.Amount).
@@ -216,7 +216,7 @@ func (t *Transactor) handleSendStakeTx(req *node.StakeRequest) (*node.Transactio
 	return &node.TransactionResponse{Hash: hash}, nil
 }
 
-func (t *Transactor) handleSendStandardTx(req *node.TransferRequest) (*node.TransactionResponse, error) {
+func (t *Transactor) handleSendStandardTx(req *node.TransferRequest, client string) (*node.TransactionResponse, error) {
 	if t.w == nil {
 		return nil, errWalletNotLoaded
 	}
@@ -249,7 +249,7 @@ func (t *Transactor) handleSendStandardTx(req *node.TransferRequest) (*node.Tran
 	log.WithField("duration_ms", d).Debug("NewTransfer grpc call")
 
 	// Publish transaction to the mempool processing
-	hash, err := t.publishTx(tx)
+	hash, err := t.publishTx(tx, client)
 	if err != nil {
 		log.
 			WithField("amount", req.Amount).
@@ -293,13 +293,16 @@ func (t *Transactor) handleClearWalletDatabase() (*node.GenericResponse, error)
 	return &node.GenericResponse{Response: "Wallet database deleted."}, nil
 }
 
-func (t *Transactor) publishTx(tx transactions.ContractCall) ([]byte, error) {
+// publishTx submits tx to the mempool on behalf of the RPC client, if any.
+func (t *Transactor) publishTx(tx transactions.ContractCall, client string) ([]byte, error) {
 	hash, err := tx.CalculateHash()
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = t.rb.Call(topics.SendMempoolTx, rpcbus.NewRequest(tx), 5*time.Second)
+	req := mempool.TxRequest{Tx: tx, Client: client}
+
+	_, err = t.rb.Call(topics.SendMempoolTx, rpcbus.NewRequest(req), 5*time.Second)
 	return hash, err
 }
 
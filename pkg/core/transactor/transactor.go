@@ -22,6 +22,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	"github.com/dusk-network/dusk-protobuf/autogen/go/node"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
 )
 
 // Transactor is the implementation of both the Waller and the Transactor GRPC servers.
@@ -166,12 +167,12 @@ func (t *Transactor) CallContract(ctx context.Context, c *node.CallContractReque
 
 // Transfer will create a normal transaction, transferring DUSK.
 func (t *Transactor) Transfer(ctx context.Context, tr *node.TransferRequest) (*node.TransactionResponse, error) {
-	return t.handleSendStandardTx(tr)
+	return t.handleSendStandardTx(tr, rpcClient(ctx))
 }
 
 // Bid will create a bidding transaction.
 func (t *Transactor) Bid(ctx context.Context, c *node.BidRequest) (*node.TransactionResponse, error) {
-	return t.handleSendBidTx(c)
+	return t.handleSendBidTx(c, rpcClient(ctx))
 }
 
 // Stake will create a staking transaction.
@@ -181,7 +182,7 @@ func (t *Transactor) Stake(ctx context.Context, c *node.StakeRequest) (*node.Tra
 		return nil, errors.New("node is not synced")
 	}
 
-	return t.handleSendStakeTx(c)
+	return t.handleSendStakeTx(c, rpcClient(ctx))
 }
 
 // GetAddress returns the address of the loaded wallet.
@@ -193,3 +194,14 @@ func (t *Transactor) GetAddress(ctx context.Context, e *node.EmptyRequest) (*nod
 func (t *Transactor) GetBalance(ctx context.Context, e *node.EmptyRequest) (*node.BalanceResponse, error) {
 	return t.handleBalance()
 }
+
+// rpcClient returns the address of the gRPC client of a request, or an empty
+// string for requests issued by the node itself.
+func rpcClient(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	return p.Addr.String()
+}
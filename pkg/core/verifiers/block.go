@@ -9,15 +9,50 @@ package verifiers
 import (
 	"bytes"
 	"errors"
+	"fmt"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/agreement"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/sortedset"
 	"github.com/dusk-network/dusk-crypto/bls"
 )
 
+// CertificateFailure identifies the part of a block certificate that failed
+// the verification.
+type CertificateFailure string
+
+const (
+	// FailureStep means that the certificate step is not a valid second
+	// reduction step.
+	FailureStep CertificateFailure = "step"
+	// FailureBitSet means that the certificate committee bitset does not
+	// match the voting committee.
+	FailureBitSet CertificateFailure = "bitset"
+	// FailureSignature means that the aggregated BLS signature is malformed
+	// or does not verify against the committee.
+	FailureSignature CertificateFailure = "signature"
+)
+
+// CertificateError is returned by AuditBlockCertificate when a certificate is
+// invalid.
+type CertificateError struct {
+	Failure CertificateFailure
+	Step    uint8
+	Err     error
+}
+
+func (e *CertificateError) Error() string {
+	return fmt.Sprintf("invalid certificate %s at step %d: %v", e.Failure, e.Step, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *CertificateError) Unwrap() error {
+	return e.Err
+}
+
 // CheckBlockCertificate ensures that the block certificate is valid.
 func CheckBlockCertificate(provisioners user.Provisioners, blk block.Block) error {
 	// TODO: this should be set back to 1, once we fix this issue:
@@ -63,6 +98,63 @@ func checkBlockCertificateForStep(batchedSig *bls.Signature, bitSet uint64, roun
 	return header.VerifySignatures(round, step, blockHash, apk, batchedSig)
 }
 
+// AuditBlockCertificate verifies a block certificate like
+// CheckBlockCertificate, and classifies the failure into a *CertificateError.
+// On top of it, certificates with a step lower than 2, or with a bitset
+// selecting no member or members beyond the committee, are reported.
+//
+// These extra checks are not consensus rules, and AuditBlockCertificate must
+// not be used to accept blocks.
+func AuditBlockCertificate(provisioners user.Provisioners, blk block.Block) error {
+	if blk.Header.Height < 2 {
+		return nil
+	}
+
+	// Consensus steps start at 1, and the certificate step is the one of the
+	// second reduction
+	if blk.Header.Certificate.Step < 2 {
+		return &CertificateError{FailureStep, blk.Header.Certificate.Step, errors.New("step is lower than 2")}
+	}
+
+	stepOne := blk.Header.Certificate.Step - 1
+	stepTwo := blk.Header.Certificate.Step
+
+	stepOneBatchedSig, err := bls.UnmarshalSignature(blk.Header.Certificate.StepOneBatchedSig)
+	if err != nil {
+		return &CertificateError{FailureSignature, stepOne, err}
+	}
+
+	stepTwoBatchedSig, err := bls.UnmarshalSignature(blk.Header.Certificate.StepTwoBatchedSig)
+	if err != nil {
+		return &CertificateError{FailureSignature, stepTwo, err}
+	}
+
+	if err := auditBlockCertificateForStep(stepOneBatchedSig, blk.Header.Certificate.StepOneCommittee, blk.Header.Height, stepOne, provisioners, blk.Header.Hash); err != nil {
+		return err
+	}
+
+	return auditBlockCertificateForStep(stepTwoBatchedSig, blk.Header.Certificate.StepTwoCommittee, blk.Header.Height, stepTwo, provisioners, blk.Header.Hash)
+}
+
+func auditBlockCertificateForStep(batchedSig *bls.Signature, bitSet uint64, round uint64, step uint8, provisioners user.Provisioners, blockHash []byte) error {
+	size := committeeSize(provisioners.SubsetSizeAt(round))
+	committee := provisioners.CreateVotingCommittee(round, step, size)
+
+	if bitSet != sortedset.All && len(committee.Set) < 64 && bitSet>>uint(len(committee.Set)) != 0 {
+		return &CertificateError{FailureBitSet, step, fmt.Errorf("bitset %#x exceeds a committee of %d members", bitSet, len(committee.Set))}
+	}
+
+	if len(committee.IntersectCluster(bitSet).Set) == 0 {
+		return &CertificateError{FailureBitSet, step, fmt.Errorf("bitset %#x selects no committee member", bitSet)}
+	}
+
+	if err := checkBlockCertificateForStep(batchedSig, bitSet, round, step, provisioners, blockHash); err != nil {
+		return &CertificateError{FailureSignature, step, err}
+	}
+
+	return nil
+}
+
 func committeeSize(memberAmount int) int {
 	if memberAmount > agreement.MaxCommitteeSize {
 		return agreement.MaxCommitteeSize
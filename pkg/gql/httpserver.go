@@ -36,6 +36,10 @@ const (
 	endpointWS  = "/ws"
 	endpointWSS = "/wss"
 	endpointGQL = "/graphql"
+
+	// mempool events, filtered by the "type" and "txid" query parameters
+	endpointMempoolWS  = "/ws/mempool"
+	endpointMempoolWSS = "/wss/mempool"
 )
 
 // Server defines the HTTP server of the GraphQL service node.
@@ -201,14 +205,36 @@ func (s *Server) EnableNotifications(serverMux *http.ServeMux) error {
 		s.pool.PushConn(conn)
 	}
 
+	mempoolWSHandler := func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadUint32(&s.started) == 0 {
+			return
+		}
+
+		filter, err := notifications.ParseTxFilter(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.WithError(err).Error("Failed to set websocket upgrade")
+			return
+		}
+
+		s.pool.PushMempoolConn(conn, filter)
+	}
+
 	middleware := tollbooth.LimitFuncHandler(s.lmt, wsHandler)
+	mempoolMiddleware := tollbooth.LimitFuncHandler(s.lmt, mempoolWSHandler)
 
-	endpoint := endpointWS
+	endpoint, mempoolEndpoint := endpointWS, endpointMempoolWS
 	if cfg.Get().Gql.EnableTLS {
-		endpoint = endpointWSS
+		endpoint, mempoolEndpoint = endpointWSS, endpointMempoolWSS
 	}
 
 	serverMux.Handle(endpoint, middleware)
+	serverMux.Handle(mempoolEndpoint, mempoolMiddleware)
 
 	return nil
 }
@@ -8,10 +8,13 @@ package notifications
 
 import (
 	"container/list"
+	"encoding/json"
 	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	logger "github.com/sirupsen/logrus"
@@ -23,12 +26,16 @@ const (
 	writeDeadline = 3 * time.Second
 
 	maxTxsPerMsg = 15
+
+	// Number of mempool event messages buffered per broker.
+	txEventsBufSize = 1000
 )
 
 var log = logger.WithField("process", "broker")
 
 // Broker is a pub/sub broker that keeps updated all subscribers (websocket
-// connections) with latest block accepted published by node layer.
+// connections) with latest block accepted published by node layer, or with
+// the mempool events matching their filter.
 //
 // IMPL Notes:
 // Broker is implemented in a non-blocking manner. That means it should not be
@@ -50,6 +57,7 @@ type Broker struct {
 	eventBus          eventbus.Broker
 	acceptedBlockChan chan block.Block
 	acceptedBlockID   uint32
+	txEvents          *mempool.TxEventSubscription
 }
 
 // NewBroker creates a new Broker instance.
@@ -58,6 +66,7 @@ func NewBroker(id uint, eventBus eventbus.Broker, maxClientsCount uint, connChan
 	b.eventBus = eventBus
 	b.ConnectionChan = connChan
 	b.acceptedBlockChan, b.acceptedBlockID = consensus.InitAcceptedBlockUpdate(eventBus)
+	b.txEvents = mempool.SubscribeTxEvents(eventBus, txEventsBufSize)
 	b.clients = list.New()
 	b.maxClientsCount = maxClientsCount
 	b.id = id
@@ -76,6 +85,7 @@ func (b *Broker) Run() {
 
 		// Unsubscribe from all eventBus events.
 		b.eventBus.Unsubscribe(topics.AcceptedBlock, b.acceptedBlockID)
+		b.txEvents.Unsubscribe()
 
 		// Terminate all clients goroutines.
 		for e := b.clients.Front(); e != nil; e = e.Next() {
@@ -101,6 +111,9 @@ func (b *Broker) Run() {
 		// new accepted block from node
 		case blk := <-b.acceptedBlockChan:
 			b.handleBlock(blk)
+		// new mempool event from node
+		case msg := <-b.txEvents.C:
+			b.handleTxEvent(msg)
 		case <-time.After(30 * time.Second):
 			b.handleIdle()
 		}
@@ -126,6 +139,50 @@ func (b *Broker) handleBlock(blk block.Block) {
 	b.broadcastMessage(msg)
 }
 
+// handleTxEvent handles the topics of the mempool tx lifecycle. It sends each
+// resulting event to the clients which filter matches it.
+func (b *Broker) handleTxEvent(msg message.Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("handleTxEvent recovered from err: %v", r)
+		}
+	}()
+
+	b.reap()
+
+	events, err := mempool.ToTxEvents(msg)
+	if err != nil {
+		log.Errorf("tx event err: %v", err)
+		return
+	}
+
+	for _, e := range events {
+		var data []byte
+
+		for el := b.clients.Front(); el != nil; el = el.Next() {
+			c := el.Value.(*wsClient)
+			if c.filter == nil || !c.filter.Match(e) {
+				continue
+			}
+
+			if data == nil {
+				if data, err = json.Marshal(e); err != nil {
+					log.Errorf("encoding err: %v", err)
+					return
+				}
+			}
+
+			// Mempool events can outpace a slow client. They are dropped
+			// rather than blocking the broker.
+			select {
+			case c.msgChan <- data:
+			default:
+				log.WithField("conn_addr", c.id).Warn("mempool event dropped")
+			}
+		}
+	}
+}
+
 // handleConn handles a new websocket conn pushed from webserver layer It stores
 // the conn to list of active clients.
 func (b *Broker) handleConn(conn wsConn) {
@@ -152,6 +209,11 @@ func (b *Broker) handleConn(conn wsConn) {
 		id:      conn.RemoteAddr().String(),
 	}
 
+	if mc, ok := conn.(*mempoolConn); ok {
+		c.conn = mc.wsConn
+		c.filter = &mc.filter
+	}
+
 	_ = b.clients.PushBack(c)
 
 	// Start a writer-goroutine dedicated for websocket conn. All messages to a
@@ -186,6 +248,11 @@ func (b *Broker) broadcastMessage(data string) {
 
 	for e := b.clients.Front(); e != nil; e = e.Next() {
 		c := e.Value.(*wsClient)
+		if c.filter != nil {
+			// mempool events client
+			continue
+		}
+
 		c.msgChan <- []byte(data)
 	}
 }
@@ -8,8 +8,17 @@ package notifications
 
 import (
 	"container/list"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
 	"strconv"
 	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/stretchr/testify/require"
 )
 
 func TestReapClients(t *testing.T) {
@@ -45,3 +54,41 @@ func TestReapClients(t *testing.T) {
 		t.Fatalf("Not all closed")
 	}
 }
+
+func TestMempoolEvents(t *testing.T) {
+	b := Broker{}
+	b.clients = list.New()
+
+	txid := []byte{1, 2, 3}
+
+	byType, err := ParseTxFilter(url.Values{"type": []string{strconv.Itoa(int(transactions.Stake))}})
+	require.NoError(t, err)
+
+	byID, err := ParseTxFilter(url.Values{"txid": []string{"aabbcc"}})
+	require.NoError(t, err)
+
+	_, err = ParseTxFilter(url.Values{"txid": []string{"not hex"}})
+	require.Error(t, err)
+
+	clients := []*wsClient{
+		// block notifications
+		{id: "blocks", msgChan: make(chan []byte, 1)},
+		{id: "type", msgChan: make(chan []byte, 1), filter: &byType},
+		{id: "txid", msgChan: make(chan []byte, 1), filter: &byID},
+	}
+
+	for _, c := range clients {
+		b.clients.PushBack(c)
+	}
+
+	msg := message.New(topics.AdmittedTx, message.AdmittedTx{TxID: txid, TxType: transactions.Stake})
+	b.handleTxEvent(msg)
+
+	require.Empty(t, clients[0].msgChan)
+	require.Empty(t, clients[2].msgChan)
+	require.Len(t, clients[1].msgChan, 1)
+
+	var e mempool.TxEvent
+	require.NoError(t, json.Unmarshal(<-clients[1].msgChan, &e))
+	require.Equal(t, mempool.TxEvent{Kind: mempool.TxAdmitted, TxID: hex.EncodeToString(txid), TxType: transactions.Stake}, e)
+}
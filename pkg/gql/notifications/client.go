@@ -10,9 +10,17 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
 	"github.com/gorilla/websocket"
 )
 
+// mempoolConn is a websocket connection subscribed to the mempool events
+// instead of the accepted blocks.
+type mempoolConn struct {
+	wsConn
+	filter mempool.TxFilter
+}
+
 type wsClient struct {
 	conn wsConn
 	// data to be sent as a websocket.TextMessage frame
@@ -20,6 +28,9 @@ type wsClient struct {
 	msgChan chan []byte
 	id      string
 
+	// filter of the mempool events, nil for the block notifications.
+	filter *mempool.TxFilter
+
 	closed int32
 }
 
@@ -9,8 +9,13 @@ package notifications
 import (
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
 )
 
 // BlockMsg represents the data need by Explorer UI on each new block accepted.
@@ -58,3 +63,29 @@ func MarshalBlockMsg(blk block.Block) (string, error) {
 
 	return string(msg), nil
 }
+
+// ParseTxFilter builds the filter of the mempool events from the query
+// parameters of a websocket request. Both "type" (numeric tx type) and
+// "txid" (hex) can be repeated.
+func ParseTxFilter(query url.Values) (mempool.TxFilter, error) {
+	var f mempool.TxFilter
+
+	for _, t := range query["type"] {
+		v, err := strconv.ParseUint(t, 10, 32)
+		if err != nil {
+			return f, fmt.Errorf("invalid tx type %q", t)
+		}
+
+		f.Types = append(f.Types, transactions.TxType(v))
+	}
+
+	for _, id := range query["txid"] {
+		if _, err := hex.DecodeString(id); err != nil {
+			return f, fmt.Errorf("invalid txid %q", id)
+		}
+
+		f.TxIDs = append(f.TxIDs, id)
+	}
+
+	return f, nil
+}
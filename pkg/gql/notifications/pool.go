@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/gorilla/websocket"
 )
@@ -65,6 +66,20 @@ func (bp *BrokerPool) PushConn(conn *websocket.Conn) {
 		return
 	}
 
+	bp.push(conn)
+}
+
+// PushMempoolConn pushes a websocket connection, subscribed to the mempool
+// events matching the filter, to the broker pool.
+func (bp *BrokerPool) PushMempoolConn(conn *websocket.Conn, filter mempool.TxFilter) {
+	if conn == nil {
+		return
+	}
+
+	bp.push(&mempoolConn{wsConn: conn, filter: filter})
+}
+
+func (bp *BrokerPool) push(conn wsConn) {
 	bp.lock.Lock()
 	defer bp.lock.Unlock()
 
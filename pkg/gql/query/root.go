@@ -16,9 +16,11 @@ type Root struct {
 	Query *graphql.Object
 }
 
-// NewRoot returns a Root with blocks, transactions and mempool setup.
+// NewRoot returns a Root with blocks, transactions, mempool and sync status
+// setup.
 func NewRoot(rpcBus *rpcbus.RPCBus) *Root {
 	m := mempool{rpcBus: rpcBus}
+	s := syncStatus{rpcBus: rpcBus}
 
 	root := Root{
 		Query: graphql.NewObject(
@@ -28,6 +30,7 @@ func NewRoot(rpcBus *rpcbus.RPCBus) *Root {
 					"blocks":       blocks{}.getQuery(),
 					"transactions": transactions{}.getQuery(),
 					"mempool":      m.getQuery(),
+					"syncstatus":   s.getQuery(),
 				},
 			},
 		),
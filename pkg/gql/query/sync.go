@@ -0,0 +1,85 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package query
+
+import (
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/chain"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/graphql-go/graphql"
+)
+
+// SyncStatus is the graphql object representing the chain sync status.
+var SyncStatus = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "SyncStatus",
+		Fields: graphql.Fields{
+			"state": &graphql.Field{
+				Type: graphql.String,
+			},
+			"height": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"target": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"highestseen": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"progress": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"peer": &graphql.Field{
+				Type: graphql.String,
+			},
+			"peers": &graphql.Field{
+				Type: graphql.NewList(graphql.String),
+			},
+			"blockspersecond": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"eta": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"sequencerbacklog": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"lasterror": &graphql.Field{
+				Type: graphql.String,
+			},
+			"lasterrortime": &graphql.Field{
+				Type: UnixTimestamp,
+			},
+		},
+	},
+)
+
+type syncStatus struct {
+	rpcBus *rpcbus.RPCBus
+}
+
+func (s syncStatus) getQuery() *graphql.Field {
+	return &graphql.Field{
+		Type:    SyncStatus,
+		Resolve: s.resolve,
+	}
+}
+
+func (s syncStatus) resolve(p graphql.ResolveParams) (interface{}, error) {
+	timeoutGetSyncStatus := time.Duration(config.Get().Timeout.TimeoutGetSyncStatus) * time.Second
+
+	resp, err := s.rpcBus.Call(topics.GetSyncStatus, rpcbus.EmptyRequest(), timeoutGetSyncStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	status := resp.(chain.SyncStatus)
+	return status, nil
+}
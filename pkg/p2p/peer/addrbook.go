@@ -0,0 +1,377 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package peer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+)
+
+const (
+	defaultMaxAddresses          = 1000
+	defaultMaxAddressesPerSource = 100
+	defaultMinBackoff            = 30
+	defaultMaxBackoff            = 60 * 60
+
+	// maxAddrFailures is the number of consecutive dial failures after which
+	// an address never successfully dialed is forgotten.
+	maxAddrFailures = 10
+)
+
+// knownAddress is an entry of the address book.
+type knownAddress struct {
+	Addr string `json:"addr"`
+	// Failures is the number of consecutive dial failures.
+	Failures int `json:"failures"`
+	// LastAttempt and LastSuccess are the unix times of the last dial, and
+	// of the last successful one.
+	LastAttempt int64 `json:"lastAttempt"`
+	LastSuccess int64 `json:"lastSuccess"`
+	// Source is the host of the peer which advertised the address. It is
+	// empty for the addresses not learned from a peer.
+	Source string `json:"source,omitempty"`
+}
+
+// evictable returns true if the address can make room for a new one: it
+// has never been reached, or its last dials failed.
+func (a *knownAddress) evictable() bool {
+	return a.LastSuccess == 0 || a.Failures > 0
+}
+
+// addrBook holds the addresses of the full nodes learned from the network.
+// It is persisted as JSON into a file, so that the node can reconnect to
+// the network without the seeders after a restart.
+type addrBook struct {
+	lock  sync.Mutex
+	path  string
+	addrs map[string]*knownAddress
+	dirty bool
+
+	// number of addresses per source
+	sources map[string]int
+
+	maxAddresses          int
+	maxAddressesPerSource int
+	minBackoff            time.Duration
+	maxBackoff            time.Duration
+}
+
+// newAddrBook returns an empty address book, with the configured bounds.
+func newAddrBook() *addrBook {
+	conf := config.Get().Network.AddrBook
+
+	maxAddresses := conf.MaxAddresses
+	if maxAddresses == 0 {
+		maxAddresses = defaultMaxAddresses
+	}
+
+	maxAddressesPerSource := conf.MaxAddressesPerSource
+	if maxAddressesPerSource == 0 {
+		maxAddressesPerSource = defaultMaxAddressesPerSource
+	}
+
+	minBackoff := conf.MinBackoff
+	if minBackoff == 0 {
+		minBackoff = defaultMinBackoff
+	}
+
+	maxBackoff := conf.MaxBackoff
+	if maxBackoff == 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	return &addrBook{
+		path:                  conf.File,
+		addrs:                 make(map[string]*knownAddress),
+		sources:               make(map[string]int),
+		maxAddresses:          maxAddresses,
+		maxAddressesPerSource: maxAddressesPerSource,
+		minBackoff:            time.Duration(minBackoff) * time.Second,
+		maxBackoff:            time.Duration(maxBackoff) * time.Second,
+	}
+}
+
+// load reads the persisted addresses. A missing file is an empty book.
+func (b *addrBook) load() error {
+	if b.path == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(b.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	addrs := make([]*knownAddress, 0)
+	if err := json.Unmarshal(data, &addrs); err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, a := range addrs {
+		if _, ok := b.addrs[a.Addr]; ok {
+			continue
+		}
+
+		b.addrs[a.Addr] = a
+		b.sources[a.Source]++
+	}
+
+	return nil
+}
+
+// save writes the addresses into the file of the book, if changed since the
+// last save.
+func (b *addrBook) save() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.path == "" || !b.dirty {
+		return nil
+	}
+
+	addrs := make([]*knownAddress, 0, len(b.addrs))
+	for _, a := range b.addrs {
+		addrs = append(addrs, a)
+	}
+
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].Addr < addrs[j].Addr
+	})
+
+	data, err := json.MarshalIndent(addrs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := b.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0o600); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, b.path); err != nil {
+		return err
+	}
+
+	b.dirty = false
+	return nil
+}
+
+// add records a new address, advertised by the peer at source. It returns
+// false if the address is invalid or already known, if the source has
+// reached its quota of addresses, or if the book is full of addresses
+// reached successfully.
+//
+// When the book is full, the address with the most consecutive failures, or
+// else one never reached, is evicted to make room for the new one.
+func (b *addrBook) add(addr, source string) bool {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return false
+	}
+
+	if host, err := hostOf(source); err == nil {
+		source = host
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, ok := b.addrs[addr]; ok {
+		return false
+	}
+
+	if source != "" && b.sources[source] >= b.maxAddressesPerSource {
+		return false
+	}
+
+	if len(b.addrs) >= b.maxAddresses && !b.evict() {
+		return false
+	}
+
+	b.addrs[addr] = &knownAddress{Addr: addr, Source: source}
+	b.sources[source]++
+	b.dirty = true
+	return true
+}
+
+// evict removes the address most likely to be unreachable. It returns false
+// if all the addresses have been reached on their last dial.
+func (b *addrBook) evict() bool {
+	var worst *knownAddress
+
+	for _, a := range b.addrs {
+		if !a.evictable() {
+			continue
+		}
+
+		if worst == nil || a.Failures > worst.Failures ||
+			(a.Failures == worst.Failures && a.LastSuccess < worst.LastSuccess) {
+			worst = a
+		}
+	}
+
+	if worst == nil {
+		return false
+	}
+
+	b.remove(worst)
+	return true
+}
+
+// remove deletes an address from the book.
+func (b *addrBook) remove(a *knownAddress) {
+	delete(b.addrs, a.Addr)
+
+	b.sources[a.Source]--
+	if b.sources[a.Source] <= 0 {
+		delete(b.sources, a.Source)
+	}
+
+	b.dirty = true
+}
+
+// attempted records the outcome of a dial to addr.
+func (b *addrBook) attempted(addr string, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	a, ok := b.addrs[addr]
+	if !ok {
+		return
+	}
+
+	now := time.Now().Unix()
+	a.LastAttempt = now
+	b.dirty = true
+
+	if err == nil {
+		a.Failures = 0
+		a.LastSuccess = now
+		return
+	}
+
+	a.Failures++
+
+	if a.Failures >= maxAddrFailures && a.LastSuccess == 0 {
+		b.remove(a)
+	}
+}
+
+// backoff returns the delay before an address failing since failures
+// consecutive dials can be retried.
+func (b *addrBook) backoff(failures int) time.Duration {
+	if failures == 0 {
+		return 0
+	}
+
+	d := b.minBackoff
+	for i := 1; i < failures && d < b.maxBackoff; i++ {
+		d *= 2
+	}
+
+	if d > b.maxBackoff {
+		d = b.maxBackoff
+	}
+
+	return d
+}
+
+// candidates returns up to n addresses to dial, out of the ones not
+// excluded and not backing off. The addresses with the fewest failures
+// come first, and the subnets not in usedGroups are preferred, so that the
+// outbound connections are spread over distinct networks.
+func (b *addrBook) candidates(n int, exclude func(addr string) bool, usedGroups map[string]bool) []string {
+	now := time.Now()
+
+	b.lock.Lock()
+
+	eligible := make([]*knownAddress, 0, len(b.addrs))
+
+	for _, a := range b.addrs {
+		if now.Before(time.Unix(a.LastAttempt, 0).Add(b.backoff(a.Failures))) {
+			continue
+		}
+
+		eligible = append(eligible, a)
+	}
+
+	b.lock.Unlock()
+
+	rand.Shuffle(len(eligible), func(i, j int) {
+		eligible[i], eligible[j] = eligible[j], eligible[i]
+	})
+
+	sort.SliceStable(eligible, func(i, j int) bool {
+		return eligible[i].Failures < eligible[j].Failures
+	})
+
+	groups := make(map[string]bool, len(usedGroups))
+	for g := range usedGroups {
+		groups[g] = true
+	}
+
+	picked := make(map[string]bool)
+	addrs := make([]string, 0, n)
+
+	// First pass over the new subnets only, second pass over the rest
+	for pass := 0; pass < 2; pass++ {
+		for _, a := range eligible {
+			if len(addrs) == n {
+				return addrs
+			}
+
+			if picked[a.Addr] || exclude(a.Addr) {
+				continue
+			}
+
+			g := addrGroup(a.Addr)
+			if pass == 0 && groups[g] {
+				continue
+			}
+
+			groups[g] = true
+			picked[a.Addr] = true
+			addrs = append(addrs, a.Addr)
+		}
+	}
+
+	return addrs
+}
+
+// addrGroup returns the subnet of an address: the /16 of an IPv4, and the
+// /32 of an IPv6. Hostnames are their own group.
+func addrGroup(addr string) string {
+	host, err := hostOf(addr)
+	if err != nil {
+		return addr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return host
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(16, 32)).String()
+	}
+
+	return ip.Mask(net.CIDRMask(32, 128)).String()
+}
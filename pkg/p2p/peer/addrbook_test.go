@@ -0,0 +1,158 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package peer
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddrBookCandidates(t *testing.T) {
+	assert := require.New(t)
+
+	b := newAddrBook()
+
+	for _, addr := range []string{"10.0.0.1:7000", "10.0.0.2:7000", "10.1.0.1:7000", "10.2.0.1:7000"} {
+		assert.True(b.add(addr, ""))
+	}
+
+	assert.False(b.add("10.0.0.1:7000", ""))
+	assert.False(b.add("not an address", ""))
+
+	none := func(string) bool { return false }
+
+	// Distinct subnets are preferred
+	addrs := b.candidates(2, none, map[string]bool{"10.0.0.0": true})
+	sort.Strings(addrs)
+	assert.Equal([]string{"10.1.0.1:7000", "10.2.0.1:7000"}, addrs)
+
+	// Subnets are reused only when there is no other choice
+	addrs = b.candidates(4, none, nil)
+	assert.Len(addrs, 4)
+	assert.NotEqual(addrGroup(addrs[0]), addrGroup(addrs[1]))
+	assert.NotEqual(addrGroup(addrs[1]), addrGroup(addrs[2]))
+
+	// Excluded addresses are skipped
+	addrs = b.candidates(4, func(addr string) bool { return addr != "10.2.0.1:7000" }, nil)
+	assert.Equal([]string{"10.2.0.1:7000"}, addrs)
+
+	// Failing addresses back off
+	b.attempted("10.2.0.1:7000", errors.New("connection refused"))
+	assert.NotContains(b.candidates(4, none, nil), "10.2.0.1:7000")
+
+	b.addrs["10.2.0.1:7000"].LastAttempt = time.Now().Add(-b.minBackoff).Unix()
+	assert.Contains(b.candidates(4, none, nil), "10.2.0.1:7000")
+
+	// Addresses never dialed successfully are forgotten after repeated failures
+	for i := 0; i < maxAddrFailures; i++ {
+		b.attempted("10.1.0.1:7000", errors.New("connection refused"))
+	}
+
+	assert.NotContains(b.addrs, "10.1.0.1:7000")
+}
+
+func TestAddrBookEviction(t *testing.T) {
+	assert := require.New(t)
+
+	b := newAddrBook()
+	b.maxAddresses = 3
+	b.maxAddressesPerSource = 2
+
+	// A peer can not fill the book on its own
+	assert.True(b.add("10.0.0.1:7000", "10.9.0.1:7000"))
+	assert.True(b.add("10.0.0.2:7000", "10.9.0.1:7001"))
+	assert.False(b.add("10.0.0.3:7000", "10.9.0.1:7000"))
+	assert.True(b.add("10.0.0.3:7000", "10.9.0.2:7000"))
+
+	for _, addr := range []string{"10.0.0.1:7000", "10.0.0.2:7000", "10.0.0.3:7000"} {
+		b.attempted(addr, nil)
+	}
+
+	// The book is full of reachable addresses
+	assert.False(b.add("10.0.0.4:7000", ""))
+
+	// The address with the most failures makes room for a new one
+	b.attempted("10.0.0.2:7000", errors.New("connection refused"))
+	b.attempted("10.0.0.2:7000", errors.New("connection refused"))
+	b.attempted("10.0.0.3:7000", errors.New("connection refused"))
+
+	assert.True(b.add("10.0.0.4:7000", ""))
+	assert.NotContains(b.addrs, "10.0.0.2:7000")
+	assert.Len(b.addrs, 3)
+
+	// The evicted address frees the quota of its source
+	assert.Equal(1, b.sources["10.9.0.1"])
+
+	// An address never reached makes room, while the others are reachable
+	b.attempted("10.0.0.3:7000", nil)
+
+	assert.True(b.add("10.0.0.5:7000", ""))
+	assert.NotContains(b.addrs, "10.0.0.4:7000")
+}
+
+func TestAddrBookBackoff(t *testing.T) {
+	assert := require.New(t)
+
+	b := newAddrBook()
+	b.minBackoff = time.Second
+	b.maxBackoff = 5 * time.Second
+
+	assert.Zero(b.backoff(0))
+	assert.Equal(time.Second, b.backoff(1))
+	assert.Equal(2*time.Second, b.backoff(2))
+	assert.Equal(4*time.Second, b.backoff(3))
+	assert.Equal(5*time.Second, b.backoff(4))
+	assert.Equal(5*time.Second, b.backoff(50))
+}
+
+func TestAddrBookPersistence(t *testing.T) {
+	assert := require.New(t)
+
+	dir, err := ioutil.TempDir("", "addrbook")
+	assert.NoError(err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	b := newAddrBook()
+	b.path = filepath.Join(dir, "addrbook.json")
+
+	assert.True(b.add("10.0.0.1:7000", ""))
+	b.attempted("10.0.0.1:7000", nil)
+	assert.NoError(b.save())
+	assert.False(b.dirty)
+
+	restored := newAddrBook()
+	restored.path = b.path
+	assert.NoError(restored.load())
+
+	assert.Contains(restored.addrs, "10.0.0.1:7000")
+	assert.NotZero(restored.addrs["10.0.0.1:7000"].LastSuccess)
+}
+
+func TestProcessNewAddress(t *testing.T) {
+	assert := require.New(t)
+
+	c := newTestConnector()
+
+	_, err := c.ProcessNewAddress("", message.New(topics.Addr, message.Addr{NetAddr: "10.0.0.1:7000"}))
+	assert.NoError(err)
+
+	// The address is recorded, and the dialer is woken up
+	assert.Contains(c.book.addrs, "10.0.0.1:7000")
+	assert.Len(c.dialNow, 1)
+}
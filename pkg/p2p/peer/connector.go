@@ -10,11 +10,14 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"net"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/reputation"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
@@ -23,12 +26,33 @@ import (
 )
 
 const (
-	defaultDialTimeout    = 5
-	defaultMaxConnections = 50
+	defaultDialTimeout        = 5
+	defaultMaxConnections     = 50
+	defaultMinimumConnections = 5
+	defaultDialInterval       = 10
+	defaultBanDuration        = 24 * 60 * 60
 )
 
 type connectFunc func(context.Context, *Reader, *Writer, chan bytes.Buffer)
 
+var (
+	// ErrPeerNotFound is returned when addressing a peer which is not connected.
+	ErrPeerNotFound = errors.New("peer not connected")
+	// ErrPeerBanned is returned when connecting to a banned peer.
+	ErrPeerBanned = errors.New("peer is banned")
+	// ErrAlreadyConnected is returned when connecting to a connected peer.
+	ErrAlreadyConnected = errors.New("peer already connected")
+)
+
+// registryEntry is the connection and the outgoing message queue of a
+// connected peer.
+type registryEntry struct {
+	conn           *Connection
+	writeQueueChan chan<- bytes.Buffer
+	services       protocol.ServiceFlag
+	inbound        bool
+}
+
 // Connector is responsible for accepting incoming connection requests, and
 // establishing outward connections with desired peers.
 type Connector struct {
@@ -39,7 +63,15 @@ type Connector struct {
 	l net.Listener
 
 	lock     sync.RWMutex
-	registry map[string]struct{}
+	registry map[string]registryEntry
+
+	scores *reputation.Scores
+	bans   *reputation.BanList
+
+	// book holds the addresses dialed to maintain the outbound connections.
+	book    *addrBook
+	dialNow chan struct{}
+	quit    chan struct{}
 
 	services protocol.ServiceFlag
 
@@ -60,17 +92,37 @@ func NewConnector(eb eventbus.Broker, gossip *protocol.Gossip, port string,
 			Panic("could not establish a listener")
 	}
 
+	bans := reputation.NewBanList(config.Get().Network.Reputation.BanList)
+	if err := bans.Load(); err != nil {
+		log.WithField("process", "peer connector").
+			WithError(err).
+			Error("could not load the ban list")
+	}
+
+	book := newAddrBook()
+	if err := book.load(); err != nil {
+		log.WithField("process", "peer connector").
+			WithError(err).
+			Error("could not load the address book")
+	}
+
 	c := &Connector{
 		eventBus:      eb,
 		gossip:        gossip,
 		readerFactory: NewReaderFactory(processor),
 		l:             listener,
-		registry:      make(map[string]struct{}),
+		registry:      make(map[string]registryEntry),
+		scores:        reputation.NewScores(),
+		bans:          bans,
+		book:          book,
+		dialNow:       make(chan struct{}, 1),
+		quit:          make(chan struct{}),
 		services:      services,
 		connectFunc:   connectFunc,
 	}
 
 	processor.Register(topics.Addr, c.ProcessNewAddress)
+	processor.penalize = c.Penalize
 
 	go func(c *Connector) {
 		for {
@@ -86,40 +138,164 @@ func NewConnector(eb eventbus.Broker, gossip *protocol.Gossip, port string,
 		}
 	}(c)
 
+	// Only the full nodes maintain outbound connections to the network
+	if services == protocol.FullNode {
+		go c.maintainConnections()
+	}
+
 	return c
 }
 
-// Close the listener.
+// Close the listener, and stop dialing the known addresses.
 func (c *Connector) Close() error {
+	close(c.quit)
 	return c.l.Close()
 }
 
 // ProcessNewAddress will handle a new Addr message from the network.
+// The address is recorded into the address book, and dialed if outbound
+// connections are missing.
 // Satisfies the peer.ProcessorFunc interface.
 func (c *Connector) ProcessNewAddress(srcPeerID string, m message.Message) ([]bytes.Buffer, error) {
-	maxConn := config.Get().Network.MaxConnections
-	if maxConn == 0 {
-		maxConn = defaultMaxConnections
+	a := m.Payload().(message.Addr)
+	if !c.book.add(a.NetAddr, srcPeerID) {
+		return nil, nil
 	}
 
-	if c.GetConnectionsCount() > maxConn {
-		return nil, errors.New("max amount of connections reached")
+	select {
+	case c.dialNow <- struct{}{}:
+	default:
 	}
 
-	a := m.Payload().(message.Addr)
-	return nil, c.Connect(a.NetAddr)
+	return nil, nil
 }
 
 // Connect dials a connection with its string, then on succession
 // we pass the connection and the address to the OnConn method.
 func (c *Connector) Connect(addr string) error {
+	if c.IsBanned(addr) {
+		return ErrPeerBanned
+	}
+
+	if c.isConnected(addr) {
+		return ErrAlreadyConnected
+	}
+
 	conn, err := c.Dial(addr)
 	if err != nil {
 		return err
 	}
 
-	c.proposeConnection(conn)
-	return nil
+	return c.proposeConnection(conn)
+}
+
+// maintainConnections dials the addresses of the book whenever the outbound
+// connections to full nodes are below the minimum, until the connector is
+// closed.
+func (c *Connector) maintainConnections() {
+	interval := config.Get().Network.AddrBook.DialInterval
+	if interval == 0 {
+		interval = defaultDialInterval
+	}
+
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.dialNow:
+		case <-c.quit:
+			c.saveAddrBook()
+			return
+		}
+
+		c.dialMissing()
+		c.saveAddrBook()
+	}
+}
+
+// dialMissing dials as many known addresses as outbound connections are
+// missing, preferring the subnets not connected yet.
+func (c *Connector) dialMissing() {
+	min := config.Get().Network.MinimumConnections
+	if min == 0 {
+		min = defaultMinimumConnections
+	}
+
+	outbound, groups := c.outbound()
+	if outbound >= min {
+		return
+	}
+
+	exclude := func(addr string) bool {
+		return c.isConnected(addr) || c.IsBanned(addr)
+	}
+
+	for _, addr := range c.book.candidates(min-outbound, exclude, groups) {
+		err := c.Connect(addr)
+		c.book.attempted(addr, err)
+
+		if err != nil {
+			log.WithField("process", "peer connector").
+				WithField("address", addr).
+				WithError(err).
+				Debugln("could not dial known address")
+		}
+	}
+}
+
+func (c *Connector) saveAddrBook() {
+	if err := c.book.save(); err != nil {
+		log.WithField("process", "peer connector").
+			WithError(err).
+			Warnln("could not save the address book")
+	}
+}
+
+// outbound returns the number of outbound connections to full nodes, and
+// their subnets.
+func (c *Connector) outbound() (int, map[string]bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	count := 0
+	groups := make(map[string]bool)
+
+	for addr, e := range c.registry {
+		if e.inbound || e.services != protocol.FullNode {
+			continue
+		}
+
+		count++
+		groups[addrGroup(addr)] = true
+	}
+
+	return count, groups
+}
+
+// inboundCount returns the number of inbound connections.
+func (c *Connector) inboundCount() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	count := 0
+
+	for _, e := range c.registry {
+		if e.inbound {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (c *Connector) isConnected(addr string) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	_, ok := c.registry[addr]
+	return ok
 }
 
 // Dial dials up a connection, given its address string.
@@ -140,6 +316,29 @@ func (c *Connector) Dial(addr string) (net.Conn, error) {
 }
 
 func (c *Connector) acceptConnection(conn net.Conn) {
+	if c.IsBanned(conn.RemoteAddr().String()) {
+		log.WithField("process", "peer connector").
+			WithField("address", conn.RemoteAddr().String()).
+			Debugln("refusing connection from banned peer")
+
+		_ = conn.Close()
+		return
+	}
+
+	maxConn := config.Get().Network.MaxConnections
+	if maxConn == 0 {
+		maxConn = defaultMaxConnections
+	}
+
+	if c.inboundCount() >= maxConn {
+		log.WithField("process", "peer connector").
+			WithField("address", conn.RemoteAddr().String()).
+			Debugln("refusing connection, max amount of inbound connections reached")
+
+		_ = conn.Close()
+		return
+	}
+
 	writeQueueChan := make(chan bytes.Buffer, 1000)
 	pConn := NewConnection(conn, c.gossip)
 	peerReader := c.readerFactory.SpawnReader(pConn, writeQueueChan)
@@ -156,7 +355,7 @@ func (c *Connector) acceptConnection(conn net.Conn) {
 
 	peerWriter := NewWriter(pConn, c.eventBus)
 
-	c.addPeer(peerReader.Addr())
+	c.addPeer(peerReader.Addr(), pConn, writeQueueChan, true)
 
 	go func() {
 		c.connectFunc(context.Background(), peerReader, peerWriter, writeQueueChan)
@@ -164,7 +363,7 @@ func (c *Connector) acceptConnection(conn net.Conn) {
 	}()
 }
 
-func (c *Connector) proposeConnection(conn net.Conn) {
+func (c *Connector) proposeConnection(conn net.Conn) error {
 	writeQueueChan := make(chan bytes.Buffer, 1000)
 	pConn := NewConnection(conn, c.gossip)
 	peerWriter := NewWriter(pConn, c.eventBus)
@@ -172,7 +371,9 @@ func (c *Connector) proposeConnection(conn net.Conn) {
 	if err := peerWriter.Connect(c.services); err != nil {
 		log.WithField("process", "peer connector").
 			WithError(err).Warnln("problem performing outgoing handshake")
-		return
+
+		_ = conn.Close()
+		return err
 	}
 
 	address := peerWriter.Addr()
@@ -183,19 +384,21 @@ func (c *Connector) proposeConnection(conn net.Conn) {
 
 	peerReader := c.readerFactory.SpawnReader(pConn, writeQueueChan)
 
-	c.addPeer(peerWriter.Addr())
+	c.addPeer(peerWriter.Addr(), pConn, writeQueueChan, false)
 
 	go func() {
 		c.connectFunc(context.Background(), peerReader, peerWriter, writeQueueChan)
 		c.removePeer(peerWriter.Addr())
 	}()
+
+	return nil
 }
 
-func (c *Connector) addPeer(address string) {
+func (c *Connector) addPeer(address string, conn *Connection, writeQueueChan chan<- bytes.Buffer, inbound bool) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	c.registry[address] = struct{}{}
+	c.registry[address] = registryEntry{conn, writeQueueChan, conn.services, inbound}
 }
 
 func (c *Connector) removePeer(address string) {
@@ -224,3 +427,195 @@ func (c *Connector) GetConnectionsCount() int {
 
 	return len(c.registry)
 }
+
+// FullNodes returns the addresses of the connected full nodes.
+func (c *Connector) FullNodes() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	addrs := make([]string, 0, len(c.registry))
+
+	for addr, e := range c.registry {
+		if e.services == protocol.FullNode {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	return addrs
+}
+
+// PeerInfo describes a connected peer.
+type PeerInfo struct {
+	Address  string               `json:"address"`
+	Services protocol.ServiceFlag `json:"services"`
+	// Score is the reputation score of the peer host.
+	Score int `json:"score"`
+	// Dropped is the number of messages dropped for exceeding the rate
+	// limit, by topic.
+	Dropped map[string]uint64 `json:"dropped,omitempty"`
+}
+
+// Peers returns the connected peers, sorted by address.
+func (c *Connector) Peers() []PeerInfo {
+	scores := c.scores.All()
+
+	c.lock.RLock()
+
+	peers := make([]PeerInfo, 0, len(c.registry))
+
+	for addr, e := range c.registry {
+		info := PeerInfo{
+			Address:  addr,
+			Services: e.services,
+			Dropped:  e.conn.limiter.droppedMessages(),
+		}
+
+		if host, err := hostOf(addr); err == nil {
+			info.Score = scores[host]
+		}
+
+		peers = append(peers, info)
+	}
+
+	c.lock.RUnlock()
+
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Address < peers[j].Address
+	})
+
+	return peers
+}
+
+// SendTo puts a marshaled wire message on the outgoing message queue of a
+// connected peer. It does not block if the queue is full.
+func (c *Connector) SendTo(address string, buf bytes.Buffer) error {
+	c.lock.RLock()
+	e, ok := c.registry[address]
+	c.lock.RUnlock()
+
+	if !ok {
+		return ErrPeerNotFound
+	}
+
+	select {
+	case e.writeQueueChan <- buf:
+		return nil
+	default:
+		return errors.New("peer write queue is full")
+	}
+}
+
+// Penalize adds the penalty of the misbehavior to the score of the peer at
+// address. A peer scoring above the configured threshold is banned.
+func (c *Connector) Penalize(address string, m reputation.Misbehavior) {
+	host, err := hostOf(address)
+	if err != nil {
+		return
+	}
+
+	score := c.scores.Add(host, m)
+
+	threshold := config.Get().Network.Reputation.Threshold
+	if threshold <= 0 || score <= threshold {
+		return
+	}
+
+	c.scores.Reset(host)
+
+	if err := c.ban(host, banDuration(), fmt.Sprintf("score %d, last misbehavior: %s", score, m)); err != nil {
+		log.WithField("process", "peer connector").
+			WithField("address", address).
+			WithError(err).
+			Warnln("could not ban peer")
+	}
+}
+
+// ReportInvalidBlock penalizes the peer at address for supplying an invalid
+// block.
+func (c *Connector) ReportInvalidBlock(address string) {
+	c.Penalize(address, reputation.InvalidBlock)
+}
+
+// ban disconnects the peers at host, and refuses any connection from and to
+// it for the duration d.
+func (c *Connector) ban(host string, d time.Duration, reason string) error {
+	if err := c.bans.Add(host, d, reason); err != nil {
+		return err
+	}
+
+	conns := make([]*Connection, 0)
+
+	c.lock.RLock()
+
+	for addr, e := range c.registry {
+		if h, err := hostOf(addr); err == nil && h == host {
+			conns = append(conns, e.conn)
+		}
+	}
+
+	c.lock.RUnlock()
+
+	log.WithField("process", "peer connector").
+		WithField("host", host).
+		WithField("duration", d).
+		WithField("reason", reason).
+		Warnln("peer banned")
+
+	// The connection loops remove the peers from the registry on exit
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+
+	return nil
+}
+
+// unban lifts the ban of the host of address. It returns false if the host
+// is not banned.
+func (c *Connector) unban(address string) (bool, error) {
+	host, err := hostOf(address)
+	if err != nil {
+		return false, err
+	}
+
+	c.scores.Reset(host)
+	return c.bans.Remove(host)
+}
+
+// IsBanned returns true if the host of address is banned.
+func (c *Connector) IsBanned(address string) bool {
+	host, err := hostOf(address)
+	if err != nil {
+		return false
+	}
+
+	return c.bans.IsBanned(host)
+}
+
+// Bans returns the bans in force.
+func (c *Connector) Bans() []reputation.Ban {
+	return c.bans.List()
+}
+
+// Scores returns the current scores of the peers, by host.
+func (c *Connector) Scores() map[string]int {
+	return c.scores.All()
+}
+
+func banDuration() time.Duration {
+	d := config.Get().Network.BanDuration
+	if d == 0 {
+		d = defaultBanDuration
+	}
+
+	return time.Duration(d) * time.Second
+}
+
+// hostOf returns the host of a network address. A bare IP is its own host.
+func hostOf(address string) (string, error) {
+	if ip := net.ParseIP(address); ip != nil {
+		return ip.String(), nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	return host, err
+}
@@ -0,0 +1,118 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package peer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/reputation"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBan(t *testing.T) {
+	assert := require.New(t)
+
+	c := newTestConnector()
+
+	client, srv := net.Pipe()
+
+	defer func() {
+		_ = srv.Close()
+	}()
+
+	c.addPeer("10.0.0.1:7000", NewConnection(client, nil), make(chan bytes.Buffer, 1), false)
+
+	_, err := c.Ban(context.Background(), &BanRequest{Address: "10.0.0.1:45678"})
+	assert.NoError(err)
+
+	// All the ports of the host are banned
+	assert.True(c.IsBanned("10.0.0.1:7000"))
+	assert.False(c.IsBanned("10.0.0.2:7000"))
+
+	assert.Equal(ErrPeerBanned, c.Connect("10.0.0.1:7000"))
+
+	// The connection of the banned peer is closed
+	_, err = srv.Read(make([]byte, 1))
+	assert.Error(err)
+
+	_, err = c.Ban(context.Background(), &BanRequest{Address: "not an address"})
+	assert.Error(err)
+
+	resp, err := c.ListBans(context.Background(), &ListBansRequest{})
+	assert.NoError(err)
+	assert.Len(resp.Bans, 1)
+	assert.Equal("10.0.0.1", resp.Bans[0].Host)
+
+	unbanned, err := c.Unban(context.Background(), &UnbanRequest{Address: "10.0.0.1"})
+	assert.NoError(err)
+	assert.True(unbanned.Unbanned)
+	assert.False(c.IsBanned("10.0.0.1:7000"))
+}
+
+func TestPenalize(t *testing.T) {
+	assert := require.New(t)
+
+	conf := config.Get()
+
+	r := conf
+	r.Network.Reputation.Threshold = 100
+	r.Network.Reputation.IllegalTopic = 50
+	r.Network.Reputation.MalformedMessage = 25
+	config.Mock(&r)
+
+	defer config.Mock(&conf)
+
+	c := newTestConnector()
+
+	processor := NewMessageProcessor(nil)
+	processor.penalize = c.Penalize
+
+	// A voucher node cannot send consensus messages
+	for i := 0; i < 2; i++ {
+		_, err := processor.process("10.0.0.1:7000", message.New(topics.Reduction, bytes.Buffer{}), nil, protocol.VoucherNode)
+		assert.Error(err)
+	}
+
+	assert.Equal(100, c.Scores()["10.0.0.1"])
+	assert.False(c.IsBanned("10.0.0.1:7000"))
+
+	// Going over the threshold with a malformed message bans the peer
+	_, err := processor.Collect("10.0.0.1:7000", []byte{}, nil, protocol.FullNode, nil)
+	assert.Error(err)
+
+	assert.True(c.IsBanned("10.0.0.1:7000"))
+	assert.Empty(c.Scores())
+
+	// The misbehaviors reported by the processing functions are penalized
+	invalid := errors.New("invalid")
+
+	processor.Register(topics.Inv, func(string, message.Message) ([]bytes.Buffer, error) {
+		return nil, reputation.Wrap(reputation.MalformedMessage, invalid)
+	})
+
+	_, err = processor.process("10.0.0.2:7000", message.New(topics.Inv, message.Inv{}), nil, protocol.FullNode)
+	assert.True(errors.Is(err, invalid))
+	assert.Equal(25, c.Scores()["10.0.0.2"])
+}
+
+func newTestConnector() *Connector {
+	return &Connector{
+		registry: make(map[string]registryEntry),
+		scores:   reputation.NewScores(),
+		bans:     reputation.NewBanList(""),
+		book:     newAddrBook(),
+		dialNow:  make(chan struct{}, 1),
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/reputation"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/checksum"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
@@ -42,13 +43,17 @@ type Connection struct {
 	net.Conn
 	gossip   *protocol.Gossip
 	services protocol.ServiceFlag //nolint:structcheck
+
+	// limiter limits the messages read from the peer.
+	limiter *rateLimiter
 }
 
 // NewConnection creates a peer connection struct.
 func NewConnection(conn net.Conn, gossip *protocol.Gossip) *Connection {
 	return &Connection{
-		Conn:   conn,
-		gossip: gossip,
+		Conn:    conn,
+		gossip:  gossip,
+		limiter: newRateLimiter(),
 	}
 }
 
@@ -373,27 +378,44 @@ func (p *Reader) readLoop(ctx context.Context) {
 
 		b, err := p.gossip.ReadMessage(p.Conn)
 		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				p.processor.report(p.Addr(), reputation.Timeout)
+			}
+
 			l.WithError(err).Warnln("error reading message")
 			return
 		}
 
 		message, cs, err := checksum.Extract(b)
 		if err != nil {
+			p.processor.report(p.Addr(), reputation.MalformedMessage)
 			l.WithError(err).Warnln("error reading Extract message")
 			return
 		}
 
 		if !checksum.Verify(message, cs) {
+			p.processor.report(p.Addr(), reputation.MalformedMessage)
 			l.WithError(errors.New("invalid checksum")).Warnln("error reading message")
 			return
 		}
 
+		// Drop the messages over the rate limit of their topic, before
+		// spending any time on decoding them
+		if len(message) > 0 && !p.limiter.allow(topics.Topic(message[0])) {
+			l.WithField("address", p.Addr()).
+				WithField("topic", topics.Topic(message[0]).String()).
+				Trace("rate limit exceeded, message dropped")
+
+			timer.Reset(keepAliveTime)
+			continue
+		}
+
 		go func() {
-			// TODO: error here should be checked in order to decrease reputation
-			// or blacklist spammers
+			// The misbehaving peers are penalized by the processor
 			startTime := time.Now().UnixNano()
 
-			if _, err = p.processor.Collect(p.Addr(), message, p.responseChan, p.services, nil); err != nil {
+			if _, err := p.processor.Collect(p.Addr(), message, p.responseChan, p.services, nil); err != nil {
 				l.WithField("process", "readloop").WithField("cs", hex.EncodeToString(cs)).
 					WithError(err).Error("failed to process message")
 			}
@@ -13,6 +13,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/dupemap"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/reputation"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
@@ -28,6 +29,10 @@ type ProcessorFunc func(srcPeerID string, m message.Message) ([]bytes.Buffer, er
 type MessageProcessor struct {
 	dupeMap    *dupemap.DupeMap
 	processors map[topics.Topic]ProcessorFunc
+
+	// penalize lowers the reputation of the misbehaving peers. It is set by
+	// the Connector.
+	penalize func(srcPeerID string, m reputation.Misbehavior)
 }
 
 // NewMessageProcessor returns an initialized MessageProcessor.
@@ -51,6 +56,7 @@ func (m *MessageProcessor) Collect(srcPeerID string, packet []byte, respChan cha
 
 	msg, err := message.Unmarshal(b)
 	if err != nil {
+		m.report(srcPeerID, reputation.MalformedMessage)
 		return nil, err
 	}
 
@@ -78,11 +84,13 @@ func (m *MessageProcessor) shouldBeCached(t topics.Topic) bool {
 func (m *MessageProcessor) process(srcPeerID string, msg message.Message, respChan chan<- bytes.Buffer, services protocol.ServiceFlag) ([]bytes.Buffer, error) {
 	category := msg.Category()
 	if !canRoute(services, category) {
+		m.report(srcPeerID, reputation.IllegalTopic)
 		return nil, fmt.Errorf("attempted to process an illegal topic %s for node type %v", category, services)
 	}
 
 	if m.shouldBeCached(category) {
 		if !m.dupeMap.HasAnywhere(bytes.NewBuffer(msg.Id())) {
+			m.report(srcPeerID, reputation.DuplicateMessage)
 			return nil, nil
 		}
 	}
@@ -95,6 +103,10 @@ func (m *MessageProcessor) process(srcPeerID string, msg message.Message, respCh
 
 	bufs, err := processFn(srcPeerID, msg)
 	if err != nil {
+		if misbehavior, ok := reputation.Of(err); ok {
+			m.report(srcPeerID, misbehavior)
+		}
+
 		return nil, err
 	}
 
@@ -106,3 +118,11 @@ func (m *MessageProcessor) process(srcPeerID string, msg message.Message, respCh
 
 	return bufs, nil
 }
+
+// report penalizes srcPeerID for the misbehavior, if the reputation of the
+// peers is tracked.
+func (m *MessageProcessor) report(srcPeerID string, misbehavior reputation.Misbehavior) {
+	if m.penalize != nil && srcPeerID != "" {
+		m.penalize(srcPeerID, misbehavior)
+	}
+}
@@ -0,0 +1,160 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package peer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+// limit is the budget of a token bucket: up to burst messages at once,
+// refilled at rate messages per second.
+type limit struct {
+	rate  float64
+	burst float64
+}
+
+func (l limit) enabled() bool {
+	return l.rate > 0
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter limits the messages received from a peer, with a token bucket
+// per topic. The consensus topics, the sync topics and the data topics have
+// separate budgets.
+type rateLimiter struct {
+	consensus limit
+	sync      limit
+	data      limit
+
+	lock    sync.Mutex
+	buckets map[topics.Topic]*tokenBucket
+	dropped map[topics.Topic]uint64
+}
+
+// newRateLimiter returns a rateLimiter with the configured budgets.
+func newRateLimiter() *rateLimiter {
+	conf := config.Get().Network.RateLimit
+
+	r := &rateLimiter{
+		consensus: limit{rate: conf.ConsensusRate, burst: float64(conf.ConsensusBurst)},
+		sync:      limit{rate: conf.SyncRate, burst: float64(conf.SyncBurst)},
+		data:      limit{rate: conf.DataRate, burst: float64(conf.DataBurst)},
+		buckets:   make(map[topics.Topic]*tokenBucket),
+		dropped:   make(map[topics.Topic]uint64),
+	}
+
+	// A bucket holds at least one message
+	if r.consensus.burst < 1 {
+		r.consensus.burst = 1
+	}
+
+	if r.sync.burst < 1 {
+		r.sync.burst = 1
+	}
+
+	if r.data.burst < 1 {
+		r.data.burst = 1
+	}
+
+	return r
+}
+
+// isConsensusTopic returns true if t is a topic of the consensus messages.
+func isConsensusTopic(t topics.Topic) bool {
+	switch t {
+	case topics.Candidate,
+		topics.GetCandidate,
+		topics.Score,
+		topics.Reduction,
+		topics.Agreement:
+		return true
+	default:
+		return false
+	}
+}
+
+// isSyncTopic returns true if t is a topic of the replies solicited by the
+// synchronization, which come in bursts.
+func isSyncTopic(t topics.Topic) bool {
+	switch t {
+	case topics.Block,
+		topics.Headers,
+		topics.Inv:
+		return true
+	default:
+		return false
+	}
+}
+
+// limitOf returns the budget of topic t.
+func (r *rateLimiter) limitOf(t topics.Topic) limit {
+	switch {
+	case isConsensusTopic(t):
+		return r.consensus
+	case isSyncTopic(t):
+		return r.sync
+	default:
+		return r.data
+	}
+}
+
+// allow consumes a token of the bucket of topic t. It returns false, and
+// counts the message as dropped, if the bucket is empty.
+func (r *rateLimiter) allow(t topics.Topic) bool {
+	l := r.limitOf(t)
+
+	if !l.enabled() {
+		return true
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+
+	b, ok := r.buckets[t]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		r.buckets[t] = b
+	}
+
+	b.tokens += l.rate * now.Sub(b.last).Seconds()
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+
+	b.last = now
+
+	if b.tokens < 1 {
+		r.dropped[t]++
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+// droppedMessages returns the number of messages dropped so far, by topic.
+func (r *rateLimiter) droppedMessages() map[string]uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	dropped := make(map[string]uint64, len(r.dropped))
+	for t, n := range r.dropped {
+		dropped[t.String()] = n
+	}
+
+	return dropped
+}
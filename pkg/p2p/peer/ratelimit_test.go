@@ -0,0 +1,82 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package peer
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	assert := require.New(t)
+
+	conf := config.Get()
+
+	r := conf
+	r.Network.RateLimit.ConsensusRate = 10
+	r.Network.RateLimit.ConsensusBurst = 2
+	r.Network.RateLimit.SyncRate = 10
+	r.Network.RateLimit.SyncBurst = 50
+	r.Network.RateLimit.DataRate = 0
+	config.Mock(&r)
+
+	defer config.Mock(&conf)
+
+	l := newRateLimiter()
+
+	// The burst is allowed, then the bucket is empty
+	assert.True(l.allow(topics.Reduction))
+	assert.True(l.allow(topics.Reduction))
+	assert.False(l.allow(topics.Reduction))
+
+	// Each topic has its own bucket
+	assert.True(l.allow(topics.Agreement))
+
+	// Data topics are not limited
+	for i := 0; i < 100; i++ {
+		assert.True(l.allow(topics.Tx))
+	}
+
+	// Sync replies have their own budget
+	for i := 0; i < 50; i++ {
+		assert.True(l.allow(topics.Block))
+	}
+
+	assert.False(l.allow(topics.Block))
+	assert.True(l.allow(topics.Headers))
+
+	// The bucket refills over time
+	l.buckets[topics.Reduction].last = time.Now().Add(-200 * time.Millisecond)
+	assert.True(l.allow(topics.Reduction))
+
+	assert.Equal(map[string]uint64{topics.Reduction.String(): 1, topics.Block.String(): 1}, l.droppedMessages())
+
+	// The dropped messages are exposed per peer
+	c := newTestConnector()
+
+	client, srv := net.Pipe()
+
+	defer func() {
+		_ = client.Close()
+		_ = srv.Close()
+	}()
+
+	conn := NewConnection(client, nil)
+	conn.limiter = l
+
+	c.addPeer("10.0.0.1:7000", conn, make(chan bytes.Buffer, 1), false)
+
+	peers := c.Peers()
+	assert.Len(peers, 1)
+	assert.Equal(uint64(1), peers[0].Dropped[topics.Reduction.String()])
+}
@@ -32,6 +32,8 @@ var routingRegistry = map[protocol.ServiceFlag]map[topics.Topic]struct{}{
 		topics.Challenge:    {},
 		topics.Response:     {},
 		topics.GetAddrs:     {},
+		topics.GetHeaders:   {},
+		topics.Headers:      {},
 	},
 	// Voucher node
 	protocol.VoucherNode: {
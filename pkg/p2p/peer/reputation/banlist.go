@@ -0,0 +1,164 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package reputation
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Ban is a banned host.
+type Ban struct {
+	Host string `json:"host"`
+	// Expiry is the unix time the ban ends at.
+	Expiry int64  `json:"expiry"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// BanList is the set of the banned hosts. It is persisted as JSON into a
+// file, rewritten on each change, so that the bans survive a restart.
+type BanList struct {
+	lock sync.RWMutex
+	path string
+	bans map[string]Ban
+}
+
+// NewBanList returns an empty ban list, persisted into path. With an empty
+// path, the list is kept in memory only.
+func NewBanList(path string) *BanList {
+	return &BanList{path: path, bans: make(map[string]Ban)}
+}
+
+// Load reads the bans persisted into the file of the list. A missing file
+// is an empty list.
+func (b *BanList) Load() error {
+	if b.path == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(b.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	bans := make([]Ban, 0)
+	if err := json.Unmarshal(data, &bans); err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, ban := range bans {
+		b.bans[ban.Host] = ban
+	}
+
+	return nil
+}
+
+// Add bans host for duration d, replacing any previous ban of it.
+func (b *BanList) Add(host string, d time.Duration, reason string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.bans[host] = Ban{
+		Host:   host,
+		Expiry: time.Now().Add(d).Unix(),
+		Reason: reason,
+	}
+
+	return b.save()
+}
+
+// Remove lifts the ban of host. It returns false if host is not banned.
+func (b *BanList) Remove(host string) (bool, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, ok := b.bans[host]; !ok {
+		return false, nil
+	}
+
+	delete(b.bans, host)
+	return true, b.save()
+}
+
+// IsBanned returns true if host is banned.
+func (b *BanList) IsBanned(host string) bool {
+	b.lock.RLock()
+	ban, ok := b.bans[host]
+	b.lock.RUnlock()
+
+	return ok && time.Now().Unix() < ban.Expiry
+}
+
+// List returns the bans in force, sorted by host.
+func (b *BanList) List() []Ban {
+	now := time.Now().Unix()
+
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	bans := make([]Ban, 0, len(b.bans))
+
+	for _, ban := range b.bans {
+		if now < ban.Expiry {
+			bans = append(bans, ban)
+		}
+	}
+
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Host < bans[j].Host
+	})
+
+	return bans
+}
+
+// save prunes the expired bans, and writes the list into its file. It must
+// be called with the lock held.
+func (b *BanList) save() error {
+	now := time.Now().Unix()
+
+	bans := make([]Ban, 0, len(b.bans))
+
+	for host, ban := range b.bans {
+		if now >= ban.Expiry {
+			delete(b.bans, host)
+			continue
+		}
+
+		bans = append(bans, ban)
+	}
+
+	if b.path == "" {
+		return nil
+	}
+
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Host < bans[j].Host
+	})
+
+	data, err := json.MarshalIndent(bans, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := b.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, b.path)
+}
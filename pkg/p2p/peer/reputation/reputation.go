@@ -0,0 +1,195 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package reputation
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+)
+
+// Misbehavior is a behavior of a peer which lowers its reputation.
+type Misbehavior uint8
+
+const (
+	// IllegalTopic is a message of a topic not routed for the services of
+	// the peer.
+	IllegalTopic Misbehavior = iota
+	// MalformedMessage is a message which cannot be decoded.
+	MalformedMessage
+	// InvalidBlock is a block failing the verification.
+	InvalidBlock
+	// InvalidTx is a transaction failing the verification.
+	InvalidTx
+	// DuplicateMessage is a message already received.
+	DuplicateMessage
+	// Timeout is a peer not sending anything within the read timeout.
+	Timeout
+)
+
+var misbehaviorNames = [...]string{
+	IllegalTopic:     "illegal topic",
+	MalformedMessage: "malformed message",
+	InvalidBlock:     "invalid block",
+	InvalidTx:        "invalid tx",
+	DuplicateMessage: "duplicate message",
+	Timeout:          "timeout",
+}
+
+func (m Misbehavior) String() string {
+	if int(m) < len(misbehaviorNames) {
+		return misbehaviorNames[m]
+	}
+
+	return "unknown"
+}
+
+// Penalty returns the configured score penalty of the misbehavior.
+func (m Misbehavior) Penalty() int {
+	conf := cfg.Get().Network.Reputation
+
+	switch m {
+	case IllegalTopic:
+		return conf.IllegalTopic
+	case MalformedMessage:
+		return conf.MalformedMessage
+	case InvalidBlock:
+		return conf.InvalidBlock
+	case InvalidTx:
+		return conf.InvalidTx
+	case DuplicateMessage:
+		return conf.DuplicateMessage
+	case Timeout:
+		return conf.Timeout
+	default:
+		return 0
+	}
+}
+
+// Error is an error caused by a misbehavior of the peer which sent the
+// message being processed.
+type Error struct {
+	Misbehavior Misbehavior
+	Err         error
+}
+
+// Wrap attributes err to the misbehavior m.
+func Wrap(m Misbehavior, err error) error {
+	return &Error{Misbehavior: m, Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Of returns the misbehavior err is attributed to, if any.
+func Of(err error) (Misbehavior, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Misbehavior, true
+	}
+
+	return 0, false
+}
+
+type score struct {
+	value   float64
+	updated time.Time
+}
+
+// rounded returns the score rounded up, so that a peer is forgiven only
+// once its score fully decayed.
+func (sc *score) rounded() int {
+	return int(math.Ceil(sc.value))
+}
+
+// Scores tracks the score of the peers, by host. The higher the score, the
+// worse the reputation. Scores decay linearly over time, at the configured
+// rate per minute.
+type Scores struct {
+	lock   sync.Mutex
+	scores map[string]*score
+}
+
+// NewScores returns an empty Scores.
+func NewScores() *Scores {
+	return &Scores{scores: make(map[string]*score)}
+}
+
+// Add adds the penalty of m to the score of host, and returns the new score.
+func (s *Scores) Add(host string, m Misbehavior) int {
+	penalty := m.Penalty()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	s.decay(now)
+
+	if penalty <= 0 {
+		if sc, ok := s.scores[host]; ok {
+			return sc.rounded()
+		}
+
+		return 0
+	}
+
+	sc, ok := s.scores[host]
+	if !ok {
+		sc = &score{updated: now}
+		s.scores[host] = sc
+	}
+
+	sc.value += float64(penalty)
+	return sc.rounded()
+}
+
+// Reset clears the score of host.
+func (s *Scores) Reset(host string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.scores, host)
+}
+
+// All returns the current scores, by host.
+func (s *Scores) All() map[string]int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.decay(time.Now())
+
+	all := make(map[string]int, len(s.scores))
+	for host, sc := range s.scores {
+		all[host] = sc.rounded()
+	}
+
+	return all
+}
+
+// decay lowers the scores by the time elapsed since their last update, and
+// forgets the hosts back to zero.
+func (s *Scores) decay(now time.Time) {
+	rate := float64(cfg.Get().Network.Reputation.Decay)
+
+	for host, sc := range s.scores {
+		sc.value -= rate * now.Sub(sc.updated).Minutes()
+		sc.updated = now
+
+		if sc.value <= 0 {
+			delete(s.scores, host)
+		}
+	}
+}
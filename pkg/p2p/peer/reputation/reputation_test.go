@@ -0,0 +1,100 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package reputation
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScores(t *testing.T) {
+	assert := require.New(t)
+
+	conf := cfg.Get()
+
+	r := conf
+	r.Network.Reputation.Decay = 10
+	r.Network.Reputation.InvalidBlock = 40
+	r.Network.Reputation.DuplicateMessage = 0
+	cfg.Mock(&r)
+
+	defer cfg.Mock(&conf)
+
+	s := NewScores()
+
+	assert.Equal(40, s.Add("10.0.0.1", InvalidBlock))
+	assert.Equal(80, s.Add("10.0.0.1", InvalidBlock))
+
+	// Misbehaviors without a penalty are ignored
+	assert.Equal(0, s.Add("10.0.0.2", DuplicateMessage))
+	assert.Equal(map[string]int{"10.0.0.1": 80}, s.All())
+
+	// Scores decay over time
+	s.scores["10.0.0.1"].updated = time.Now().Add(-3 * time.Minute)
+	assert.Equal(50, s.All()["10.0.0.1"])
+
+	s.scores["10.0.0.1"].updated = time.Now().Add(-10 * time.Minute)
+	assert.Empty(s.All())
+}
+
+func TestOf(t *testing.T) {
+	assert := require.New(t)
+
+	_, ok := Of(errors.New("executor unreachable"))
+	assert.False(ok)
+
+	m, ok := Of(Wrap(InvalidTx, errors.New("invalid")))
+	assert.True(ok)
+	assert.Equal(InvalidTx, m)
+}
+
+func TestBanList(t *testing.T) {
+	assert := require.New(t)
+
+	dir, err := ioutil.TempDir("", "banlist")
+	assert.NoError(err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	path := filepath.Join(dir, "banlist.json")
+
+	b := NewBanList(path)
+	assert.NoError(b.Load())
+
+	assert.NoError(b.Add("10.0.0.1", time.Hour, "score 120"))
+	assert.NoError(b.Add("10.0.0.2", time.Hour, ""))
+	assert.NoError(b.Add("10.0.0.3", -time.Second, ""))
+
+	assert.True(b.IsBanned("10.0.0.1"))
+	assert.False(b.IsBanned("10.0.0.3"))
+
+	removed, err := b.Remove("10.0.0.2")
+	assert.NoError(err)
+	assert.True(removed)
+
+	removed, err = b.Remove("10.0.0.2")
+	assert.NoError(err)
+	assert.False(removed)
+
+	// The bans in force survive a restart
+	restored := NewBanList(path)
+	assert.NoError(restored.Load())
+
+	bans := restored.List()
+	assert.Len(bans, 1)
+	assert.Equal("10.0.0.1", bans[0].Host)
+	assert.Equal("score 120", bans[0].Reason)
+}
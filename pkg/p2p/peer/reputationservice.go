@@ -0,0 +1,193 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package peer
+
+import (
+	"context"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/reputation"
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/server"
+	"google.golang.org/grpc"
+)
+
+// Full gRPC method names of the node.Reputation service. dusk-protobuf has no
+// peer management service, so that it is a server.JSONService.
+const (
+	ListBansMethod  = "/node.Reputation/ListBans"
+	BanMethod       = "/node.Reputation/Ban"
+	UnbanMethod     = "/node.Reputation/Unban"
+	ListPeersMethod = "/node.Reputation/ListPeers"
+)
+
+// ListBansRequest is the (empty) request of the ListBans call.
+type ListBansRequest struct{}
+
+// ListBansResponse holds the bans in force, and the current scores of the
+// peers, by host.
+type ListBansResponse struct {
+	Bans   []reputation.Ban `json:"bans"`
+	Scores map[string]int   `json:"scores"`
+}
+
+// BanRequest bans the host of Address, which can be a bare IP.
+type BanRequest struct {
+	Address string `json:"address"`
+	// Duration is the ban duration in seconds. If zero, the configured
+	// ban duration applies.
+	Duration int64  `json:"duration,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// BanResponse is the (empty) response of the Ban call.
+type BanResponse struct{}
+
+// UnbanRequest lifts the ban of the host of Address.
+type UnbanRequest struct {
+	Address string `json:"address"`
+}
+
+// UnbanResponse reports whether the host was banned.
+type UnbanResponse struct {
+	Unbanned bool `json:"unbanned"`
+}
+
+// ListPeersRequest is the (empty) request of the ListPeers call.
+type ListPeersRequest struct{}
+
+// ListPeersResponse holds the connected peers.
+type ListPeersResponse struct {
+	Peers []PeerInfo `json:"peers"`
+}
+
+// ReputationServer is the server API of the node.Reputation service.
+type ReputationServer interface {
+	ListBans(context.Context, *ListBansRequest) (*ListBansResponse, error)
+	Ban(context.Context, *BanRequest) (*BanResponse, error)
+	Unban(context.Context, *UnbanRequest) (*UnbanResponse, error)
+	ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error)
+}
+
+var reputationService = server.JSONService{
+	Name:        "node.Reputation",
+	HandlerType: (*ReputationServer)(nil),
+	Methods: []server.JSONMethod{
+		{
+			Name:       "ListBans",
+			NewRequest: func() interface{} { return new(ListBansRequest) },
+			Call: func(srv interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.(ReputationServer).ListBans(ctx, req.(*ListBansRequest))
+			},
+		},
+		{
+			Name:       "Ban",
+			NewRequest: func() interface{} { return new(BanRequest) },
+			Call: func(srv interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.(ReputationServer).Ban(ctx, req.(*BanRequest))
+			},
+		},
+		{
+			Name:       "Unban",
+			NewRequest: func() interface{} { return new(UnbanRequest) },
+			Call: func(srv interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.(ReputationServer).Unban(ctx, req.(*UnbanRequest))
+			},
+		},
+		{
+			Name:       "ListPeers",
+			NewRequest: func() interface{} { return new(ListPeersRequest) },
+			Call: func(srv interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.(ReputationServer).ListPeers(ctx, req.(*ListPeersRequest))
+			},
+		},
+	},
+}
+
+// RegisterReputationServer registers the node.Reputation service.
+func RegisterReputationServer(s *grpc.Server, srv ReputationServer) {
+	reputationService.Register(s, srv)
+}
+
+// ListBans implements ReputationServer.
+func (c *Connector) ListBans(_ context.Context, _ *ListBansRequest) (*ListBansResponse, error) {
+	return &ListBansResponse{Bans: c.Bans(), Scores: c.Scores()}, nil
+}
+
+// Ban implements ReputationServer.
+func (c *Connector) Ban(_ context.Context, req *BanRequest) (*BanResponse, error) {
+	host, err := hostOf(req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	d := banDuration()
+	if req.Duration > 0 {
+		d = time.Duration(req.Duration) * time.Second
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = "banned by the operator"
+	}
+
+	if err := c.ban(host, d, reason); err != nil {
+		return nil, err
+	}
+
+	return &BanResponse{}, nil
+}
+
+// Unban implements ReputationServer.
+func (c *Connector) Unban(_ context.Context, req *UnbanRequest) (*UnbanResponse, error) {
+	unbanned, err := c.unban(req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UnbanResponse{Unbanned: unbanned}, nil
+}
+
+// ListPeers implements ReputationServer.
+func (c *Connector) ListPeers(_ context.Context, _ *ListPeersRequest) (*ListPeersResponse, error) {
+	return &ListPeersResponse{Peers: c.Peers()}, nil
+}
+
+// ListBans calls the node.Reputation service.
+func ListBans(ctx context.Context, cc grpc.ClientConnInterface) (*ListBansResponse, error) {
+	resp := new(ListBansResponse)
+	if err := server.InvokeJSON(ctx, cc, ListBansMethod, &ListBansRequest{}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// BanPeer calls the node.Reputation service.
+func BanPeer(ctx context.Context, cc grpc.ClientConnInterface, req *BanRequest) error {
+	return server.InvokeJSON(ctx, cc, BanMethod, req, &BanResponse{})
+}
+
+// UnbanPeer calls the node.Reputation service. It returns false if the host
+// was not banned.
+func UnbanPeer(ctx context.Context, cc grpc.ClientConnInterface, address string) (bool, error) {
+	resp := new(UnbanResponse)
+	if err := server.InvokeJSON(ctx, cc, UnbanMethod, &UnbanRequest{Address: address}, resp); err != nil {
+		return false, err
+	}
+
+	return resp.Unbanned, nil
+}
+
+// ListPeers calls the node.Reputation service.
+func ListPeers(ctx context.Context, cc grpc.ClientConnInterface) ([]PeerInfo, error) {
+	resp := new(ListPeersResponse)
+	if err := server.InvokeJSON(ctx, cc, ListPeersMethod, &ListPeersRequest{}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Peers, nil
+}
@@ -14,9 +14,11 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	log "github.com/sirupsen/logrus"
 )
 
-// BlockHashBroker is a processing unit which handles GetBlocks messages.
+// BlockHashBroker is a processing unit which handles GetBlocks and GetHeaders
+// messages.
 // It has a database connection, and a channel pointing to the outgoing message queue
 // of the requesting peer.
 type BlockHashBroker struct {
@@ -42,6 +44,29 @@ func (b *BlockHashBroker) AdvertiseMissingBlocks(srcPeerID string, m message.Mes
 		return nil, err
 	}
 
+	// Do not advertise blocks we can not serve anymore. A peer behind the
+	// pruned height could not link our lowest blocks to its tip, so it gets
+	// nothing and has to sync from an archival node.
+	var prunedHeight uint64
+
+	err = b.db.View(func(t database.Transaction) error {
+		prunedHeight, err = t.FetchPrunedHeight()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if height+1 < prunedHeight {
+		log.
+			WithField("height", height).
+			WithField("pruned_height", prunedHeight).
+			WithField("src_addr", srcPeerID).
+			Debug("peer locator is behind the pruned height")
+
+		return nil, nil
+	}
+
 	// Fill an inv message with all block hashes between the locator
 	// and the chain tip.
 	inv := &message.Inv{}
@@ -79,6 +104,59 @@ func (b *BlockHashBroker) AdvertiseMissingBlocks(srcPeerID string, m message.Mes
 	return nil, nil
 }
 
+// ProvideHeaders takes a GetHeaders wire message, finds the requesting peer's
+// height, and returns a Headers message with up to message.MaxHeaders block
+// headers which follow the provided locator. Headers are never pruned.
+func (b *BlockHashBroker) ProvideHeaders(srcPeerID string, m message.Message) ([]bytes.Buffer, error) {
+	msg := m.Payload().(message.GetBlocks)
+
+	height, err := b.fetchLocatorHeight(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := &message.Headers{}
+
+	err = b.db.View(func(t database.Transaction) error {
+		for len(headers.Headers) < message.MaxHeaders {
+			height++
+
+			hash, err := t.FetchBlockHashByHeight(height)
+			if err == database.ErrBlockNotFound {
+				// Tip of the chain reached
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			hdr, err := t.FetchBlockHeader(hash)
+			if err != nil {
+				return err
+			}
+
+			headers.Headers = append(headers.Headers, hdr)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(headers.Headers) == 0 {
+		return nil, nil
+	}
+
+	buf := topics.Headers.ToBuffer()
+	if err := headers.Encode(&buf); err != nil {
+		return nil, err
+	}
+
+	return []bytes.Buffer{buf}, nil
+}
+
 // Determine a peer's height from his locator hash.
 func (b *BlockHashBroker) fetchLocatorHeight(msg message.GetBlocks) (uint64, error) {
 	if len(msg.Locators) == 0 {
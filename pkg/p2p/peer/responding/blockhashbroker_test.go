@@ -54,6 +54,94 @@ func TestAdvertiseBlocks(t *testing.T) {
 	}
 }
 
+// A peer behind the pruned height is not advertised the blocks it could not
+// link to its tip.
+func TestAdvertisePrunedBlocks(t *testing.T) {
+	assert := assert.New(t)
+	_, db := lite.CreateDBConnection()
+
+	defer func() {
+		_ = db.Close()
+	}()
+
+	hashes, blocks := generateBlocks(5)
+	assert.NoError(storeBlocks(db, blocks))
+
+	// The bodies of the blocks below height 3 are pruned
+	blockHashBroker := responding.NewBlockHashBroker(prunedDB{db, 3})
+
+	blksBuf, err := blockHashBroker.AdvertiseMissingBlocks("", createGetBlocks(hashes[0]))
+	assert.NoError(err)
+	assert.Empty(blksBuf)
+
+	// A peer at the height preceding the pruned one is still served
+	blksBuf, err = blockHashBroker.AdvertiseMissingBlocks("", createGetBlocks(hashes[2]))
+	assert.NoError(err)
+
+	inv := &message.Inv{}
+	_, _ = topics.Extract(&blksBuf[0])
+	assert.NoError(inv.Decode(&blksBuf[0]))
+	assert.Len(inv.InvList, 2)
+	assert.Equal(hashes[3], inv.InvList[0].Hash)
+}
+
+// prunedDB reports a pruned height over a database which does not prune.
+type prunedDB struct {
+	database.DB
+	height uint64
+}
+
+func (d prunedDB) View(fn func(database.Transaction) error) error {
+	return d.DB.View(func(t database.Transaction) error {
+		return fn(prunedTx{t, d.height})
+	})
+}
+
+type prunedTx struct {
+	database.Transaction
+	height uint64
+}
+
+func (t prunedTx) FetchPrunedHeight() (uint64, error) {
+	return t.height, nil
+}
+
+// Test the behavior of the block hash broker, upon receiving a GetHeaders message.
+func TestProvideHeaders(t *testing.T) {
+	assert := assert.New(t)
+	_, db := lite.CreateDBConnection()
+
+	defer func() {
+		_ = db.Close()
+	}()
+
+	hashes, blocks := generateBlocks(5)
+	assert.NoError(storeBlocks(db, blocks))
+
+	blockHashBroker := responding.NewBlockHashBroker(db)
+
+	getHeaders := &message.GetBlocks{Locators: [][]byte{hashes[1]}}
+	bufs, err := blockHashBroker.ProvideHeaders("", message.New(topics.GetHeaders, *getHeaders))
+	assert.NoError(err)
+
+	msg, err := message.Unmarshal(&bufs[0])
+	assert.NoError(err)
+	assert.Equal(topics.Headers, msg.Category())
+
+	headers := msg.Payload().(message.Headers).Headers
+	assert.Len(headers, 3)
+
+	for i, hdr := range headers {
+		assert.Equal(hashes[i+2], hdr.Hash)
+	}
+
+	// Nothing to provide past the tip
+	getHeaders = &message.GetBlocks{Locators: [][]byte{hashes[4]}}
+	bufs, err = blockHashBroker.ProvideHeaders("", message.New(topics.GetHeaders, *getHeaders))
+	assert.NoError(err)
+	assert.Empty(bufs)
+}
+
 // Generate a set of random blocks, which follow each other up in the chain.
 func generateBlocks(amount int) ([][]byte, []*block.Block) {
 	var hashes [][]byte
@@ -51,6 +51,13 @@ func (d *DataBroker) MarshalObjects(srcPeerID string, m message.Message) ([]byte
 				b, err = t.FetchBlock(obj.Hash)
 				return err
 			})
+
+			if err == database.ErrBlockPruned {
+				// Block body is not available anymore. Skip it so that the
+				// peer can request it from someone else.
+				continue
+			}
+
 			if err != nil {
 				return nil, err
 			}
@@ -0,0 +1,28 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package message
+
+import (
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message/payload"
+)
+
+// AdmittedTx is published by the mempool on topics.AdmittedTx when a tx is
+// verified and added to the pool. It is never sent over the wire.
+type AdmittedTx struct {
+	TxID   []byte
+	TxType transactions.TxType
+}
+
+// Copy an AdmittedTx.
+// Implements the payload.Safe interface.
+func (a AdmittedTx) Copy() payload.Safe {
+	txid := make([]byte, len(a.TxID))
+	copy(txid, a.TxID)
+
+	return AdmittedTx{TxID: txid, TxType: a.TxType}
+}
@@ -0,0 +1,48 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package message
+
+import (
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message/payload"
+)
+
+// EvictionReason tells why a tx has been evicted from the mempool.
+type EvictionReason string
+
+const (
+	// EvictedSize means that the tx had the lowest fee when the mempool
+	// exceeded its maximum size.
+	EvictedSize EvictionReason = "size"
+	// EvictedExpired means that the tx stayed in the mempool longer than
+	// its time-to-live.
+	EvictedExpired EvictionReason = "expired"
+	// EvictedInvalid means that the tx failed a periodic re-verification
+	// against the latest chain state.
+	EvictedInvalid EvictionReason = "invalid"
+	// EvictedReplaced means that the tx has been replaced by a tx spending
+	// the same nullifiers with a higher fee.
+	EvictedReplaced EvictionReason = "replaced"
+)
+
+// EvictedTx is published by the mempool on topics.EvictedTx when a tx is
+// removed without being accepted into a block. It is never sent over the
+// wire.
+type EvictedTx struct {
+	TxID   []byte
+	TxType transactions.TxType
+	Reason EvictionReason
+}
+
+// Copy an EvictedTx.
+// Implements the payload.Safe interface.
+func (e EvictedTx) Copy() payload.Safe {
+	txid := make([]byte, len(e.TxID))
+	copy(txid, e.TxID)
+
+	return EvictedTx{TxID: txid, TxType: e.TxType, Reason: e.Reason}
+}
@@ -0,0 +1,50 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package message_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecodeHeaders(t *testing.T) {
+	var headers []*block.Header
+
+	for i := 0; i < 5; i++ {
+		headers = append(headers, helper.RandomBlock(uint64(i), 1).Header)
+	}
+
+	msg := &message.Headers{headers}
+
+	buf := topics.Headers.ToBuffer()
+	if err := msg.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := message.Unmarshal(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, *msg, decoded.Payload().(message.Headers))
+}
+
+func TestDecodeTooManyHeaders(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := encoding.WriteVarInt(buf, message.MaxHeaders+1); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Error(t, (&message.Headers{}).Decode(buf))
+}
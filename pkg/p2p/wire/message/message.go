@@ -245,8 +245,10 @@ func Unmarshal(b *bytes.Buffer) (Message, error) {
 	switch topic {
 	case topics.Block:
 		err = UnmarshalBlockMessage(b, msg)
-	case topics.GetBlocks:
+	case topics.GetBlocks, topics.GetHeaders:
 		err = UnmarshalGetBlocksMessage(b, msg)
+	case topics.Headers:
+		err = UnmarshalHeadersMessage(b, msg)
 	case topics.Inv, topics.GetData:
 		err = UnmarshalInvMessage(b, msg)
 	case topics.GetCandidate:
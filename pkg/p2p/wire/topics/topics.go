@@ -91,6 +91,35 @@ const (
 
 	// Kadcast wire point-to-point messaging.
 	KadcastPoint
+
+	// Chain rollback, published for each block removed from the chain.
+	RolledBackBlock
+
+	// Mempool eviction, published for each tx removed from the mempool
+	// without being accepted into a block.
+	EvictedTx
+
+	// Mempool admission, published for each tx added to the verified pool.
+	AdmittedTx
+
+	// Header-first synchronization topics.
+	GetHeaders
+	Headers
+
+	// Chain sync status, requested over the rpcbus.
+	GetSyncStatus
+
+	// Consensus step timeouts, requested over the rpcbus.
+	GetConsensusTimeouts
+
+	// Consensus channels and queues occupation, requested over the rpcbus.
+	GetConsensusQueues
+
+	// Voting committee of a round and step, requested over the rpcbus.
+	GetCommittee
+
+	// Equivocation evidence, requested over the rpcbus.
+	GetEquivocations
 )
 
 type topicBuf struct {
@@ -151,6 +180,17 @@ var Topics = [...]topicBuf{
 	{GetCandidate, *(bytes.NewBuffer([]byte{byte(GetCandidate)})), "getcandidate"},
 	{SyncProgress, *(bytes.NewBuffer([]byte{byte(SyncProgress)})), "syncprogress"},
 	{Kadcast, *(bytes.NewBuffer([]byte{byte(Kadcast)})), "kadcast"},
+	{KadcastPoint, *(bytes.NewBuffer([]byte{byte(KadcastPoint)})), "kadcastpoint"},
+	{RolledBackBlock, *(bytes.NewBuffer([]byte{byte(RolledBackBlock)})), "rolledbackblock"},
+	{EvictedTx, *(bytes.NewBuffer([]byte{byte(EvictedTx)})), "evictedtx"},
+	{AdmittedTx, *(bytes.NewBuffer([]byte{byte(AdmittedTx)})), "admittedtx"},
+	{GetHeaders, *(bytes.NewBuffer([]byte{byte(GetHeaders)})), "getheaders"},
+	{Headers, *(bytes.NewBuffer([]byte{byte(Headers)})), "headers"},
+	{GetSyncStatus, *(bytes.NewBuffer([]byte{byte(GetSyncStatus)})), "getsyncstatus"},
+	{GetConsensusTimeouts, *(bytes.NewBuffer([]byte{byte(GetConsensusTimeouts)})), "getconsensustimeouts"},
+	{GetConsensusQueues, *(bytes.NewBuffer([]byte{byte(GetConsensusQueues)})), "getconsensusqueues"},
+	{GetCommittee, *(bytes.NewBuffer([]byte{byte(GetCommittee)})), "getcommittee"},
+	{GetEquivocations, *(bytes.NewBuffer([]byte{byte(GetEquivocations)})), "getequivocations"},
 }
 
 func checkConsistency(topics []topicBuf) {
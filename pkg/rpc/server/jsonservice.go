@@ -0,0 +1,109 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// JSONMethod is a unary method of a JSONService.
+type JSONMethod struct {
+	Name string
+	// NewRequest returns the empty request the call is decoded into.
+	NewRequest func() interface{}
+	// Call serves the decoded request with the service implementation.
+	Call func(srv interface{}, ctx context.Context, req interface{}) (interface{}, error)
+}
+
+// JSONService is a gRPC service not defined in dusk-protobuf, served with the
+// JSON codec. It spares the hand-written grpc.ServiceDesc and handlers of the
+// generated services.
+//
+// JSONService is a stopgap, not the way to add RPCs. The node services are
+// generated from the dusk-protobuf module pinned in go.mod, so that a new RPC
+// needs a dusk-protobuf release first. Until then, a JSONService serves it,
+// at the cost of a second RPC surface: its clients must select the JSON
+// codec, and the clients generated from dusk-protobuf can not call it. Once
+// the RPC is released in dusk-protobuf, the JSONService is to be removed.
+type JSONService struct {
+	// Name is the full service name, e.g. "node.SyncStatus".
+	Name string
+	// HandlerType is a nil pointer to the server interface, used by gRPC to
+	// check the implementation at registration.
+	HandlerType interface{}
+	Methods     []JSONMethod
+	Streams     []grpc.StreamDesc
+}
+
+// FullMethod returns the full gRPC method name of a method of the service.
+func (s JSONService) FullMethod(method string) string {
+	return "/" + s.Name + "/" + method
+}
+
+// Register registers srv as the implementation of the service.
+func (s JSONService) Register(g *grpc.Server, srv interface{}) {
+	desc := &grpc.ServiceDesc{
+		ServiceName: s.Name,
+		HandlerType: s.HandlerType,
+		Methods:     make([]grpc.MethodDesc, len(s.Methods)),
+		Streams:     s.Streams,
+		Metadata:    s.Name,
+	}
+
+	if desc.Streams == nil {
+		desc.Streams = []grpc.StreamDesc{}
+	}
+
+	for i, m := range s.Methods {
+		desc.Methods[i] = grpc.MethodDesc{
+			MethodName: m.Name,
+			Handler:    s.unaryHandler(m),
+		}
+	}
+
+	g.RegisterService(desc, srv)
+}
+
+// unaryHandler decodes the request of a method, and serves it through the
+// server interceptor, if any.
+func (s JSONService) unaryHandler(m JSONMethod) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	fullMethod := s.FullMethod(m.Name)
+
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := m.NewRequest()
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+
+		if interceptor == nil {
+			return m.Call(srv, ctx, in)
+		}
+
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: fullMethod,
+		}
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return m.Call(srv, ctx, req)
+		}
+
+		return interceptor(ctx, in, info, handler)
+	}
+}
+
+// InvokeJSON calls a unary method of a JSONService.
+func InvokeJSON(ctx context.Context, cc grpc.ClientConnInterface, method string, req, resp interface{}) error {
+	return cc.Invoke(ctx, method, req, resp, grpc.CallContentSubtype(JSONCodecName))
+}
+
+// NewJSONStream opens a stream of a JSONService.
+func NewJSONStream(ctx context.Context, cc grpc.ClientConnInterface, desc *grpc.StreamDesc, method string) (grpc.ClientStream, error) {
+	return cc.NewStream(ctx, desc, method, grpc.CallContentSubtype(JSONCodecName))
+}
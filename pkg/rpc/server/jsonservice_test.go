@@ -0,0 +1,80 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package server_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/server"
+	assert "github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type echoRequest struct {
+	Text string `json:"text"`
+}
+
+type echoServer interface {
+	Echo(context.Context, *echoRequest) (*echoRequest, error)
+}
+
+type echo struct{}
+
+func (echo) Echo(_ context.Context, req *echoRequest) (*echoRequest, error) {
+	return req, nil
+}
+
+func TestJSONService(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := server.JSONService{
+		Name:        "test.Echo",
+		HandlerType: (*echoServer)(nil),
+		Methods: []server.JSONMethod{
+			{
+				Name:       "Echo",
+				NewRequest: func() interface{} { return new(echoRequest) },
+				Call: func(srv interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(echoServer).Echo(ctx, req.(*echoRequest))
+				},
+			},
+		},
+	}
+
+	sock := "/tmp/dusk-grpc-json-test.sock"
+	_ = os.Remove(sock)
+
+	l, err := net.Listen("unix", sock)
+	assert.NoError(err)
+
+	srv := grpc.NewServer()
+	svc.Register(srv, echo{})
+
+	go func() {
+		_ = srv.Serve(l)
+	}()
+
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(sock, grpc.WithInsecure(), grpc.WithContextDialer(getDialer("unix")))
+	assert.NoError(err)
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp := new(echoRequest)
+	assert.NoError(server.InvokeJSON(ctx, conn, svc.FullMethod("Echo"), &echoRequest{Text: "dusk"}, resp))
+	assert.Equal("dusk", resp.Text)
+}